@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"basics/internal/cover"
+	"basics/internal/dialect"
+	"basics/internal/interpreter"
+)
+
+// attachCoverage mesure la couverture du programme ; la fonction retournée
+// écrit en fin de programme le profil (lcov ou go) et le rapport HTML
+// demandés, puis affiche le taux de couverture
+func attachCoverage(interp *interpreter.Interpreter, profile, format, htmlFile, filename, source string, d *dialect.Dialect) func() {
+	switch format {
+	case "lcov", "go":
+	default:
+		fmt.Printf("⚠️ Unknown cover format %s (lcov, go)\n", format)
+		os.Exit(1)
+	}
+
+	coverage := interpreter.NewCoverage()
+	interp.SetCoverage(coverage)
+
+	return func() {
+		stmts := coverage.Statements()
+		if stmts == nil {
+			fmt.Println("⚠️ Program still running: no coverage written")
+			return
+		}
+		report, err := cover.New(filename, source, d, stmts)
+		if err != nil {
+			fmt.Printf("⚠️ Error computing coverage: %v\n", err)
+			return
+		}
+
+		if profile != "" {
+			write := report.WriteLcov
+			if format == "go" {
+				write = report.WriteGo
+			}
+			writeCoverFile(profile, write)
+		}
+		if htmlFile != "" {
+			writeCoverFile(htmlFile, report.WriteHTML)
+		}
+		fmt.Printf("coverage: %.1f%% of statements (%d/%d)\n", report.Percent(), report.Covered, report.Statements)
+	}
+}
+
+// writeCoverFile crée un fichier de couverture
+func writeCoverFile(path string, write func(io.Writer) error) {
+	f, err := os.Create(path)
+	if err == nil {
+		err = write(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		fmt.Printf("⚠️ Error writing %s: %v\n", path, err)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"basics/internal/dap"
+)
+
+// runDap implémente "basics dap" : adaptateur Debug Adapter Protocol sur
+// stdin / stdout. La sortie du programme est transmise à l'IDE par des
+// événements, stdout restant réservé au protocole.
+func runDap(args []string) {
+	fs := flag.NewFlagSet("dap", flag.ExitOnError)
+
+	basicTypeStr := fs.String("basic", "APPLE", "BASIC type: APPLE, C64, AMS")
+	crunched := fs.Bool("crunched", false, "Read programs written without spaces (10FORI=1TO10)")
+	lowercase := fs.Bool("lowercase", false, "Read keywords written in lowercase (print)")
+	fs.Usage = func() {
+		fmt.Println("🆘 Usage: basics dap [options]")
+		fs.PrintDefaults()
+	}
+	logs := addLogFlags(fs)
+	_ = fs.Parse(args)
+	defer logs.init()()
+
+	d := readDialect(*basicTypeStr, *crunched, *lowercase)
+	if err := dap.NewServer(os.Stdin, os.Stdout, d).Serve(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️ %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+
+	"basics/internal/dialect"
+	"basics/internal/interpreter"
+)
+
+// attachDebugger attache à l'interpréteur un débogueur arrêté avant la
+// première instruction, piloté depuis le terminal. Sa console (Console)
+// tourne à côté du programme, exécuté dans les limites comme sans
+// débogueur ; en mode terminal, elle et les INPUT du programme lisent tour
+// à tour l'entrée standard.
+func attachDebugger(interp *interpreter.Interpreter, d *dialect.Dialect) *interpreter.Debugger {
+	dbg := interpreter.NewDebugger(d)
+	dbg.StopOnEntry = true
+	interp.SetDebugger(dbg)
+
+	fmt.Println("🐞 DEBUGGER: type help for the commands")
+	return dbg
+}
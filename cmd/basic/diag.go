@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"basics/internal/diag"
+	"basics/internal/errors"
+	"basics/internal/parser"
+)
+
+// diagOptions règlent l'affichage des erreurs de syntaxe
+type diagOptions struct {
+	format string
+	color  string
+}
+
+// addDiagFlags déclare les options d'affichage des erreurs
+func addDiagFlags(fs *flag.FlagSet) *diagOptions {
+	o := &diagOptions{}
+	fs.StringVar(&o.format, "error-format", "text", "Syntax error format: text (source line and caret), json")
+	fs.StringVar(&o.color, "color", "auto", "Colour the syntax errors: auto, always, never")
+	return o
+}
+
+// check arrête le programme si une option est invalide
+func (o *diagOptions) check() {
+	if o.format != "text" && o.format != "json" {
+		fmt.Printf("⚠️ Unknown error format %q (text, json)\n", o.format)
+		os.Exit(1)
+	}
+	if o.color != "auto" && o.color != "always" && o.color != "never" {
+		fmt.Printf("⚠️ Unknown colour mode %q (auto, always, never)\n", o.color)
+		os.Exit(1)
+	}
+}
+
+// report affiche les erreurs d'analyse d'un programme sur la sortie
+// standard
+func (o *diagOptions) report(filename, source string, prog *parser.Program, errs []*errors.Error) {
+	diags := diag.FromErrors(filename, prog, errs)
+
+	var err error
+	if o.format == "json" {
+		err = diag.WriteJSON(os.Stdout, diags)
+	} else {
+		err = diag.Render(os.Stdout, source, diags, o.colored())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️ %v\n", err)
+	}
+}
+
+// colored indique si les couleurs sont affichées : en mode auto, seulement
+// sur un terminal et si NO_COLOR n'est pas défini
+func (o *diagOptions) colored() bool {
+	switch o.color {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
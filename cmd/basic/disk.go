@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"basics/internal/applesoft"
+	"basics/internal/disk"
+	"basics/internal/parser"
+)
+
+// listCatalog affiche le catalogue d'une image, comme CATALOG
+func listCatalog(path string) {
+	img := openImage(path)
+
+	entries, err := img.Catalog()
+	if err != nil {
+		fmt.Printf("⚠️ Error reading catalog: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nDISK VOLUME %d\n\n", img.Volume())
+	for _, e := range entries {
+		fmt.Println(e.String())
+	}
+	fmt.Printf("\n%d FREE SECTORS\n", img.FreeSectors())
+}
+
+// loadDiskProgram lit un programme Applesoft (type A) dans une image
+func loadDiskProgram(path, name string) []byte {
+	f := readDiskFile(path, name)
+
+	if f.Type.Letter() != "A" {
+		fmt.Printf("⚠️ %s is not an Applesoft program (type %s)\n", f.Name, f.Type.Letter())
+		os.Exit(1)
+	}
+
+	return f.Data
+}
+
+// extractDiskFile copie un fichier de l'image à côté de celle-ci : les
+// programmes et binaires gardent leur type et leur adresse dans le nom
+// (HELLO#fc0801, PIC#062000), les fichiers texte deviennent des .txt.
+func extractDiskFile(path, name string) {
+	f := readDiskFile(path, name)
+
+	var outName string
+	switch f.Type.Letter() {
+	case "A":
+		outName = fmt.Sprintf("%s#fc%04x", f.Name, applesoft.LoadAddress)
+	case "B":
+		outName = fmt.Sprintf("%s#06%04x", f.Name, f.Address)
+	case "T":
+		outName = f.Name + ".txt"
+	default:
+		fmt.Printf("⚠️ Cannot extract %s (type %s)\n", f.Name, f.Type.Letter())
+		os.Exit(1)
+	}
+
+	outFile := filepath.Join(filepath.Dir(path), outName)
+	if err := os.WriteFile(outFile, f.Data, 0o644); err != nil {
+		fmt.Printf("⚠️ Error writing %s: %v\n", outFile, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s → %s\n", f.Name, outFile)
+}
+
+// saveToDisk enregistre le programme dans une image, comme SAVE. La
+// référence est "image.dsk" ou "image.dsk:NOM" ; par défaut, le nom est
+// celui du fichier source. L'image est créée si elle n'existe pas.
+func saveToDisk(ref string, prog *parser.Program, source string) {
+	path, name, ok := disk.SplitPath(ref)
+	if !ok {
+		path = strings.TrimSuffix(ref, ":")
+		name = strings.ToUpper(filepath.Base(changeExt(source, "")))
+	}
+
+	if !disk.IsImage(path) {
+		fmt.Printf("⚠️ %s is not a disk image (.dsk, .do, .po)\n", path)
+		os.Exit(1)
+	}
+
+	img := disk.New()
+	if _, err := os.Stat(path); err == nil {
+		img = openImage(path)
+	}
+
+	data, err := applesoft.Encode(prog)
+	if err == nil {
+		err = img.WriteApplesoft(name, data)
+	}
+	if err == nil {
+		err = img.Save(path)
+	}
+	if err != nil {
+		fmt.Printf("⚠️ Error saving to disk image: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// readDiskFile lit un fichier dans une image
+func readDiskFile(path, name string) *disk.File {
+	img := openImage(path)
+
+	f, err := img.ReadFile(name)
+	if errors.Is(err, disk.ErrFileNotFound) {
+		fmt.Printf("⚠️ FILE NOT FOUND: %s\n", name)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Printf("⚠️ Error reading %s: %v\n", name, err)
+		os.Exit(1)
+	}
+
+	return f
+}
+
+// openImage ouvre une image de disquette DOS 3.3
+func openImage(path string) *disk.Image {
+	img, err := disk.Open(path)
+	if err != nil {
+		fmt.Printf("⚠️ Error opening disk image %s: %v\n", path, err)
+		os.Exit(1)
+	}
+	return img
+}
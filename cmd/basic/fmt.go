@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"basics/internal/applesoft"
+	"basics/internal/dialect"
+	"basics/internal/errors"
+	"basics/internal/lexer"
+	"basics/internal/parser"
+	"basics/internal/token"
+	"basics/internal/unparse"
+)
+
+// runFmt implémente "basics fmt" : réécrit les programmes au format
+// canonique. Le texte produit est vérifié avant d'être écrit.
+func runFmt(args []string) {
+	fs := flag.NewFlagSet("fmt", flag.ExitOnError)
+
+	basicTypeStr := fs.String("basic", "APPLE", "BASIC type: APPLE, C64, AMS")
+	crunched := fs.Bool("crunched", false, "Read programs written without spaces (10FORI=1TO10)")
+	lowercase := fs.Bool("lowercase", false, "Read keywords written in lowercase (print)")
+	keywordCase := fs.String("case", "upper", "Keyword case: upper, lower")
+	style := fs.String("style", "list", "Spacing: list (LIST output), crunched (no spaces)")
+	width := fs.Int("width", 0, "Wrap lines longer than width between statements (0: no wrapping)")
+	check := fs.Bool("check", false, "List the files that are not formatted, without rewriting them")
+	fs.Usage = func() {
+		fmt.Println("🆘 Usage: basics fmt [options] <file.bas>...")
+		fs.PrintDefaults()
+	}
+	logs := addLogFlags(fs)
+	_ = fs.Parse(args)
+	defer logs.init()()
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	d := readDialect(*basicTypeStr, *crunched, *lowercase)
+
+	var opts unparse.Options
+	switch strings.ToLower(*keywordCase) {
+	case "upper":
+	case "lower":
+		opts.Case = unparse.Lower
+	default:
+		fmt.Printf("⚠️ Unknown keyword case '%s' (upper, lower)\n", *keywordCase)
+		os.Exit(1)
+	}
+	switch strings.ToLower(*style) {
+	case "list":
+	case "crunched":
+		opts.Style = unparse.Crunched
+	default:
+		fmt.Printf("⚠️ Unknown style '%s' (list, crunched)\n", *style)
+		os.Exit(1)
+	}
+	opts.Width = *width
+
+	failed := false
+	for _, filename := range fs.Args() {
+		changed, err := formatFile(filename, d, opts, !*check)
+		switch {
+		case err != nil:
+			fmt.Printf("⚠️ %s: %v\n", filename, err)
+			failed = true
+		case changed && *check:
+			fmt.Println(filename)
+			failed = true
+		case changed:
+			fmt.Printf("✅ FORMATTED: %s\n", filename)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// formatFile formate un fichier et indique s'il a changé ; le fichier
+// n'est réécrit que si write est vrai
+func formatFile(filename string, d *dialect.Dialect, opts unparse.Options, write bool) (bool, error) {
+	data, tokens, prog, err := loadText(filename, d)
+	if err != nil {
+		return false, err
+	}
+
+	opts.Remarks = unparse.Remarks(string(data), tokens)
+	out, err := unparse.Format(prog, d, opts)
+	if err != nil {
+		return false, err
+	}
+
+	if bytes.Equal([]byte(out), data) {
+		return false, nil
+	}
+	if write {
+		return true, os.WriteFile(filename, []byte(out), 0o644)
+	}
+	return true, nil
+}
+
+// loadText lit et analyse un programme texte sans arrêter le programme en
+// cas d'erreur (outils travaillant sur plusieurs fichiers)
+func loadText(filename string, d *dialect.Dialect) ([]byte, []token.Token, *parser.Program, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if applesoft.IsTokenized(data) {
+		return nil, nil, nil, fmt.Errorf("tokenized Applesoft programs are not supported")
+	}
+
+	tokens, err := lexer.Scan(string(data), d)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	prog, errs := parser.NewWithDialect(tokens, d).ParseProgram()
+	if len(errs) > 0 {
+		return nil, nil, nil, syntaxError{errs[0]}
+	}
+	return data, tokens, prog, nil
+}
+
+// syntaxError est la première erreur d'analyse d'un fichier
+type syntaxError struct {
+	err *errors.Error
+}
+
+func (e syntaxError) Error() string {
+	return fmt.Sprintf("%s IN %d (%s)", e.err.Msg, e.err.Line, e.err.Token)
+}
+
+// readDialect retourne le dialecte de lecture des options --basic,
+// --crunched et --lowercase
+func readDialect(basicType string, crunched, lowercase bool) *dialect.Dialect {
+	d := dialect.ForType(parseBasicType(basicType))
+	if crunched {
+		d = d.WithCrunched()
+	}
+	if lowercase {
+		d = d.WithLowercase()
+	}
+	return d
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"basics/internal/interpreter"
+	"basics/internal/parser"
+)
+
+// breakGrace est l'attente d'un programme annulé pendant un INPUT sur le
+// terminal : la lecture de l'entrée standard ne peut pas être interrompue
+const breakGrace = time.Second
+
+// limitOptions sont les limites d'exécution du programme
+type limitOptions struct {
+	maxSteps int
+	timeout  time.Duration
+}
+
+// addLimitFlags déclare les options de limites d'un jeu d'options
+func addLimitFlags(fs *flag.FlagSet) *limitOptions {
+	o := &limitOptions{}
+	fs.IntVar(&o.maxSteps, "max-steps", 0, "Stop the program after this number of statements (0: no limit)")
+	fs.DurationVar(&o.timeout, "timeout", 0, "Stop the program after this duration, e.g. 10s (0: no limit)")
+	return o
+}
+
+// context applique les limites à l'interpréteur et retourne le contexte
+// d'exécution : Ctrl-C l'annule (BREAK), le suivant termine basics
+func (o *limitOptions) context(interp *interpreter.Interpreter) (context.Context, context.CancelFunc) {
+	interp.SetMaxSteps(o.maxSteps)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	context.AfterFunc(ctx, stop)
+	if o.timeout <= 0 {
+		return ctx, stop
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// run exécute le programme dans les limites et retourne la cause de son
+// arrêt, nil s'il s'est terminé
+func (o *limitOptions) run(interp *interpreter.Interpreter, prog *parser.Program) error {
+	ctx, cancel := o.context(interp)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() { result <- interp.RunContext(ctx, prog) }()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(breakGrace):
+		err := interpreter.Cause(ctx)
+		fmt.Printf("\n⚠️ %v\n", err)
+		return err
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"basics/internal/dialect"
+	"basics/internal/lint"
+)
+
+// fileDiagnostic est un diagnostic de la sortie JSON
+type fileDiagnostic struct {
+	File string `json:"file"`
+	lint.Diagnostic
+}
+
+// runLint implémente "basics lint" : analyse statique des programmes. Le
+// code de sortie vaut 1 si un diagnostic est produit.
+func runLint(args []string) {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+
+	format := fs.String("format", "text", "Output format: text, json")
+	basicTypeStr := fs.String("basic", "APPLE", "BASIC type: APPLE, C64, AMS")
+	crunched := fs.Bool("crunched", false, "Read programs written without spaces (10FORI=1TO10)")
+	lowercase := fs.Bool("lowercase", false, "Read keywords written in lowercase (print)")
+	fs.Usage = func() {
+		fmt.Println("🆘 Usage: basics lint [options] <file.bas>...")
+		fs.PrintDefaults()
+	}
+	logs := addLogFlags(fs)
+	_ = fs.Parse(args)
+	defer logs.init()()
+
+	if fs.NArg() < 1 || (*format != "text" && *format != "json") {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	d := readDialect(*basicTypeStr, *crunched, *lowercase)
+
+	all := []fileDiagnostic{}
+	for _, filename := range fs.Args() {
+		for _, diag := range lintFile(filename, d) {
+			all = append(all, fileDiagnostic{File: filename, Diagnostic: diag})
+		}
+	}
+
+	if *format == "json" {
+		out, _ := json.MarshalIndent(all, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		for _, fd := range all {
+			fmt.Printf("%s:%d: %s: %s (%s)\n", fd.File, fd.Line, fd.Severity, fd.Message, fd.Code)
+		}
+	}
+
+	if len(all) > 0 {
+		os.Exit(1)
+	}
+}
+
+// lintFile analyse un fichier ; les erreurs de lecture et de syntaxe sont
+// des diagnostics
+func lintFile(filename string, d *dialect.Dialect) []lint.Diagnostic {
+	_, _, prog, err := loadText(filename, d)
+	if err == nil {
+		return lint.Check(prog, d)
+	}
+
+	diag := lint.Diagnostic{Severity: lint.Error, Code: "syntax", Message: err.Error()}
+	if e, ok := err.(syntaxError); ok {
+		diag.Line, diag.Column, diag.Message = e.err.Line, e.err.Column, e.err.Msg
+	}
+	return []lint.Diagnostic{diag}
+}
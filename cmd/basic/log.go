@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"basics/internal/logger"
+)
+
+// logOptions sont les options de journalisation, communes au programme et
+// aux sous-commandes
+type logOptions struct {
+	level  string
+	file   string
+	format string
+}
+
+// addLogFlags déclare les options de journalisation d'un jeu d'options
+func addLogFlags(fs *flag.FlagSet) *logOptions {
+	o := &logOptions{}
+	fs.StringVar(&o.level, "log-level", "info", "Minimum log level: info (everything), debug, warning, critical, fatal")
+	fs.StringVar(&o.file, "log-file", "none", "Write the log to a file (- for the standard error, none to disable it)")
+	fs.StringVar(&o.format, "log-format", "text", "Log format: text, json")
+	return o
+}
+
+// init initialise le logger ; la fonction retournée ferme le fichier de log
+func (o *logOptions) init() func() {
+	level, err := logger.ParseLevel(o.level)
+	if err == nil {
+		var closeLogger func() error
+		closeLogger, err = logger.Setup(o.file, o.format, "basics", level)
+		if err == nil {
+			logger.Info("Logging initialized")
+			return func() { _ = closeLogger() }
+		}
+	}
+	fmt.Fprintf(os.Stderr, "⚠️ %v\n", err)
+	os.Exit(1)
+	return nil
+}
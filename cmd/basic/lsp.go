@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"basics/internal/lsp"
+)
+
+// runLsp implémente "basics lsp" : serveur Language Server Protocol sur
+// stdin / stdout. stdout étant réservé au protocole, les journaux vont dans
+// un fichier ou sur stderr (--log-file).
+func runLsp(args []string) {
+	fs := flag.NewFlagSet("lsp", flag.ExitOnError)
+
+	basicTypeStr := fs.String("basic", "APPLE", "BASIC type: APPLE, C64, AMS")
+	crunched := fs.Bool("crunched", false, "Read programs written without spaces (10FORI=1TO10)")
+	lowercase := fs.Bool("lowercase", false, "Read keywords written in lowercase (print)")
+	fs.Usage = func() {
+		fmt.Println("🆘 Usage: basics lsp [options]")
+		fs.PrintDefaults()
+	}
+	logs := addLogFlags(fs)
+	_ = fs.Parse(args)
+	defer logs.init()()
+
+	d := readDialect(*basicTypeStr, *crunched, *lowercase)
+	if err := lsp.NewServer(os.Stdin, os.Stdout, d).Serve(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️ %v\n", err)
+		os.Exit(1)
+	}
+}
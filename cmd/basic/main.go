@@ -8,44 +8,133 @@ import (
 	"strings"
 
 	"basics/internal/app"
+	"basics/internal/applesoft"
 	"basics/internal/binary"
 	"basics/internal/constants"
+	"basics/internal/dialect"
+	"basics/internal/disk"
 	"basics/internal/input"
 	"basics/internal/interpreter"
 	"basics/internal/lexer"
+	"basics/internal/lint"
 	"basics/internal/logger"
 	"basics/internal/machines"
 	"basics/internal/parser"
+	"basics/internal/unparse"
 )
 
 func main() {
-	closeLogger, err := logger.InitLogger("basics.log", "basics", logger.LevelInfo)
-	if err != nil {
-		panic(err)
+	// -------------------------
+	// Sous-commandes
+	// -------------------------
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "fmt":
+			runFmt(os.Args[2:])
+			return
+		case "renum":
+			runRenum(os.Args[2:])
+			return
+		case "repl":
+			runRepl(os.Args[2:])
+			return
+		case "lint":
+			runLint(os.Args[2:])
+			return
+		case "lsp":
+			runLsp(os.Args[2:])
+			return
+		case "dap":
+			runDap(os.Args[2:])
+			return
+		}
 	}
-	defer closeLogger()
-
-	logger.Info("Logging initialized")
-	logger.Info("Application starting...")
 
 	// -------------------------
 	// Options CLI
 	// -------------------------
 	var compileBin bool
+	var debugInfo bool
+	var migrate bool
+	var signKey string
+	var verifyKey string
+	var scramble bool
+	var genKey string
+	var tokenize bool
 	var dumpTokens bool
 	var dumpAST bool
 	var tty bool
+	var shortNames bool
+	var crunched bool
+	var lowercase bool
 	var basicTypeStr string
+	var extract bool
+	var saveDisk string
+	var debug bool
+	var traceFile string
+	var profileFile string
+	var profileFormat string
+	var coverFile string
+	var coverFormat string
+	var coverHTML string
 
 	flag.BoolVar(&compileBin, "compile", false, "Generate binary (.bin)")
+	flag.BoolVar(&debugInfo, "debug-info", false, "Keep the source code in the binary (with --compile)")
+	flag.BoolVar(&migrate, "migrate", false, "Convert a binary (.bin) to the current format")
+	flag.StringVar(&signKey, "sign", "", "Sign the binary with an Ed25519 private key (PEM file, with --compile)")
+	flag.StringVar(&verifyKey, "verify", "", "Only run a binary signed by the Ed25519 public key (PEM file)")
+	flag.BoolVar(&scramble, "scramble", false, "Scramble the strings of the binary (with --compile)")
+	flag.StringVar(&genKey, "gen-key", "", "Generate an Ed25519 key pair (<name>.key and <name>.pub)")
+	flag.BoolVar(&tokenize, "tokenize", false, "Generate native Applesoft tokenized file (#fc0801)")
 	flag.BoolVar(&dumpTokens, "dump-tokens", false, "Dump tokens")
 	flag.BoolVar(&dumpAST, "dump-ast", false, "Dump AST")
 	flag.BoolVar(&tty, "tty", false, "Enable TTY output and ensure that your program does not use any graphical instructions.")
+	flag.BoolVar(&shortNames, "short-names", false, "Only the significant characters of variable names are used (2 for APPLE and C64)")
+	flag.BoolVar(&crunched, "crunched", false, "Recognise keywords without spaces (10FORI=1TO10)")
+	flag.BoolVar(&lowercase, "lowercase", false, "Recognise keywords in lowercase (print)")
 	flag.StringVar(&basicTypeStr, "basic", "APPLE", "BASIC type: APPLE, C64, AMS")
+	flag.BoolVar(&extract, "extract", false, "Extract a file from a DOS 3.3 disk image (game.dsk:HELLO)")
+	flag.StringVar(&saveDisk, "save", "", "Save the program into a DOS 3.3 disk image (game.dsk[:NAME])")
+	flag.BoolVar(&debug, "debug", false, "Run the program under the debugger (breakpoints, stepping, watches)")
+	flag.StringVar(&traceFile, "trace", "", "Write the execution trace to a file, one JSON event per statement")
+	flag.StringVar(&profileFile, "profile", "", "Write an execution profile to a file (- for the standard output)")
+	flag.StringVar(&profileFormat, "profile-format", "json", "Profile format: json, table, pprof")
+	flag.StringVar(&coverFile, "cover", "", "Write the statement coverage of the program to a file")
+	flag.StringVar(&coverFormat, "cover-format", "lcov", "Coverage format: lcov, go")
+	flag.StringVar(&coverHTML, "cover-html", "", "Write an HTML coverage report annotating the source")
+	logs := addLogFlags(flag.CommandLine)
+	diags := addDiagFlags(flag.CommandLine)
+	limits := addLimitFlags(flag.CommandLine)
 	flag.Parse()
+	diags.check()
+
+	// code de sortie d'un programme interrompu, après les autres defer
+	status := 0
+	defer func() {
+		if status != 0 {
+			os.Exit(status)
+		}
+	}()
+	defer logs.init()()
+	logger.Info("Application starting...")
+
+	if genKey != "" {
+		if err := binary.GenerateKeyFiles(genKey); err != nil {
+			fmt.Printf("⚠️ Error generating keys: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ KEYS GENERATED: %s.key, %s.pub\n", genKey, genKey)
+		return
+	}
 
 	if flag.NArg() < 1 {
-		fmt.Println("🆘 Usage: basics [options] <file.bas|file.bin>")
+		fmt.Println("🆘 Usage: basics [options] <file.bas|file.bin|disk.dsk[:FILE]>")
+		fmt.Println("          basics fmt [options] <file.bas>...")
+		fmt.Println("          basics renum [options] <file.bas>")
+		fmt.Println("          basics repl [options] [file.bas]")
+		fmt.Println("          basics lint [options] <file.bas>...")
+		fmt.Println("          basics lsp [options]")
+		fmt.Println("          basics dap [options]")
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
@@ -53,7 +142,36 @@ func main() {
 	filename := flag.Arg(0)
 	ext := strings.ToLower(filepath.Ext(filename))
 
-	basicType := constants.BASIC_APPLE
+	// =========================================================
+	// Image de disquette → catalogue ou extraction
+	// =========================================================
+	diskImage, diskFile, inDisk := disk.SplitPath(filename)
+
+	if disk.IsImage(filename) {
+		listCatalog(filename)
+		return
+	}
+
+	if extract {
+		if !inDisk {
+			fmt.Println("⚠️ --extract needs a disk image file (game.dsk:HELLO)")
+			os.Exit(1)
+		}
+		extractDiskFile(diskImage, diskFile)
+		return
+	}
+
+	// BASIC ciblé (dialecte) et machine d'exécution
+	dialectType := parseBasicType(basicTypeStr)
+	basicDialect := dialect.ForType(dialectType)
+	if crunched {
+		basicDialect = basicDialect.WithCrunched()
+	}
+	if lowercase {
+		basicDialect = basicDialect.WithLowercase()
+	}
+
+	basicType := dialectType
 	if tty {
 		basicType = constants.BASIC_TTY
 	}
@@ -68,12 +186,43 @@ func main() {
 			os.Exit(1)
 		}
 
-		// Vérification du header
-		if err := binary.IsValidBasicsBinary(filename); err != nil {
+		if migrate {
+			migrateBinary(filename)
+			return
+		}
+
+		// Vérification du header et de la signature
+		sig, err := binary.IsValidBasicsBinary(filename)
+		if sig == binary.Tampered {
+			fmt.Println("⚠️ TAMPERED BINARY PROGRAM")
+			os.Exit(1)
+		}
+		if err != nil {
 			fmt.Println("⚠️ INVALID BINARY PROGRAM")
 			os.Exit(1)
 		}
 
+		if verifyKey != "" {
+			pub, err := binary.LoadPublicKey(verifyKey)
+			if err == nil {
+				err = binary.VerifyFile(filename, pub)
+			}
+			if err != nil {
+				fmt.Printf("⚠️ SIGNATURE VERIFICATION FAILED: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		// Le BASIC ciblé est celui enregistré dans le header
+		header, err := binary.ReadHeader(filename)
+		if err != nil {
+			fmt.Printf("⚠️ Error reading binary header: %v\n", err)
+			os.Exit(1)
+		}
+		if !tty {
+			basicType = header.BasicType
+		}
+
 		// Décodage binaire → AST
 		prog, err := binary.DecodeProgram(filename)
 		if err != nil {
@@ -90,46 +239,77 @@ func main() {
 			fmt.Println(err)
 			os.Exit(1)
 		}
+		if shortNames {
+			rt.Env.SetNameSignificance(dialect.ForType(header.BasicType))
+		}
 		interp := interpreter.New(rt)
-		interp.Run(prog)
+		if traceFile != "" {
+			defer attachTracer(interp, traceFile)()
+		}
+		if profileFile != "" {
+			defer attachProfiler(interp, profileFile, profileFormat, filename)()
+		}
+		if coverFile != "" || coverHTML != "" {
+			fmt.Println("⚠️ Coverage needs a source program: --cover ignored")
+		}
+		if debug {
+			go attachDebugger(interp, dialect.ForType(header.BasicType)).Console(os.Stdin, os.Stdout)
+		}
+		if err := limits.run(interp, prog); err != nil {
+			status = 1
+		}
 		return
 	}
 
 	// =========================================================
 	// Fichier source en BASIC → pipeline classique
 	// =========================================================
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		fmt.Printf("⚠️ Error reading file %s: %v\n", filename, err)
-		os.Exit(1)
+	var data []byte
+	var err error
+
+	if inDisk {
+		// Programme Applesoft dans une image : les fichiers générés sont
+		// placés à côté de l'image
+		data = loadDiskProgram(diskImage, diskFile)
+		filename = filepath.Join(filepath.Dir(diskImage), diskFile)
+	} else {
+		data, err = os.ReadFile(filename)
+		if err != nil {
+			fmt.Printf("⚠️ Error reading file %s: %v\n", filename, err)
+			os.Exit(1)
+		}
 	}
 
 	logger.Info(fmt.Sprintf("Loaded source file: %s", filename))
 
-	source := string(data)
+	var prog *parser.Program
+	var source string
 
-	// =========================
-	// Lexer
-	// =========================
-	tokens := lexer.Lex(source)
+	if inDisk || applesoft.IsTokenized(data) {
+		// Programme Applesoft tokenisé (ex: extrait d'une disquette)
+		dialectType = constants.BASIC_APPLE
+		basicDialect = dialect.Applesoft
+		if !tty {
+			basicType = dialectType
+		}
 
-	if dumpTokens {
-		fmt.Println("=== TOKENS ===")
-		lexer.DumpTokens(tokens)
+		prog, err = applesoft.Decode(data)
+		if err != nil {
+			fmt.Printf("⚠️ Error decoding Applesoft program: %v\n", err)
+			os.Exit(1)
+		}
+		source = unparse.Program(prog)
+	} else {
+		source = string(data)
+		prog = parseSource(filename, source, basicDialect, dumpTokens, diags)
 	}
 
-	// =========================
-	// Parser
-	// =========================
-	p := parser.New(tokens)
-	prog, errs := p.ParseProgram()
-
-	if len(errs) > 0 {
-		fmt.Println("\n=== ERRORS ===")
-		for _, e := range errs {
-			fmt.Println(e.Error())
+	// Noms de variables identiques pour la machine d'origine
+	if warnings := lint.NameCollisions(prog, basicDialect); len(warnings) > 0 {
+		fmt.Println("\n=== WARNINGS ===")
+		for _, w := range warnings {
+			fmt.Println(w.String())
 		}
-		os.Exit(1)
 	}
 
 	if dumpAST {
@@ -143,21 +323,20 @@ func main() {
 	if compileBin {
 		outFile := changeExt(filename, ".bin")
 
-		// déterminer le type BASIC
-		var basicType byte
-		switch strings.ToUpper(basicTypeStr) {
-		case "APPLE":
-			basicType = constants.BASIC_APPLE
-		case "C64":
-			basicType = constants.BASIC_C64
-		case "AMS":
-			basicType = constants.BASIC_AMS
-		default:
-			fmt.Printf("Unknown BASIC type '%s', using APPLE\n", basicTypeStr)
-			basicType = constants.BASIC_APPLE
-		}
-
-		if err := binary.EncodeProgram(prog, outFile, basicType); err != nil {
+		opts := binary.Options{Debug: debugInfo, Scramble: scramble}
+		if debugInfo {
+			opts.SourceFile = filepath.Base(filename)
+			opts.Source = string(data)
+		}
+		if signKey != "" {
+			opts.SignKey, err = binary.LoadPrivateKey(signKey)
+			if err != nil {
+				fmt.Printf("⚠️ Error reading signing key: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if err := binary.EncodeProgramWithOptions(prog, outFile, dialectType, opts); err != nil {
 			fmt.Printf("⚠️ Error during binary compilation: %v\n", err)
 			os.Exit(1)
 		}
@@ -165,6 +344,34 @@ func main() {
 		os.Exit(0) // fin du programme après compilation
 	}
 
+	// =========================
+	// Export Applesoft tokenisé
+	// =========================
+	if tokenize {
+		// suffixe #fc0801 : type $FC (Applesoft), adresse $0801, reconnu
+		// à l'import par les utilitaires de disquettes Apple II
+		outFile := changeExt(filename, "") + "#fc0801"
+
+		data, err := applesoft.Encode(prog)
+		if err == nil {
+			err = os.WriteFile(outFile, data, 0o644)
+		}
+		if err != nil {
+			fmt.Printf("⚠️ Error during Applesoft export: %v\n", err)
+			os.Exit(1)
+		}
+
+		os.Exit(0)
+	}
+
+	// =========================
+	// Sauvegarde sur disquette
+	// =========================
+	if saveDisk != "" {
+		saveToDisk(saveDisk, prog, filename)
+		os.Exit(0)
+	}
+
 	// =========================
 	// Interpreter
 	// =========================
@@ -175,28 +382,118 @@ func main() {
 		os.Exit(1)
 	}
 
+	if shortNames {
+		rt.Env.SetNameSignificance(basicDialect)
+	}
+
 	interp := interpreter.New(rt)
+	if traceFile != "" {
+		defer attachTracer(interp, traceFile)()
+	}
+	if profileFile != "" {
+		defer attachProfiler(interp, profileFile, profileFormat, filename)()
+	}
+	if coverFile != "" || coverHTML != "" {
+		defer attachCoverage(interp, coverFile, coverFormat, coverHTML, filename, source, basicDialect)()
+	}
+
+	var dbg *interpreter.Debugger
+	if debug {
+		dbg = attachDebugger(interp, basicDialect)
+	}
 
 	// --------------------
 	// Mode Terminal (for test and debug purpose)
 	// --------------------
 	if basicType == constants.BASIC_TTY {
 		rt.Input = input.NewTTYInput(os.Stdin, os.Stdout)
-		interp.Run(prog)
+		if dbg != nil {
+			go dbg.Console(os.Stdin, os.Stdout)
+		}
+		if err := limits.run(interp, prog); err != nil {
+			status = 1
+		}
 		return
 	}
 
 	// --------------------
-	// Mode graphique
+	// Mode graphique : la console du débogueur reste dans le terminal
 	// --------------------
-	basicApp := app.NewBasicEbitenApp(rt, interp, prog)
+	if dbg != nil {
+		go dbg.Console(os.Stdin, os.Stdout)
+	}
+	ctx, cancel := limits.context(interp)
+	defer cancel()
+	basicApp := app.NewBasicEbitenApp(ctx, rt, interp, prog)
 	ebitenApp := app.NewEbitenApp(basicApp)
 
 	if err := ebitenApp.Run(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
+	if basicApp.Err() != nil {
+		status = 1
+	}
+
+}
+
+// parseSource analyse un source BASIC texte. Le programme s'arrête en cas
+// d'erreur de syntaxe, après les avoir toutes affichées.
+func parseSource(filename, source string, d *dialect.Dialect, dumpTokens bool, diags *diagOptions) *parser.Program {
+	// =========================
+	// Lexer
+	// =========================
+	tokens := lexer.LexDialect(source, d)
+
+	if dumpTokens {
+		fmt.Println("=== TOKENS ===")
+		lexer.DumpTokens(tokens)
+	}
+
+	// =========================
+	// Parser
+	// =========================
+	p := parser.NewWithDialect(tokens, d)
+	prog, errs := p.ParseProgram()
+
+	if len(errs) > 0 {
+		diags.report(filename, source, prog, errs)
+		os.Exit(1)
+	}
+
+	return prog
+}
+
+// migrateBinary réécrit un binaire v1 au format courant
+func migrateBinary(filename string) {
+	data, err := os.ReadFile(filename)
+	if err == nil {
+		data, err = binary.Migrate(data)
+	}
+	if err == nil {
+		err = os.WriteFile(filename, data, 0o644)
+	}
+	if err != nil {
+		fmt.Printf("⚠️ Error during binary migration: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ BINARY FILE MIGRATED: %s\n", filename)
+}
 
+// parseBasicType convertit l'option --basic en type BASIC
+func parseBasicType(name string) byte {
+	switch strings.ToUpper(name) {
+	case "APPLE":
+		return constants.BASIC_APPLE
+	case "C64":
+		return constants.BASIC_C64
+	case "AMS":
+		return constants.BASIC_AMS
+	default:
+		fmt.Printf("Unknown BASIC type '%s', using APPLE\n", name)
+		return constants.BASIC_APPLE
+	}
 }
 
 // changeExt remplace l'extension d'un fichier
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"basics/testutils"
+)
+
+// mainArgs fait exécuter main() par l'exécutable de test relancé par
+// runMain ; les arguments sont séparés par \x1f
+const mainArgs = "BASICS_MAIN_ARGS"
+
+func TestMain(m *testing.M) {
+	if args, ok := os.LookupEnv(mainArgs); ok {
+		os.Args = append([]string{"basics"}, strings.Split(args, "\x1f")...)
+		main()
+		os.Exit(0)
+	}
+	testutils.RunWithAssertTracking(m)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"basics/internal/interpreter"
+)
+
+// attachProfiler profile l'exécution du programme ; la fonction retournée
+// écrit le rapport en fin de programme, dans path ("-" : sortie standard)
+// au format json, table ou pprof
+func attachProfiler(interp *interpreter.Interpreter, path, format, program string) func() {
+	switch format {
+	case "json", "table", "pprof":
+	default:
+		fmt.Printf("⚠️ Unknown profile format %s (json, table, pprof)\n", format)
+		os.Exit(1)
+	}
+
+	profiler := interpreter.NewProfiler()
+	interp.SetProfiler(profiler)
+
+	return func() {
+		prof := profiler.Profile()
+		if prof == nil {
+			fmt.Println("⚠️ Program still running: no profile written")
+			return
+		}
+
+		var w io.Writer = os.Stdout
+		if path != "-" {
+			f, err := os.Create(path)
+			if err != nil {
+				fmt.Printf("⚠️ Error creating profile file: %v\n", err)
+				return
+			}
+			defer f.Close()
+			w = f
+		}
+
+		var err error
+		switch format {
+		case "table":
+			err = prof.WriteTable(w)
+		case "pprof":
+			err = prof.WritePprof(w, program)
+		default:
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(prof)
+		}
+		if err != nil {
+			fmt.Printf("⚠️ Error writing profile: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"basics/internal/renum"
+	"basics/internal/unparse"
+)
+
+// runRenum implémente "basics renum" : renumérote un programme et réécrit
+// les numéros cités par GOTO, GOSUB et THEN
+func runRenum(args []string) {
+	fs := flag.NewFlagSet("renum", flag.ExitOnError)
+
+	start := fs.Int("start", 10, "First new line number")
+	step := fs.Int("step", 10, "Increment between line numbers")
+	from := fs.Int("from", 0, "First line to renumber")
+	to := fs.Int("to", 0, "Last line to renumber (0: last line of the program)")
+	basicTypeStr := fs.String("basic", "APPLE", "BASIC type: APPLE, C64, AMS")
+	crunched := fs.Bool("crunched", false, "Read programs written without spaces (10FORI=1TO10)")
+	lowercase := fs.Bool("lowercase", false, "Read keywords written in lowercase (print)")
+	fs.Usage = func() {
+		fmt.Println("🆘 Usage: basics renum [options] <file.bas>")
+		fs.PrintDefaults()
+	}
+	logs := addLogFlags(fs)
+	_ = fs.Parse(args)
+	defer logs.init()()
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	filename := fs.Arg(0)
+	d := readDialect(*basicTypeStr, *crunched, *lowercase)
+
+	data, tokens, prog, err := loadText(filename, d)
+	if err != nil {
+		fmt.Printf("⚠️ %s: %v\n", filename, err)
+		os.Exit(1)
+	}
+
+	res, err := renum.Renumber(prog, renum.Options{Start: *start, Step: *step, From: *from, To: *to})
+	if err != nil {
+		fmt.Printf("⚠️ %s: %v\n", filename, err)
+		os.Exit(1)
+	}
+	for _, line := range res.Computed {
+		fmt.Printf("⚠️ COMPUTED GOTO OR GOSUB NOT RENUMBERED IN %d\n", line)
+	}
+
+	opts := unparse.Options{Remarks: res.Remarks(unparse.Remarks(string(data), tokens))}
+	out, err := unparse.Format(prog, d, opts)
+	if err == nil {
+		err = os.WriteFile(filename, []byte(out), 0o644)
+	}
+	if err != nil {
+		fmt.Printf("⚠️ %s: %v\n", filename, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ RENUMBERED: %s (%d lines)\n", filename, len(res.Lines))
+}
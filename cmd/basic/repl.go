@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"basics/internal/repl"
+)
+
+// runRepl implémente "basics repl" : le mode immédiat (LIST, RUN, RENUM...)
+// sur un programme vide ou chargé d'un fichier
+func runRepl(args []string) {
+	fs := flag.NewFlagSet("repl", flag.ExitOnError)
+
+	basicTypeStr := fs.String("basic", "APPLE", "BASIC type: APPLE, C64, AMS")
+	crunched := fs.Bool("crunched", false, "Read programs written without spaces (10FORI=1TO10)")
+	lowercase := fs.Bool("lowercase", false, "Read keywords written in lowercase (print)")
+	fs.Usage = func() {
+		fmt.Println("🆘 Usage: basics repl [options] [file.bas]")
+		fs.PrintDefaults()
+	}
+	logs := addLogFlags(fs)
+	_ = fs.Parse(args)
+	defer logs.init()()
+
+	if fs.NArg() > 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	s := repl.New(os.Stdin, os.Stdout, readDialect(*basicTypeStr, *crunched, *lowercase))
+	if fs.NArg() == 1 {
+		if err := s.Load(fs.Arg(0)); err != nil {
+			fmt.Printf("⚠️ %s: %v\n", fs.Arg(0), err)
+			os.Exit(1)
+		}
+	}
+	s.Loop()
+}
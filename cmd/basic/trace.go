@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"basics/internal/interpreter"
+)
+
+// attachTracer écrit la trace d'exécution JSON de l'interpréteur dans un
+// fichier ; la fonction retournée ferme le fichier en fin de programme
+func attachTracer(interp *interpreter.Interpreter, path string) func() {
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Printf("⚠️ Error creating trace file: %v\n", err)
+		os.Exit(1)
+	}
+
+	tracer := interpreter.NewTracer(f)
+	interp.SetTracer(tracer)
+
+	return func() {
+		if err := tracer.Err(); err != nil {
+			fmt.Printf("⚠️ Error writing trace file: %v\n", err)
+		}
+		_ = f.Close()
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"basics/testutils"
+)
+
+// runMain lance la commande basics et retourne sa sortie et son code de
+// sortie
+func runMain(t *testing.T, args ...string) (string, int) {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), mainArgs+"="+strings.Join(args, "\x1f"))
+	out, err := cmd.CombinedOutput()
+
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		return string(out), exit.ExitCode()
+	}
+	if err != nil {
+		t.Fatalf("run %v: %v", args, err)
+	}
+	return string(out), 0
+}
+
+func TestLimits_ExitStatus(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "loop.bas")
+	if err := os.WriteFile(src, []byte("10 GOTO 10\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, code := runMain(t, "--tty", "--max-steps", "100", src)
+	testutils.Equal(t, "source status", code, 1)
+	testutils.True(t, "source message: "+out, strings.Contains(out, "STEP LIMIT EXCEEDED"))
+
+	_, code = runMain(t, "--compile", src)
+	testutils.Equal(t, "compile status", code, 0)
+
+	bin := strings.TrimSuffix(src, ".bas") + ".bin"
+	out, code = runMain(t, "--tty", "--max-steps", "100", bin)
+	testutils.Equal(t, "binary status", code, 1)
+	testutils.True(t, "binary message: "+out, strings.Contains(out, "STEP LIMIT EXCEEDED"))
+
+	_, code = runMain(t, "--tty", "--timeout", "50ms", bin)
+	testutils.Equal(t, "binary timeout status", code, 1)
+}
+
+func TestLimits_Debugger(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "loop.bas")
+	if err := os.WriteFile(src, []byte("10 GOTO 10\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// sans entrée, la console détache le débogueur et le programme continue
+	out, code := runMain(t, "--tty", "--debug", "--max-steps", "100", src)
+	testutils.Equal(t, "status", code, 1)
+	testutils.True(t, "message: "+out, strings.Contains(out, "STEP LIMIT EXCEEDED"))
+}
@@ -0,0 +1,9 @@
+10 MODE 2
+20 PLOT 0,0
+30 DRAW 639,399
+40 PLOT 0,399,1
+50 DRAW 639,0
+60 FOR X = 0 TO 600 STEP 40
+70 PLOT X,200:DRAW X+20,220
+80 NEXT X
+90 END
@@ -0,0 +1,7 @@
+10 MODE 0
+20 FOR I = 0 TO 15
+30 PEN I
+40 PRINT "PEN ";I
+50 NEXT I
+60 INK 15,6,24
+70 END
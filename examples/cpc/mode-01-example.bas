@@ -0,0 +1,7 @@
+10 MODE 1
+20 BORDER 0
+30 INK 0,0:INK 1,26
+40 PEN 1:PAPER 0:CLS
+50 LOCATE 10,5
+60 PRINT "Amstrad CPC 6128"
+70 END
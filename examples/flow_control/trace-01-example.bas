@@ -0,0 +1,8 @@
+10 REM **** TRACE affiche le numero des lignes executees ****
+20 TRACE
+30 FOR I = 1 TO 2 : PRINT I : NEXT I
+40 GOSUB 100
+50 NOTRACE
+60 PRINT "FIN"
+70 END
+100 PRINT "SOUS-PROGRAMME" : RETURN
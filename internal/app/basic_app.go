@@ -1,10 +1,11 @@
 package app
 
 import (
+	"context"
+
 	"basics/internal/interpreter"
 	"basics/internal/parser"
 	"basics/internal/runtime"
-	"basics/internal/video"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -14,29 +15,59 @@ type BasicEbitenApp struct {
 	Runtime     *runtime.Runtime
 	Interpreter *interpreter.Interpreter
 	Program     *parser.Program
+
+	ctx    context.Context
+	cancel context.CancelFunc // BREAK
+	done   chan struct{}      // fermé à la fin du programme
+	err    error              // cause de l'arrêt du programme
 }
 
-// NewBasicEbitenApp crée une app graphique BASIC
+// NewBasicEbitenApp crée une app graphique BASIC ; le programme s'arrête
+// quand ctx est annulé
 func NewBasicEbitenApp(
+	ctx context.Context,
 	rt *runtime.Runtime,
 	interp *interpreter.Interpreter,
 	prog *parser.Program,
 ) *BasicEbitenApp {
+	ctx, cancel := context.WithCancel(ctx)
 	return &BasicEbitenApp{
 		Runtime:     rt,
 		Interpreter: interp,
 		Program:     prog,
+		ctx:         ctx,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+}
+
+// start lance le programme
+func (a *BasicEbitenApp) start() {
+	go func() {
+		a.err = a.Interpreter.RunContext(a.ctx, a.Program)
+		close(a.done)
+	}()
+}
+
+// Err retourne la cause de l'arrêt du programme (BREAK, limites) ; nil
+// s'il s'est terminé normalement ou s'exécute encore
+func (a *BasicEbitenApp) Err() error {
+	select {
+	case <-a.done:
+		return a.err
+	default:
+		return nil
 	}
 }
 
 func (a *BasicEbitenApp) Draw(screen *ebiten.Image) {
-	if dev, ok := a.Runtime.Video.(video.EbitenDevice); ok {
+	if dev, ok := a.Runtime.Video.(ebitenDevice); ok {
 		dev.Draw(screen)
 	}
 }
 
 func (a *BasicEbitenApp) Layout(w, h int) (int, int) {
-	if dev, ok := a.Runtime.Video.(video.EbitenDevice); ok {
+	if dev, ok := a.Runtime.Video.(ebitenDevice); ok {
 		return dev.Layout(w, h)
 	}
 	return w, h
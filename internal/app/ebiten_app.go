@@ -3,12 +3,44 @@ package app
 import (
 	"errors"
 
-	"basics/internal/machines/apple2"
 	"basics/internal/video"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+// ebitenDevice est implémenté par les devices affichés par Ebiten
+type ebitenDevice interface {
+	video.Device
+	Draw(screen *ebiten.Image)
+	Layout(w, h int) (int, int)
+}
+
+// keyboardDevice est implémenté par les devices qui gèrent le clavier
+// pour INPUT et GET
+type keyboardDevice interface {
+	IsGetActive() bool
+	PushGetRune(r rune)
+	InputRune(r rune)
+	Backspace()
+	Enter()
+}
+
+// updatableDevice est implémenté par les devices animés (curseur, clignotement)
+type updatableDevice interface {
+	Update() error
+}
+
+// breakableDevice est implémenté par les devices dont la saisie peut être
+// interrompue par Ctrl-C
+type breakableDevice interface {
+	Break()
+}
+
+// titledDevice fournit le titre de la fenêtre
+type titledDevice interface {
+	Title() string
+}
+
 // EbitenApp implémente ebiten.Game
 type EbitenApp struct {
 	*BasicEbitenApp
@@ -28,11 +60,16 @@ func NewEbitenApp(basic *BasicEbitenApp) *EbitenApp {
 func (a *EbitenApp) Run() error {
 
 	// Vérification que le device supporte Ebiten
-	if _, ok := a.Runtime.Video.(video.EbitenDevice); !ok {
+	if _, ok := a.Runtime.Video.(ebitenDevice); !ok {
 		return errors.New("video device does not support Ebiten")
 	}
 
-	ebiten.SetWindowTitle("BASIC – Apple II")
+	title := "BASIC"
+	if d, ok := a.Runtime.Video.(titledDevice); ok {
+		title = d.Title()
+	}
+
+	ebiten.SetWindowTitle(title)
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 
 	return ebiten.RunGame(a)
@@ -48,11 +85,11 @@ func (a *EbitenApp) Update() error {
 	if !a.started {
 		a.started = true
 
-		go a.Interpreter.Run(a.Program)
+		a.start()
 	}
 
-	if t, ok := a.Runtime.Video.(*apple2.Text40); ok {
-		t.Update()
+	if d, ok := a.Runtime.Video.(updatableDevice); ok {
+		d.Update()
 	}
 
 	a.handleInput()
@@ -61,7 +98,16 @@ func (a *EbitenApp) Update() error {
 }
 
 func (a *EbitenApp) handleInput() {
-	t, ok := a.Runtime.Video.(*apple2.Text40)
+	// Ctrl-C : BREAK, y compris pendant INPUT et GET
+	if a.keyJustPressed(ebiten.KeyC) && ebiten.IsKeyPressed(ebiten.KeyControl) {
+		a.cancel()
+		if d, ok := a.Runtime.Video.(breakableDevice); ok {
+			d.Break()
+		}
+		return
+	}
+
+	t, ok := a.Runtime.Video.(keyboardDevice)
 	if !ok {
 		return
 	}
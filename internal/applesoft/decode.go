@@ -0,0 +1,160 @@
+package applesoft
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"basics/internal/dialect"
+	"basics/internal/lexer"
+	"basics/internal/parser"
+)
+
+// LoadAddress est l'adresse de chargement standard d'un programme
+// Applesoft ($0801). Les pointeurs de chaînage en dépendent.
+const LoadAddress = 0x0801
+
+// MaxLineNumber est le plus grand numéro de ligne accepté par Applesoft
+const MaxLineNumber = 63999
+
+// Line est une ligne de programme détokenisée
+type Line struct {
+	Number int
+	Text   string
+}
+
+// Detokenize convertit un programme tokenisé (format mémoire) en lignes de
+// texte. Chaque ligne est précédée d'un pointeur de chaînage sur 2 octets
+// et de son numéro sur 2 octets, et terminée par 0. Un pointeur nul marque
+// la fin du programme.
+func Detokenize(data []byte) ([]Line, error) {
+	var lines []Line
+	pos := 0
+
+	for {
+		if pos+2 > len(data) {
+			return nil, fmt.Errorf("truncated program at offset %d", pos)
+		}
+
+		link := binary.LittleEndian.Uint16(data[pos:])
+		if link == 0 {
+			return lines, nil
+		}
+
+		if pos+4 > len(data) {
+			return nil, fmt.Errorf("truncated line header at offset %d", pos)
+		}
+		number := int(binary.LittleEndian.Uint16(data[pos+2:]))
+		pos += 4
+
+		end := pos
+		for end < len(data) && data[end] != 0 {
+			end++
+		}
+		if end >= len(data) {
+			return nil, fmt.Errorf("line %d: missing end of line", number)
+		}
+
+		text, err := detokenizeLine(data[pos:end])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+
+		lines = append(lines, Line{Number: number, Text: text})
+		pos = end + 1
+	}
+}
+
+// detokenizeLine développe les tokens d'une ligne. Les mots-clés sont
+// entourés d'espaces, à la manière de LIST.
+func detokenizeLine(body []byte) (string, error) {
+	var sb strings.Builder
+
+	for _, b := range body {
+		if b < FirstToken {
+			sb.WriteByte(b)
+			continue
+		}
+
+		name, ok := TokenName(b)
+		if !ok {
+			return "", fmt.Errorf("invalid token 0x%02X", b)
+		}
+
+		if !isWord(name) {
+			sb.WriteString(name)
+			continue
+		}
+
+		if sb.Len() > 0 && !strings.HasSuffix(sb.String(), " ") &&
+			!strings.HasSuffix(sb.String(), ":") {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(name)
+		if !strings.ContainsAny(name[len(name)-1:], "(=:#") {
+			sb.WriteByte(' ')
+		}
+	}
+
+	return strings.TrimRight(sb.String(), " "), nil
+}
+
+// isWord indique si le token est un mot (et non un opérateur)
+func isWord(name string) bool {
+	return name[0] >= 'A' && name[0] <= 'Z'
+}
+
+// Listing retourne le listing texte des lignes, une ligne par ligne BASIC
+func Listing(lines []Line) string {
+	var sb strings.Builder
+	for _, line := range lines {
+		fmt.Fprintf(&sb, "%d %s\n", line.Number, line.Text)
+	}
+	return sb.String()
+}
+
+// Decode convertit un programme Applesoft tokenisé en programme BASICS
+func Decode(data []byte) (*parser.Program, error) {
+	lines, err := Detokenize(data)
+	if err != nil {
+		return nil, err
+	}
+
+	d := dialect.Applesoft
+	tokens, err := lexer.Scan(Listing(lines), d)
+	if err != nil {
+		return nil, err
+	}
+
+	prog, errs := parser.NewWithDialect(tokens, d).ParseProgram()
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return prog, nil
+}
+
+// IsTokenized indique si les données ressemblent à un programme Applesoft
+// tokenisé plutôt qu'à un source texte : le premier pointeur de chaînage
+// doit désigner la fin de la première ligne.
+func IsTokenized(data []byte) bool {
+	if len(data) < 2 {
+		return false
+	}
+
+	link := int(binary.LittleEndian.Uint16(data))
+	if link == 0 {
+		return len(data) == 2 // programme vide
+	}
+
+	end := link - LoadAddress - 1
+	if end < 4 || end >= len(data) || data[end] != 0 {
+		return false
+	}
+
+	for _, b := range data[4:end] {
+		if b == 0 || (b >= FirstToken && int(b) > LastToken) {
+			return false
+		}
+	}
+	return true
+}
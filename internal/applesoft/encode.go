@@ -0,0 +1,228 @@
+package applesoft
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"basics/internal/parser"
+	"basics/internal/unparse"
+)
+
+// Tokenize convertit des lignes de texte en programme tokenisé (format
+// mémoire, chargé en LoadAddress), terminé par un pointeur nul.
+func Tokenize(lines []Line) ([]byte, error) {
+	var out []byte
+	addr := LoadAddress
+
+	for _, line := range lines {
+		if line.Number < 0 || line.Number > MaxLineNumber {
+			return nil, fmt.Errorf("line %d: line number out of range", line.Number)
+		}
+
+		body := tokenizeLine(line.Text)
+		for _, r := range line.Text {
+			if r >= 0x80 {
+				return nil, fmt.Errorf("line %d: character %q is not available on Apple II", line.Number, r)
+			}
+		}
+
+		if len(body) > 239 {
+			return nil, fmt.Errorf("line %d: line too long", line.Number)
+		}
+
+		addr += 4 + len(body) + 1
+		out = binary.LittleEndian.AppendUint16(out, uint16(addr))
+		out = binary.LittleEndian.AppendUint16(out, uint16(line.Number))
+		out = append(out, body...)
+		out = append(out, 0)
+	}
+
+	return append(out, 0, 0), nil
+}
+
+// tokenizeLine reproduit le tokenizer de la ROM : les blancs sont ignorés
+// hors chaînes, REM et DATA, les minuscules converties en majuscules, et
+// les mots-clés reconnus n'importe où, y compris à l'intérieur des noms
+// de variables.
+func tokenizeLine(text string) []byte {
+	src := []byte(text)
+	var out []byte
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == ' ':
+			i++
+
+		case c == '"':
+			// chaîne recopiée telle quelle
+			out = append(out, c)
+			i++
+			for i < len(src) && src[i] != '"' {
+				out = append(out, src[i])
+				i++
+			}
+			if i < len(src) {
+				out = append(out, '"')
+				i++
+			}
+
+		case c == '?':
+			// ? est le raccourci de PRINT
+			out = append(out, tokenPRINT)
+			i++
+
+		default:
+			tok, next := matchToken(src, i)
+			if tok == 0 {
+				out = append(out, upper(c))
+				i++
+				continue
+			}
+
+			out = append(out, tok)
+			i = next
+
+			switch tok {
+			case tokenREM:
+				// commentaire recopié jusqu'à la fin de la ligne
+				out = append(out, src[i:]...)
+				i = len(src)
+			case tokenDATA:
+				// données recopiées jusqu'au ':' hors chaîne
+				quoted := false
+				for i < len(src) && (quoted || src[i] != ':') {
+					if src[i] == '"' {
+						quoted = !quoted
+					}
+					out = append(out, src[i])
+					i++
+				}
+			}
+		}
+	}
+
+	return out
+}
+
+// matchToken cherche un mot-clé à la position pos, dans l'ordre de la
+// table de la ROM. Retourne le token et la position qui le suit, ou 0.
+func matchToken(src []byte, pos int) (byte, int) {
+	for idx, kw := range Tokens {
+		end, ok := matchAt(src, pos, kw)
+		if !ok {
+			continue
+		}
+
+		tok := byte(FirstToken + idx)
+		if tok == tokenAT && end < len(src) {
+			// ATN : AT immédiatement suivi de N n'est pas AT
+			if upper(src[end]) == 'N' {
+				continue
+			}
+			// A TO : AT suivi de O est la variable A suivie de TO
+			if upper(src[end]) == 'O' {
+				return 0, pos
+			}
+		}
+		return tok, end
+	}
+	return 0, pos
+}
+
+// matchAt compare le mot-clé au texte à partir de pos, blancs ignorés
+func matchAt(src []byte, pos int, kw string) (int, bool) {
+	i := pos
+	for j := 0; j < len(kw); j++ {
+		if j > 0 {
+			for i < len(src) && src[i] == ' ' {
+				i++
+			}
+		}
+		if i >= len(src) || upper(src[i]) != kw[j] {
+			return pos, false
+		}
+		i++
+	}
+	return i, true
+}
+
+func upper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// Encode convertit un programme BASICS en programme Applesoft tokenisé.
+// Comme à la saisie sur Apple II, les noms de variables sont convertis en
+// majuscules. Les instructions sans équivalent Applesoft (ELSE, instructions CPC,
+// extensions BASICS) sont refusées.
+func Encode(prog *parser.Program) ([]byte, error) {
+	lines := make([]Line, 0, len(prog.Lines))
+
+	for _, line := range prog.Lines {
+		if err := checkApplesoft(line); err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, Line{
+			Number: line.Number,
+			Text:   unparse.Statements(line.Stmts),
+		})
+	}
+
+	return Tokenize(lines)
+}
+
+// checkApplesoft vérifie que la ligne n'utilise que des instructions
+// Applesoft, et des noms de variables que la ROM ne découpera pas en
+// mots-clés (SCORE contient OR)
+func checkApplesoft(line *parser.Line) error {
+	var err error
+
+	checkName := func(name string) {
+		for _, b := range tokenizeLine(name) {
+			if b >= FirstToken {
+				err = fmt.Errorf("line %d: variable %s contains a reserved word", line.Number, name)
+				return
+			}
+		}
+	}
+
+	for _, stmt := range line.Stmts {
+		parser.Inspect(stmt, func(node any) bool {
+			if err != nil {
+				return false
+			}
+			switch n := node.(type) {
+			case *parser.Identifier:
+				checkName(n.Name)
+			case *parser.LetStmt:
+				checkName(n.Name)
+			case *parser.ForStmt:
+				checkName(n.Var)
+			case *parser.NextStmt:
+				checkName(n.Var)
+			case *parser.IfStmt:
+				if n.Else != nil {
+					err = fmt.Errorf("line %d: ELSE is not supported by Applesoft", line.Number)
+				}
+			case *parser.ModeStmt, *parser.ClsStmt, *parser.LocateStmt,
+				*parser.InkStmt, *parser.PenStmt, *parser.PaperStmt,
+				*parser.BorderStmt, *parser.PlotStmt, *parser.DrawStmt,
+				*parser.IfJumpStmt:
+				err = fmt.Errorf("line %d: %s is not supported by Applesoft",
+					line.Number, parser.StmtName(n.(parser.Statement)))
+			case *parser.CallStmt:
+				err = fmt.Errorf("line %d: host statement %s is not supported by Applesoft", line.Number, n.Name)
+			case *parser.CallExpr:
+				err = fmt.Errorf("line %d: host function %s is not supported by Applesoft", line.Number, n.Name)
+			}
+			return err == nil
+		})
+	}
+
+	return err
+}
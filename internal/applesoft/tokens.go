@@ -0,0 +1,45 @@
+package applesoft
+
+// FirstToken est la valeur du premier token Applesoft (END)
+const FirstToken = 0x80
+
+// Tokens est la table des mots-clés de la ROM Applesoft, dans l'ordre des
+// valeurs de token (0x80 à 0xEA). L'ordre est aussi celui de la recherche
+// lors de la tokenisation, comme dans la ROM : HGR2 avant HGR, ONERR avant ON.
+var Tokens = [...]string{
+	"END", "FOR", "NEXT", "DATA", "INPUT", "DEL", "DIM", "READ",
+	"GR", "TEXT", "PR#", "IN#", "CALL", "PLOT", "HLIN", "VLIN",
+	"HGR2", "HGR", "HCOLOR=", "HPLOT", "DRAW", "XDRAW", "HTAB", "HOME",
+	"ROT=", "SCALE=", "SHLOAD", "TRACE", "NOTRACE", "NORMAL", "INVERSE", "FLASH",
+	"COLOR=", "POP", "VTAB", "HIMEM:", "LOMEM:", "ONERR", "RESUME", "RECALL",
+	"STORE", "SPEED=", "LET", "GOTO", "RUN", "IF", "RESTORE", "&",
+	"GOSUB", "RETURN", "REM", "STOP", "ON", "WAIT", "LOAD", "SAVE",
+	"DEF", "POKE", "PRINT", "CONT", "LIST", "CLEAR", "GET", "NEW",
+	"TAB(", "TO", "FN", "SPC(", "THEN", "AT", "NOT", "STEP",
+	"+", "-", "*", "/", "^", "AND", "OR", ">",
+	"=", "<", "SGN", "INT", "ABS", "USR", "FRE", "SCRN(",
+	"PDL", "POS", "SQR", "RND", "LOG", "EXP", "COS", "SIN",
+	"TAN", "ATN", "PEEK", "LEN", "STR$", "VAL", "ASC", "CHR$",
+	"LEFT$", "RIGHT$", "MID$",
+}
+
+// LastToken est la valeur du dernier token Applesoft (MID$)
+const LastToken = FirstToken + len(Tokens) - 1
+
+// Tokens utilisés par le tokenizer pour les cas particuliers
+const (
+	tokenDATA  = 0x83
+	tokenREM   = 0xB2
+	tokenPRINT = 0xBA
+	tokenAT    = 0xC5
+	tokenATN   = 0xE1
+)
+
+// TokenName retourne le mot-clé d'un token, ou false si la valeur n'est pas
+// un token Applesoft
+func TokenName(b byte) (string, bool) {
+	if int(b) < FirstToken || int(b) > LastToken {
+		return "", false
+	}
+	return Tokens[int(b)-FirstToken], true
+}
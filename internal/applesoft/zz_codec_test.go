@@ -0,0 +1,204 @@
+package applesoft
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"basics/internal/dialect"
+	"basics/internal/lexer"
+	"basics/internal/parser"
+	"basics/internal/unparse"
+	"basics/testutils"
+)
+
+// 10 PRINT "HELLO", tel que sauvegardé par un Apple II
+var helloProgram = []byte{
+	0x0E, 0x08, 0x0A, 0x00, 0xBA, '"', 'H', 'E', 'L', 'L', 'O', '"', 0x00,
+	0x00, 0x00,
+}
+
+func TestTokenize_Hello(t *testing.T) {
+	data, err := Tokenize([]Line{{Number: 10, Text: `PRINT "HELLO"`}})
+
+	testutils.True(t, "no error", err == nil)
+	testutils.True(t, fmt.Sprintf("bytes % X", data), bytes.Equal(data, helloProgram))
+}
+
+func TestTokenize_LinkPointers(t *testing.T) {
+	data, err := Tokenize([]Line{
+		{Number: 10, Text: "HOME"},
+		{Number: 20, Text: "END"},
+	})
+	testutils.True(t, "no error", err == nil)
+
+	// ligne 10 : 4 + 1 + 1 octets → ligne 20 en $0807
+	testutils.Equal(t, "first link", int(data[0])|int(data[1])<<8, 0x0807)
+	testutils.Equal(t, "second link", int(data[6])|int(data[7])<<8, 0x080D)
+	testutils.Equal(t, "second line number", int(data[8]), 20)
+	testutils.True(t, "end of program", bytes.Equal(data[len(data)-2:], []byte{0, 0}))
+}
+
+func TestTokenizeLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []byte
+	}{
+		{"crunched FOR", "FORI=1TO10", []byte{0x81, 'I', 0xD0, '1', 0xC1, '1', '0'}},
+		{"blanks removed", "FOR I = 1", []byte{0x81, 'I', 0xD0, '1'}},
+		{"A TO B", "FORI=ATOB", []byte{0x81, 'I', 0xD0, 'A', 0xC1, 'B'}},
+		{"ATN", "X=ATN(1)", []byte{'X', 0xD0, 0xE1, '(', '1', ')'}},
+		{"AT N", "HLIN0,39AT N", []byte{0x8E, '0', ',', '3', '9', 0xC5, 'N'}},
+		{"HGR2 before HGR", "HGR2", []byte{0x90}},
+		{"HCOLOR=", "HCOLOR=3", []byte{0x92, '3'}},
+		{"question mark", "?A", []byte{0xBA, 'A'}},
+		{"lowercase", "print a", []byte{0xBA, 'A'}},
+		{"string kept", `PRINT "for a"`, []byte{0xBA, '"', 'f', 'o', 'r', ' ', 'a', '"'}},
+		{"SCORE", "SCORE", []byte{'S', 'C', 0xCE, 'E'}},
+		{"REM kept", "REM GOTO:X", []byte{0xB2, ' ', 'G', 'O', 'T', 'O', ':', 'X'}},
+		{"DATA until colon", `DATA 1,"A:B":END`, []byte{0x83, ' ', '1', ',', '"', 'A', ':', 'B', '"', ':', 0x80}},
+		{"operators", "A<=B", []byte{'A', 0xD1, 0xD0, 'B'}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tokenizeLine(tt.text)
+			testutils.True(t, fmt.Sprintf("got % X, want % X", got, tt.expected), bytes.Equal(got, tt.expected))
+		})
+	}
+}
+
+func TestDetokenize(t *testing.T) {
+	lines, err := Detokenize(helloProgram)
+
+	testutils.True(t, "no error", err == nil)
+	testutils.Equal(t, "line count", len(lines), 1)
+	testutils.Equal(t, "line number", lines[0].Number, 10)
+	testutils.Equal(t, "line text", lines[0].Text, `PRINT "HELLO"`)
+
+	data, _ := Tokenize([]Line{{Number: 20, Text: "FORI=1TO10STEP2:HCOLOR=3:X=A<=B"}})
+	lines, _ = Detokenize(data)
+	testutils.Equal(t, "listing", lines[0].Text, "FOR I=1 TO 10 STEP 2:HCOLOR=3:X=A<=B")
+}
+
+func TestDetokenize_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", []byte{}},
+		{"truncated header", []byte{0x07, 0x08, 0x0A}},
+		{"missing end of line", []byte{0x07, 0x08, 0x0A, 0x00, 0x97}},
+		{"invalid token", []byte{0x07, 0x08, 0x0A, 0x00, 0xF0, 0x00, 0x00, 0x00}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Detokenize(tt.data)
+			testutils.True(t, "error expected", err != nil)
+		})
+	}
+}
+
+func TestIsTokenized(t *testing.T) {
+	testutils.True(t, "tokenized program", IsTokenized(helloProgram))
+	testutils.True(t, "empty program", IsTokenized([]byte{0, 0}))
+	testutils.False(t, "source text", IsTokenized([]byte("10 PRINT \"HELLO\"\n")))
+	testutils.False(t, "short source", IsTokenized([]byte("1")))
+}
+
+func TestTokenName(t *testing.T) {
+	name, ok := TokenName(0x80)
+	testutils.True(t, "END", ok && name == "END")
+
+	name, ok = TokenName(0xEA)
+	testutils.True(t, "MID$", ok && name == "MID$")
+
+	_, ok = TokenName(0xEB)
+	testutils.False(t, "0xEB is not a token", ok)
+
+	testutils.Equal(t, "last token", LastToken, 0xEA)
+}
+
+func TestTokenize_NonASCII(t *testing.T) {
+	_, err := Tokenize([]Line{{Number: 10, Text: `PRINT "ÉTÉ"`}})
+	testutils.True(t, "accents are refused", err != nil)
+}
+
+func TestEncode_Refused(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		dialect *dialect.Dialect
+	}{
+		{"ELSE", "10 IF A THEN PRINT 1 ELSE PRINT 2\n", dialect.Applesoft},
+		{"reserved word in name", "10 SCORE = 1\n", dialect.Applesoft},
+		{"CPC statement", "10 MODE 1\n", dialect.Locomotive},
+		{"host statement", "10 BEEP 1, 2\n", dialect.Applesoft},
+		{"host function", "10 PRINT TWICE(2)\n", dialect.Applesoft},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := parser.NewWithDialect(lexer.LexDialect(tt.src, tt.dialect), tt.dialect)
+			p.Extend([]string{"BEEP"}, []string{"TWICE"})
+			prog, errs := p.ParseProgram()
+			testutils.Equal(t, "no parser errors", len(errs), 0)
+
+			_, err := Encode(prog)
+			testutils.True(t, "error expected", err != nil)
+		})
+	}
+}
+
+// TestEncodeDecode_Examples vérifie l'aller-retour AST → tokens → AST sur
+// les exemples compatibles Applesoft
+func TestEncodeDecode_Examples(t *testing.T) {
+	files, err := filepath.Glob("../../examples/*/*.bas")
+	testutils.True(t, "examples found", err == nil && len(files) > 0)
+
+	more, _ := filepath.Glob("../../examples/*/*/*.bas")
+	files = append(files, more...)
+
+	encoded := 0
+	for _, file := range files {
+		if strings.Contains(file, "/cpc/") {
+			continue
+		}
+
+		src, err := os.ReadFile(file)
+		testutils.True(t, "read "+file, err == nil)
+
+		tokens, err := lexer.Scan(string(src), dialect.Applesoft)
+		if err != nil {
+			continue
+		}
+		prog, errs := parser.New(tokens).ParseProgram()
+		if len(errs) > 0 {
+			continue
+		}
+
+		data, err := Encode(prog)
+		if err != nil {
+			continue // ELSE, accents, noms contenant un mot réservé...
+		}
+		encoded++
+
+		decoded, err := Decode(data)
+		testutils.True(t, fmt.Sprintf("decode %s: %v", file, err), err == nil)
+		if err != nil {
+			continue
+		}
+
+		// les noms de variables sont convertis en majuscules
+		testutils.Equal(t, file,
+			strings.ToUpper(unparse.Program(decoded)),
+			strings.ToUpper(unparse.Program(prog)))
+	}
+
+	testutils.True(t, fmt.Sprintf("%d examples encoded", encoded), encoded > 20)
+}
@@ -0,0 +1,650 @@
+package binary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"basics/internal/parser"
+)
+
+// Opcodes des instructions. 0x00 représente une instruction vide (REM).
+const (
+	opRem    byte = 0x00
+	opLet    byte = 0x01
+	opPrint  byte = 0x02
+	opFor    byte = 0x03
+	opNext   byte = 0x04
+	opInput  byte = 0x05
+	opGet    byte = 0x06
+	opHTab   byte = 0x07
+	opVTab   byte = 0x08
+	opEnd    byte = 0x09
+	opHome   byte = 0x0A
+	opGoto   byte = 0x0B
+	opGosub  byte = 0x0C
+	opReturn byte = 0x0D
+	opIf     byte = 0x0E
+	opIfJump byte = 0x0F
+
+	// Amstrad CPC
+	opMode   byte = 0x20
+	opCls    byte = 0x21
+	opLocate byte = 0x22
+	opInk    byte = 0x23
+	opPen    byte = 0x24
+	opPaper  byte = 0x25
+	opBorder byte = 0x26
+	opPlot   byte = 0x27
+	opDraw   byte = 0x28
+
+	// Applesoft
+	opTrace   byte = 0x30
+	opNoTrace byte = 0x31
+)
+
+// Opcodes des expressions. 0x00 représente un argument facultatif absent.
+const (
+	opNil    byte = 0x00
+	opNumber byte = 0x10
+	opString byte = 0x11
+	opIdent  byte = 0x12
+	opPrefix byte = 0x13
+	opInfix  byte = 0x14
+	opInt    byte = 0x15
+	opAbs    byte = 0x16
+	opSgn    byte = 0x17
+)
+
+// maxDepth limite l'imbrication des expressions et des IF à la lecture
+const maxDepth = 1000
+
+// =========================
+// Encodage
+// =========================
+
+// astEncoder écrit les lignes de l'AST d'un conteneur v2. Les chaînes et
+// les nombres sont remplacés par leur indice dans le pool. La première
+// erreur est conservée et arrête l'encodage.
+type astEncoder struct {
+	buf  bytes.Buffer
+	pool *Pool
+	err  error
+}
+
+func (e *astEncoder) fail(format string, args ...any) {
+	if e.err == nil {
+		e.err = fmt.Errorf("encoder: "+format, args...)
+	}
+}
+
+func (e *astEncoder) byte(b byte) {
+	e.buf.WriteByte(b)
+}
+
+func (e *astEncoder) u16(v int) {
+	if v < 0 || v > math.MaxUint16 {
+		e.fail("value %d out of range", v)
+		return
+	}
+	e.buf.Write(binary.LittleEndian.AppendUint16(nil, uint16(v)))
+}
+
+func (e *astEncoder) u32(v int) {
+	if v < 0 || v > math.MaxUint32 {
+		e.fail("value %d out of range", v)
+		return
+	}
+	e.buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(v)))
+}
+
+func (e *astEncoder) str(s string) {
+	i, err := e.pool.AddString(s)
+	if err != nil && e.err == nil {
+		e.err = err
+	}
+	e.u16(int(i))
+}
+
+func (e *astEncoder) num(v float64) {
+	i, err := e.pool.AddNumber(v)
+	if err != nil && e.err == nil {
+		e.err = err
+	}
+	e.u16(int(i))
+}
+
+// pos écrit la position d'un nœud, utilisée par les messages d'erreur
+func (e *astEncoder) pos(line, column int, token string) {
+	e.u16(line)
+	e.u16(column)
+	e.str(token)
+}
+
+func (e *astEncoder) line(line *parser.Line) {
+	e.u16(line.Number)
+	e.stmts(line.Stmts)
+}
+
+func (e *astEncoder) stmts(stmts []parser.Statement) {
+	e.u16(len(stmts))
+	for _, s := range stmts {
+		e.stmt(s)
+	}
+}
+
+func (e *astEncoder) exprs(exprs ...parser.Expression) {
+	for _, x := range exprs {
+		e.expr(x)
+	}
+}
+
+func (e *astEncoder) stmt(stmt parser.Statement) {
+	switch s := stmt.(type) {
+	case nil:
+		e.byte(opRem)
+
+	case *parser.LetStmt:
+		e.byte(opLet)
+		e.str(s.Name)
+		e.expr(s.Value)
+
+	case *parser.PrintStmt:
+		e.byte(opPrint)
+		e.u16(len(s.Exprs))
+		e.exprs(s.Exprs...)
+		e.u16(len(s.Separators))
+		for _, sep := range s.Separators {
+			if sep > 0x7F {
+				e.fail("invalid PRINT separator %q", sep)
+			}
+			e.byte(byte(sep))
+		}
+
+	case *parser.ForStmt:
+		e.byte(opFor)
+		e.str(s.Var)
+		e.exprs(s.Start, s.End, s.Step)
+		e.u16(s.LineNum)
+		e.u16(s.Column)
+
+	case *parser.NextStmt:
+		e.byte(opNext)
+		e.str(s.Var)
+		e.u16(s.ForLineNum)
+
+	case *parser.InputStmt:
+		e.byte(opInput)
+		if s.Prompt != nil {
+			e.expr(s.Prompt)
+		} else {
+			e.byte(opNil)
+		}
+		e.u16(len(s.Vars))
+		for _, v := range s.Vars {
+			e.ident(v)
+		}
+		e.u16(s.Line)
+		e.u16(s.Column)
+
+	case *parser.GetStmt:
+		e.byte(opGet)
+		e.ident(s.Var)
+
+	case *parser.HTabStmt:
+		e.byte(opHTab)
+		e.expr(s.Expr)
+
+	case *parser.VTabStmt:
+		e.byte(opVTab)
+		e.expr(s.Expr)
+
+	case *parser.EndStmt:
+		e.byte(opEnd)
+
+	case *parser.HomeStmt:
+		e.byte(opHome)
+		e.u16(s.Line)
+		e.u16(s.Column)
+
+	case *parser.TraceStmt:
+		e.byte(opTrace)
+
+	case *parser.NoTraceStmt:
+		e.byte(opNoTrace)
+
+	case *parser.GotoStmt:
+		e.byte(opGoto)
+		e.expr(s.Expr)
+
+	case *parser.GosubStmt:
+		e.byte(opGosub)
+		e.expr(s.Expr)
+
+	case *parser.ReturnStmt:
+		e.byte(opReturn)
+
+	case *parser.IfStmt:
+		e.byte(opIf)
+		e.expr(s.Cond)
+		e.stmts(s.Then)
+		// ELSE absent et ELSE vide sont distingués
+		if s.Else == nil {
+			e.byte(0)
+		} else {
+			e.byte(1)
+			e.stmts(s.Else)
+		}
+
+	case *parser.IfJumpStmt:
+		e.byte(opIfJump)
+		e.expr(s.Cond)
+		e.u32(s.Target)
+
+	case *parser.ModeStmt:
+		e.byte(opMode)
+		e.expr(s.Expr)
+
+	case *parser.ClsStmt:
+		e.byte(opCls)
+
+	case *parser.LocateStmt:
+		e.byte(opLocate)
+		e.exprs(s.X, s.Y)
+
+	case *parser.InkStmt:
+		e.byte(opInk)
+		e.exprs(s.Ink, s.Color1, s.Color2)
+
+	case *parser.PenStmt:
+		e.byte(opPen)
+		e.expr(s.Expr)
+
+	case *parser.PaperStmt:
+		e.byte(opPaper)
+		e.expr(s.Expr)
+
+	case *parser.BorderStmt:
+		e.byte(opBorder)
+		e.exprs(s.Color1, s.Color2)
+
+	case *parser.PlotStmt:
+		e.byte(opPlot)
+		e.exprs(s.X, s.Y, s.Ink)
+
+	case *parser.DrawStmt:
+		e.byte(opDraw)
+		e.exprs(s.X, s.Y, s.Ink)
+
+	default:
+		e.fail("statement not supported %T", stmt)
+	}
+}
+
+func (e *astEncoder) ident(id *parser.Identifier) {
+	if id == nil {
+		e.byte(opNil)
+		return
+	}
+	e.expr(id)
+}
+
+func (e *astEncoder) expr(expr parser.Expression) {
+	switch x := expr.(type) {
+	case nil:
+		e.byte(opNil)
+
+	case *parser.NumberLiteral:
+		e.byte(opNumber)
+		e.num(x.Value)
+		e.pos(x.Line, x.Column, x.Token)
+
+	case *parser.StringLiteral:
+		e.byte(opString)
+		e.str(x.Value)
+		e.pos(x.Line, x.Column, x.Token)
+
+	case *parser.Identifier:
+		e.byte(opIdent)
+		e.str(x.Name)
+		e.pos(x.Line, x.Column, x.Token)
+
+	case *parser.PrefixExpr:
+		e.byte(opPrefix)
+		e.str(x.Op)
+		e.expr(x.Right)
+		e.pos(x.Line, x.Column, x.Token)
+
+	case *parser.InfixExpr:
+		e.byte(opInfix)
+		e.str(x.Op)
+		e.exprs(x.Left, x.Right)
+		e.pos(x.Line, x.Column, x.Token)
+
+	case *parser.IntExpr:
+		e.byte(opInt)
+		e.expr(x.Expr)
+		e.pos(x.Line, x.Column, x.Token)
+
+	case *parser.AbsExpr:
+		e.byte(opAbs)
+		e.expr(x.Expr)
+		e.pos(x.Line, x.Column, x.Token)
+
+	case *parser.SgnExpr:
+		e.byte(opSgn)
+		e.expr(x.Expr)
+		e.pos(x.Line, x.Column, x.Token)
+
+	default:
+		e.fail("expression not supported %T", expr)
+	}
+}
+
+// =========================
+// Décodage
+// =========================
+
+// astDecoder lit les lignes de l'AST d'un conteneur v2. Toute donnée
+// invalide (opcode inconnu, indice hors du pool, section tronquée) est
+// une erreur.
+type astDecoder struct {
+	r     *bytes.Reader
+	pool  *Pool
+	err   error
+	depth int
+}
+
+func (d *astDecoder) fail(format string, args ...any) {
+	if d.err == nil {
+		d.err = fmt.Errorf("decoder: "+format, args...)
+	}
+}
+
+func (d *astDecoder) byte() byte {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		d.fail("unexpected end of data")
+	}
+	return b
+}
+
+func (d *astDecoder) u16() int {
+	var buf [2]byte
+	if _, err := io.ReadFull(d.r, buf[:]); err != nil {
+		d.fail("unexpected end of data")
+		return 0
+	}
+	return int(binary.LittleEndian.Uint16(buf[:]))
+}
+
+func (d *astDecoder) u32() int {
+	var buf [4]byte
+	if _, err := io.ReadFull(d.r, buf[:]); err != nil {
+		d.fail("unexpected end of data")
+		return 0
+	}
+	return int(binary.LittleEndian.Uint32(buf[:]))
+}
+
+func (d *astDecoder) str() string {
+	s, err := d.pool.String(uint16(d.u16()))
+	if err != nil {
+		d.fail("%v", err)
+	}
+	return s
+}
+
+func (d *astDecoder) num() float64 {
+	v, err := d.pool.Number(uint16(d.u16()))
+	if err != nil {
+		d.fail("%v", err)
+	}
+	return v
+}
+
+// lines lit les lignes jusqu'à la fin de la section
+func (d *astDecoder) lines() (*parser.Program, error) {
+	prog := &parser.Program{}
+
+	for d.r.Len() > 0 && d.err == nil {
+		line := &parser.Line{Number: d.u16()}
+		line.Stmts = d.stmts()
+		prog.Lines = append(prog.Lines, line)
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+	return prog, nil
+}
+
+func (d *astDecoder) stmts() []parser.Statement {
+	n := d.u16()
+
+	var stmts []parser.Statement
+	for i := 0; i < n && d.err == nil; i++ {
+		stmts = append(stmts, d.stmt())
+	}
+	return stmts
+}
+
+func (d *astDecoder) stmt() parser.Statement {
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > maxDepth {
+		d.fail("statements nested too deeply")
+		return nil
+	}
+
+	op := d.byte()
+	if d.err != nil {
+		return nil
+	}
+
+	switch op {
+	case opRem:
+		return nil
+
+	case opLet:
+		return &parser.LetStmt{Name: d.str(), Value: d.expr()}
+
+	case opPrint:
+		s := &parser.PrintStmt{}
+		n := d.u16()
+		for i := 0; i < n && d.err == nil; i++ {
+			s.Exprs = append(s.Exprs, d.expr())
+		}
+		n = d.u16()
+		for i := 0; i < n && d.err == nil; i++ {
+			s.Separators = append(s.Separators, rune(d.byte()))
+		}
+		return s
+
+	case opFor:
+		s := &parser.ForStmt{Var: d.str()}
+		s.Start, s.End, s.Step = d.expr(), d.expr(), d.expr()
+		s.LineNum, s.Column = d.u16(), d.u16()
+		return s
+
+	case opNext:
+		return &parser.NextStmt{Var: d.str(), ForLineNum: d.u16()}
+
+	case opInput:
+		s := &parser.InputStmt{}
+		if prompt := d.expr(); prompt != nil {
+			p, ok := prompt.(*parser.StringLiteral)
+			if !ok {
+				d.fail("invalid INPUT prompt %T", prompt)
+			}
+			s.Prompt = p
+		}
+		n := d.u16()
+		for i := 0; i < n && d.err == nil; i++ {
+			s.Vars = append(s.Vars, d.ident())
+		}
+		s.Line, s.Column = d.u16(), d.u16()
+		return s
+
+	case opGet:
+		return &parser.GetStmt{Var: d.ident()}
+
+	case opHTab:
+		return &parser.HTabStmt{Expr: d.expr()}
+
+	case opVTab:
+		return &parser.VTabStmt{Expr: d.expr()}
+
+	case opEnd:
+		return &parser.EndStmt{}
+
+	case opHome:
+		return &parser.HomeStmt{Line: d.u16(), Column: d.u16()}
+
+	case opTrace:
+		return &parser.TraceStmt{}
+
+	case opNoTrace:
+		return &parser.NoTraceStmt{}
+
+	case opGoto:
+		return &parser.GotoStmt{Expr: d.expr()}
+
+	case opGosub:
+		return &parser.GosubStmt{Expr: d.expr()}
+
+	case opReturn:
+		return &parser.ReturnStmt{}
+
+	case opIf:
+		s := &parser.IfStmt{Cond: d.expr(), Then: d.stmts()}
+		switch d.byte() {
+		case 0:
+		case 1:
+			s.Else = d.stmts()
+			if s.Else == nil {
+				s.Else = []parser.Statement{}
+			}
+		default:
+			d.fail("invalid ELSE flag")
+		}
+		return s
+
+	case opIfJump:
+		return &parser.IfJumpStmt{Cond: d.expr(), Target: d.u32()}
+
+	case opMode:
+		return &parser.ModeStmt{Expr: d.expr()}
+
+	case opCls:
+		return &parser.ClsStmt{}
+
+	case opLocate:
+		return &parser.LocateStmt{X: d.expr(), Y: d.expr()}
+
+	case opInk:
+		return &parser.InkStmt{Ink: d.expr(), Color1: d.expr(), Color2: d.expr()}
+
+	case opPen:
+		return &parser.PenStmt{Expr: d.expr()}
+
+	case opPaper:
+		return &parser.PaperStmt{Expr: d.expr()}
+
+	case opBorder:
+		return &parser.BorderStmt{Color1: d.expr(), Color2: d.expr()}
+
+	case opPlot:
+		return &parser.PlotStmt{X: d.expr(), Y: d.expr(), Ink: d.expr()}
+
+	case opDraw:
+		return &parser.DrawStmt{X: d.expr(), Y: d.expr(), Ink: d.expr()}
+
+	default:
+		d.fail("unknown statement opcode 0x%02X", op)
+		return nil
+	}
+}
+
+func (d *astDecoder) ident() *parser.Identifier {
+	x := d.expr()
+	if x == nil {
+		return nil
+	}
+
+	id, ok := x.(*parser.Identifier)
+	if !ok {
+		d.fail("identifier expected, got %T", x)
+	}
+	return id
+}
+
+func (d *astDecoder) expr() parser.Expression {
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > maxDepth {
+		d.fail("expressions nested too deeply")
+		return nil
+	}
+
+	op := d.byte()
+	if d.err != nil {
+		return nil
+	}
+
+	switch op {
+	case opNil:
+		return nil
+
+	case opNumber:
+		x := &parser.NumberLiteral{Value: d.num()}
+		x.Line, x.Column, x.Token = d.pos()
+		return x
+
+	case opString:
+		x := &parser.StringLiteral{Value: d.str()}
+		x.Line, x.Column, x.Token = d.pos()
+		return x
+
+	case opIdent:
+		x := &parser.Identifier{Name: d.str()}
+		x.Line, x.Column, x.Token = d.pos()
+		return x
+
+	case opPrefix:
+		x := &parser.PrefixExpr{Op: d.str(), Right: d.expr()}
+		x.Line, x.Column, x.Token = d.pos()
+		return x
+
+	case opInfix:
+		x := &parser.InfixExpr{Op: d.str()}
+		x.Left, x.Right = d.expr(), d.expr()
+		x.Line, x.Column, x.Token = d.pos()
+		return x
+
+	case opInt:
+		x := &parser.IntExpr{Expr: d.expr()}
+		x.Line, x.Column, x.Token = d.pos()
+		return x
+
+	case opAbs:
+		x := &parser.AbsExpr{Expr: d.expr()}
+		x.Line, x.Column, x.Token = d.pos()
+		return x
+
+	case opSgn:
+		x := &parser.SgnExpr{Expr: d.expr()}
+		x.Line, x.Column, x.Token = d.pos()
+		return x
+
+	default:
+		d.fail("unknown expression opcode 0x%02X", op)
+		return nil
+	}
+}
+
+func (d *astDecoder) pos() (int, int, string) {
+	return d.u16(), d.u16(), d.str()
+}
@@ -0,0 +1,175 @@
+package binary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Étiquettes des sections d'un conteneur v2
+const (
+	SectionAST     = "AST " // lignes et instructions
+	SectionPool    = "POOL" // chaînes et constantes numériques
+	SectionSymbols = "SYMS" // variables du programme
+	SectionLineMap = "LMAP" // numéro de ligne → position dans l'AST
+	SectionData    = "DATA" // valeurs des instructions DATA
+	SectionDebug   = "DBUG" // source d'origine
+	SectionSpans   = "SPAN" // positions des nœuds dans le source
+)
+
+// SectionRequired : un lecteur qui ne connaît pas la section doit refuser
+// le fichier. Les autres sections inconnues sont ignorées.
+const SectionRequired uint16 = 1 << 0
+
+// ErrChecksum signale des données modifiées ou corrompues
+var ErrChecksum = errors.New("⚠️ CRC32 mismatch")
+
+// sections comprises par ce lecteur
+var knownSections = map[string]bool{
+	SectionAST:     true,
+	SectionPool:    true,
+	SectionSymbols: true,
+	SectionLineMap: true,
+	SectionData:    true,
+	SectionDebug:   true,
+	SectionSign:    true,
+	SectionSpans:   true,
+}
+
+// sectionHeader précède le contenu de chaque section
+type sectionHeader struct {
+	Tag      [4]byte
+	Flags    uint16
+	Reserved uint16
+	Length   uint32
+	CRC32    uint32
+}
+
+// Section est une section étiquetée d'un conteneur v2
+type Section struct {
+	Tag   string
+	Flags uint16
+	Data  []byte
+}
+
+// Container est un fichier binaire v2 en mémoire
+type Container struct {
+	Header   HeaderV2
+	Sections []Section
+}
+
+// NewContainer retourne un conteneur vide pour le BASIC donné
+func NewContainer(basicType, version byte) *Container {
+	c := &Container{}
+	copy(c.Header.Magic[:], MagicV2)
+	c.Header.Format = FormatV2
+	c.Header.BasicType = basicType
+	c.Header.Version = version
+	return c
+}
+
+// Section retourne la section portant l'étiquette, ou nil
+func (c *Container) Section(tag string) *Section {
+	for i := range c.Sections {
+		if c.Sections[i].Tag == tag {
+			return &c.Sections[i]
+		}
+	}
+	return nil
+}
+
+// AddSection ajoute une section et la fonctionnalité correspondante
+func (c *Container) AddSection(tag string, flags uint16, feature uint32, data []byte) {
+	c.Sections = append(c.Sections, Section{Tag: tag, Flags: flags, Data: data})
+	c.Header.SectionCount = uint16(len(c.Sections))
+	c.Header.Features |= feature
+	if flags&SectionRequired != 0 {
+		c.Header.Required |= feature
+	}
+}
+
+// Bytes sérialise le conteneur : en-tête puis sections, chacune avec sa
+// longueur et son CRC32
+func (c *Container) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+
+	header := c.Header
+	header.SectionCount = uint16(len(c.Sections))
+	if err := binary.Write(&buf, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+
+	for _, s := range c.Sections {
+		if len(s.Tag) != 4 {
+			return nil, fmt.Errorf("invalid section tag %q", s.Tag)
+		}
+
+		sh := sectionHeader{
+			Flags:  s.Flags,
+			Length: uint32(len(s.Data)),
+			CRC32:  crc32.ChecksumIEEE(s.Data),
+		}
+		copy(sh.Tag[:], s.Tag)
+
+		if err := binary.Write(&buf, binary.LittleEndian, &sh); err != nil {
+			return nil, err
+		}
+		buf.Write(s.Data)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ParseContainer lit un conteneur v2. Les formats plus récents sont
+// acceptés tant que leurs fonctionnalités et sections obligatoires sont
+// connues ; les sections facultatives inconnues sont conservées telles
+// quelles.
+func ParseContainer(data []byte) (*Container, error) {
+	r := bytes.NewReader(data)
+	c := &Container{}
+
+	if err := binary.Read(r, binary.LittleEndian, &c.Header); err != nil {
+		return nil, fmt.Errorf("⚠️ truncated header: %w", err)
+	}
+	if string(c.Header.Magic[:]) != MagicV2 {
+		return nil, fmt.Errorf("⚠️ invalid magic string")
+	}
+	if c.Header.Format < FormatV2 {
+		return nil, fmt.Errorf("⚠️ invalid container format %d", c.Header.Format)
+	}
+	if unknown := c.Header.Required &^ KnownFeatures; unknown != 0 {
+		return nil, fmt.Errorf("⚠️ unsupported required features 0x%08X", unknown)
+	}
+
+	for i := 0; i < int(c.Header.SectionCount); i++ {
+		var sh sectionHeader
+		if err := binary.Read(r, binary.LittleEndian, &sh); err != nil {
+			return nil, fmt.Errorf("⚠️ truncated section header %d: %w", i, err)
+		}
+
+		tag := string(sh.Tag[:])
+		if int64(sh.Length) > int64(r.Len()) {
+			return nil, fmt.Errorf("⚠️ truncated section %q", tag)
+		}
+
+		payload := make([]byte, sh.Length)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return nil, err
+		}
+
+		if crc := crc32.ChecksumIEEE(payload); crc != sh.CRC32 {
+			return nil, fmt.Errorf("%w in section %q (expected 0x%08X, got 0x%08X)",
+				ErrChecksum, tag, sh.CRC32, crc)
+		}
+		if !knownSections[tag] && sh.Flags&SectionRequired != 0 {
+			return nil, fmt.Errorf("⚠️ unsupported required section %q", tag)
+		}
+
+		c.Sections = append(c.Sections, Section{Tag: tag, Flags: sh.Flags, Data: payload})
+	}
+
+	return c, nil
+}
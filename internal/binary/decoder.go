@@ -2,9 +2,10 @@ package binary
 
 import (
 	"bytes"
+	"crypto/ed25519"
 	"encoding/binary"
+	"errors"
 	"fmt"
-	"hash/crc32"
 	"io"
 	"os"
 
@@ -12,199 +13,84 @@ import (
 	"basics/internal/parser"
 )
 
+// DecodeProgram lit un fichier binaire BASICS, conteneur v2 ou fichier v1
 func DecodeProgram(filename string) (*parser.Program, error) {
-	f, err := os.Open(filename)
+	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
-
-	// =========================
-	// 1️⃣ Lire le HEADER
-	// =========================
-	var header Header
-	if err := binary.Read(f, binary.LittleEndian, &header); err != nil {
-		return nil, err
-	}
-
-	// Magic
-	if string(header.Magic[:]) != MagicString {
-		return nil, fmt.Errorf("⚠️ invalid magic string")
-	}
 
-	// BASIC type
-	if _, ok := constants.BasicName[header.BasicType]; !ok {
-		return nil, fmt.Errorf("⚠️ unknown BASIC type")
-	}
-
-	// Version
-	if constants.BasicVersion[header.BasicType] != header.Version {
-		return nil, fmt.Errorf("⚠️ BASIC version mismatch")
+	if isV1(data) {
+		return decodeV1(data, true)
 	}
 
 	// =========================
-	// 2️⃣ Lire le payload AST
+	// 1️⃣ Lire le conteneur
 	// =========================
-	astData, err := io.ReadAll(f)
+	c, err := Load(data)
 	if err != nil {
 		return nil, err
 	}
 
 	// =========================
-	// 3️⃣ Vérifier CRC32
-	// =========================
-	crc := crc32.ChecksumIEEE(astData)
-	if crc != header.CRC32 {
-		return nil, fmt.Errorf("⚠️ CRC32 mismatch (expected 0x%08X, got 0x%08X)",
-			header.CRC32, crc)
-	}
-
-	// =========================
-	// 4️⃣ Décoder l’AST
+	// 2️⃣ Décoder l’AST
 	// =========================
-	r := bytes.NewReader(astData)
-	prog := &parser.Program{}
-
-	for {
-		line, err := decodeLine(r)
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
-		prog.Lines = append(prog.Lines, line)
+	prog, err := c.Program()
+	if err != nil {
+		return nil, err
 	}
 
 	// =========================
-	// 5️⃣ Infos header
+	// 3️⃣ Infos header
 	// =========================
 	fmt.Println("📦 BINARY HEADER")
-	fmt.Printf("Magic     : %s\n", header.Magic)
-	fmt.Printf("Basic type: %s\n", constants.BasicName[header.BasicType])
-	fmt.Printf("Version   : %d\n", header.Version)
-	fmt.Printf("Nodes     : %d\n", header.NodeCount)
-	fmt.Printf("CRC32     : 0x%08X\n\n", header.CRC32)
+	fmt.Printf("Magic     : %s\n", c.Header.Magic)
+	fmt.Printf("Format    : %d\n", c.Header.Format)
+	fmt.Printf("Basic type: %s\n", constants.BasicName[c.Header.BasicType])
+	fmt.Printf("Version   : %d\n", c.Header.Version)
+	fmt.Printf("Nodes     : %d\n", c.Header.NodeCount)
+	fmt.Printf("Features  : 0x%08X\n", c.Header.Features)
+	fmt.Printf("Sections  : %s\n", sectionTags(c))
+	fmt.Printf("Signature : %s\n\n", c.SignatureStatus())
 
 	return prog, nil
 }
 
-func decodeLine(r io.Reader) (*parser.Line, error) {
-	lineNum, err := readUint16(r)
-	if err != nil {
-		return nil, err
+// Load lit un conteneur v2 et vérifie qu'il peut être exécuté. Un fichier
+// v1 est converti en conteneur v2.
+func Load(data []byte) (*Container, error) {
+	if isV1(data) {
+		return migrateV1(data)
 	}
 
-	stmtCount, err := readUint16(r)
+	c, err := ParseContainer(data)
 	if err != nil {
 		return nil, err
 	}
 
-	line := &parser.Line{
-		Number: int(lineNum),
-	}
-
-	for i := 0; i < int(stmtCount); i++ {
-		stmt, err := decodeStatement(r)
-		if err != nil {
-			return nil, err
-		}
-		line.Stmts = append(line.Stmts, stmt)
+	if err := checkBasic(c.Header.BasicType, c.Header.Version); err != nil {
+		return nil, err
 	}
 
-	return line, nil
+	return c, nil
 }
 
-func decodeStatement(r io.Reader) (parser.Statement, error) {
-	op, err := readByte(r)
-	if err != nil {
-		return nil, err
+// checkBasic vérifie le BASIC ciblé : un programme écrit pour une version
+// plus récente de l'interpréteur est refusé
+func checkBasic(basicType, version byte) error {
+	if _, ok := constants.BasicName[basicType]; !ok {
+		return fmt.Errorf("⚠️ unknown BASIC type")
 	}
-
-	switch op {
-
-	case 0x01: // LET
-		name, _ := readString(r)
-		val, _ := decodeExpression(r)
-		return &parser.LetStmt{
-			Name:  name,
-			Value: val,
-		}, nil
-
-	case 0x02: // PRINT
-		n, _ := readUint16(r)
-		exprs := make([]parser.Expression, 0, n)
-		for i := 0; i < int(n); i++ {
-			e, _ := decodeExpression(r)
-			exprs = append(exprs, e)
-		}
-		return &parser.PrintStmt{Exprs: exprs}, nil
-
-	case 0x03: // FOR
-		name, _ := readString(r)
-		start, _ := decodeExpression(r)
-		end, _ := decodeExpression(r)
-		step, _ := decodeExpression(r)
-
-		return &parser.ForStmt{
-			Var:   name,
-			Start: start,
-			End:   end,
-			Step:  step,
-		}, nil
-
-	case 0x04: // NEXT
-		name, _ := readString(r)
-		return &parser.NextStmt{
-			Var: name,
-		}, nil
-
-	default:
-		return nil, fmt.Errorf("decoder: unknown statement opcode 0x%X", op)
+	if version > constants.BasicVersion[basicType] {
+		return fmt.Errorf("⚠️ BASIC version mismatch (program %d, interpreter %d)",
+			version, constants.BasicVersion[basicType])
 	}
+	return nil
 }
 
-func decodeExpression(r io.Reader) (parser.Expression, error) {
-	op, err := readByte(r)
-	if err != nil {
-		return nil, err
-	}
-
-	switch op {
-
-	case 0x10: // Number
-		v, _ := readFloat64(r)
-		return &parser.NumberLiteral{Value: v}, nil
-
-	case 0x11: // String
-		s, _ := readString(r)
-		return &parser.StringLiteral{Value: s}, nil
-
-	case 0x12: // Ident
-		name, _ := readString(r)
-		return &parser.Identifier{Name: name}, nil
-
-	case 0x13: // Prefix
-		opStr, _ := readString(r)
-		right, _ := decodeExpression(r)
-		return &parser.PrefixExpr{
-			Op:    opStr,
-			Right: right,
-		}, nil
-
-	case 0x14: // Infix
-		opStr, _ := readString(r)
-		left, _ := decodeExpression(r)
-		right, _ := decodeExpression(r)
-		return &parser.InfixExpr{
-			Op:    opStr,
-			Left:  left,
-			Right: right,
-		}, nil
-
-	default:
-		return nil, fmt.Errorf("decoder: unknown expression opcode 0x%X", op)
-	}
+// isV1 indique si les données commencent par l'en-tête v1
+func isV1(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(MagicString))
 }
 
 func readByte(r io.Reader) (byte, error) {
@@ -241,32 +127,92 @@ func readString(r io.Reader) (string, error) {
 	return string(buf), err
 }
 
-func IsValidBasicsBinary(filename string) error {
+// readLongString lit une chaîne précédée de sa longueur sur 4 octets
+func readLongString(r *bytes.Reader) (string, error) {
+	l, err := readUint32(r)
+	if err != nil {
+		return "", err
+	}
+	if int64(l) > int64(r.Len()) {
+		return "", io.ErrUnexpectedEOF
+	}
+	buf := make([]byte, l)
+	_, err = io.ReadFull(r, buf)
+	return string(buf), err
+}
+
+// ReadHeader lit les champs communs de l'en-tête d'un fichier binaire
+// BASICS, v1 ou v2, sans le valider. CRC32 n'est renseigné que pour v1 :
+// en v2, chaque section a le sien.
+func ReadHeader(filename string) (Header, error) {
+	var header Header
+
 	f, err := os.Open(filename)
 	if err != nil {
-		return err
+		return header, err
 	}
 	defer f.Close()
 
-	var header Header
-	if err := binary.Read(f, binary.LittleEndian, &header); err != nil {
-		return err
+	var magic [4]byte
+	if _, err := io.ReadFull(f, magic[:]); err != nil {
+		return header, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return header, err
 	}
 
-	// Magic
-	if string(header.Magic[:]) != MagicString {
-		return fmt.Errorf("⚠️ invalid magic string")
+	if string(magic[:]) != MagicV2 {
+		err = binary.Read(f, binary.LittleEndian, &header)
+		return header, err
 	}
 
-	// BASIC type
-	if _, ok := constants.BasicName[header.BasicType]; !ok {
-		return fmt.Errorf("⚠️ unknown BASIC type")
+	var v2 HeaderV2
+	if err := binary.Read(f, binary.LittleEndian, &v2); err != nil {
+		return header, err
+	}
+
+	header.Magic = v2.Magic
+	header.BasicType = v2.BasicType
+	header.Version = v2.Version
+	header.NodeCount = v2.NodeCount
+	return header, nil
+}
+
+// IsValidBasicsBinary vérifie un fichier binaire BASICS, v1 ou v2, et
+// retourne l'état de sa signature. Un fichier signé dont la signature ne
+// correspond plus, ou dont un CRC32 est faux, est Tampered.
+func IsValidBasicsBinary(filename string) (Status, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return Unsigned, err
 	}
 
-	// Version
-	if constants.BasicVersion[header.BasicType] != header.Version {
-		return fmt.Errorf("⚠️ version mismatch")
+	c, err := Load(data)
+	if errors.Is(err, ErrChecksum) {
+		return Tampered, err
+	}
+	if err != nil {
+		return Unsigned, err
 	}
 
-	return nil
+	status := c.SignatureStatus()
+	if status == Tampered {
+		return Tampered, ErrTampered
+	}
+	return status, nil
+}
+
+// VerifyFile vérifie qu'un fichier binaire est signé par la clé privée
+// associée à la clé publique donnée
+func VerifyFile(filename string, pub ed25519.PublicKey) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	c, err := Load(data)
+	if err != nil {
+		return err
+	}
+	return c.Verify(pub)
 }
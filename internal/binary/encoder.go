@@ -1,297 +1,155 @@
 package binary
 
 import (
-	"bytes"
+	"crypto/ed25519"
 	"encoding/binary"
 	"fmt"
-	"hash/crc32"
 	"io"
 	"os"
+	"strings"
 
 	"basics/internal/constants"
 	"basics/internal/parser"
 )
 
-// EncodeProgram encode un AST complet dans un fichier binaire avec header + CRC32
-func EncodeProgram(prog *parser.Program, filename string, basicType byte) error {
-	outFile := filename[:len(filename)-4] + ".bin"
+// Options règle le contenu facultatif d'un conteneur v2
+type Options struct {
+	// Debug ajoute le source d'origine (section DBUG)
+	Debug      bool
+	SourceFile string
+	Source     string
 
-	// =========================
-	// Encoder AST en mémoire
-	// =========================
-	var astBuf bytes.Buffer
-
-	nodeCount := countNodes(prog)
+	// Scramble brouille le pool de constantes
+	Scramble bool
 
-	for _, line := range prog.Lines {
-		if err := encodeLine(line, &astBuf); err != nil {
-			return err
-		}
-	}
+	// SignKey, si présente, signe le fichier (section SIGN)
+	SignKey ed25519.PrivateKey
+}
 
-	// =========================
-	// Calcul CRC32 (AST uniquement)
-	// =========================
-	crc := crc32.ChecksumIEEE(astBuf.Bytes())
+// EncodeProgram encode un AST complet dans un fichier binaire v2
+func EncodeProgram(prog *parser.Program, filename string, basicType byte) error {
+	return EncodeProgramWithOptions(prog, filename, basicType, Options{})
+}
 
-	// =========================
-	// Construire le header
-	// =========================
-	var header Header
-	copy(header.Magic[:], MagicString)
-	header.BasicType = basicType
-	header.Version = constants.BasicVersion[basicType]
-	header.NodeCount = uint32(nodeCount)
-	header.CRC32 = crc
+// EncodeProgramWithOptions encode un AST dans un fichier binaire v2 avec
+// ses sections facultatives
+func EncodeProgramWithOptions(prog *parser.Program, filename string, basicType byte, opts Options) error {
+	outFile := filename[:len(filename)-4] + ".bin"
 
-	// =========================
-	// Écriture fichier final
-	// =========================
-	f, err := os.Create(outFile)
+	c, err := EncodeContainer(prog, basicType, opts)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
 
-	// Header
-	if err := binary.Write(f, binary.LittleEndian, &header); err != nil {
+	data, err := c.Bytes()
+	if err != nil {
 		return err
 	}
 
-	// AST
-	if _, err := f.Write(astBuf.Bytes()); err != nil {
+	// =========================
+	// Écriture fichier final
+	// =========================
+	if err := os.WriteFile(outFile, data, 0o644); err != nil {
 		return err
 	}
 
 	// =========================
 	// Affichage header
 	// =========================
-	info, err := os.Stat(outFile)
-	if err != nil {
-		return err
-	}
-
 	fmt.Printf("✅ BINARY FILE GENERATED: %s\n", outFile)
-	fmt.Printf("Magic      : %s\n", header.Magic)
+	fmt.Printf("Magic      : %s\n", c.Header.Magic)
+	fmt.Printf("Format     : %d\n", c.Header.Format)
 	fmt.Printf("Basic type : %s\n", constants.BasicName[basicType])
-	fmt.Printf("Version    : %d\n", constants.BasicVersion[basicType])
-	fmt.Printf("Nodes      : %d\n", nodeCount)
-	fmt.Printf("CRC32      : 0x%08X\n", crc)
-	fmt.Printf("File size  : %d bytes\n", info.Size())
+	fmt.Printf("Version    : %d\n", c.Header.Version)
+	fmt.Printf("Nodes      : %d\n", c.Header.NodeCount)
+	fmt.Printf("Features   : 0x%08X\n", c.Header.Features)
+	fmt.Printf("Sections   : %s\n", sectionTags(c))
+	fmt.Printf("Signature  : %s\n", c.SignatureStatus())
+	fmt.Printf("File size  : %d bytes\n", len(data))
 
 	return nil
 }
 
-// --- les autres fonctions encodeLine / encodeStatement / encodeExpression / writeByte / writeString restent identiques ---
+// EncodeContainer construit le conteneur v2 d'un programme : pool de
+// constantes, AST, table des symboles, table des lignes et, sur demande,
+// informations de débogage. Le pool est ensuite brouillé et le conteneur
+// signé si demandé.
+func EncodeContainer(prog *parser.Program, basicType byte, opts Options) (*Container, error) {
+	pool := NewPool()
+	ast := &astEncoder{pool: pool}
+
+	// =========================
+	// Encoder AST en mémoire
+	// =========================
+	var lineMap []LineEntry
 
-// countNodes parcourt récursivement l'AST pour compter tous les nodes
-func countNodes(prog *parser.Program) int {
-	count := 0
 	for _, line := range prog.Lines {
-		count++ // line
-		for _, stmt := range line.Stmts {
-			if stmt != nil {
-				count += countStatementNodes(stmt)
-			}
-		}
+		lineMap = append(lineMap, LineEntry{Line: line.Number, Offset: uint32(ast.buf.Len())})
+		ast.line(line)
 	}
-	return count
-}
-
-func countStatementNodes(stmt parser.Statement) int {
-	if stmt == nil {
-		return 0 // REM
+	if ast.err != nil {
+		return nil, ast.err
 	}
 
-	count := 1
-
-	switch s := stmt.(type) {
-	case *parser.LetStmt:
-		count += countExprNodes(s.Value)
-
-	case *parser.PrintStmt:
-		for _, e := range s.Exprs {
-			count += countExprNodes(e)
-		}
-
-	case *parser.ForStmt:
-		count += countExprNodes(s.Start)
-		count += countExprNodes(s.End)
-		if s.Step != nil {
-			count += countExprNodes(s.Step)
-		}
-
-	case *parser.NextStmt:
-		// rien
+	symbols, err := encodeSymbols(collectSymbols(prog), pool)
+	if err != nil {
+		return nil, err
 	}
 
-	return count
-}
+	// =========================
+	// Sections
+	// =========================
+	c := NewContainer(basicType, constants.BasicVersion[basicType])
+	c.Header.NodeCount = uint32(countNodes(prog))
 
-func countExprNodes(expr parser.Expression) int {
-	switch e := expr.(type) {
-	case *parser.NumberLiteral, *parser.StringLiteral, *parser.Identifier:
-		return 1
-	case *parser.PrefixExpr:
-		return 1 + countExprNodes(e.Right)
-	case *parser.InfixExpr:
-		return 1 + countExprNodes(e.Left) + countExprNodes(e.Right)
-	default:
-		return 0
-	}
-}
+	// le pool est complet une fois l'AST et les symboles encodés
+	c.AddSection(SectionPool, SectionRequired, FeaturePool, pool.Bytes())
+	c.AddSection(SectionAST, SectionRequired, 0, ast.buf.Bytes())
+	c.AddSection(SectionSymbols, 0, FeatureSymbols, symbols)
+	c.AddSection(SectionLineMap, 0, FeatureLineMap, encodeLineMap(lineMap))
+	c.AddSection(SectionSpans, 0, FeatureSpans, encodeSpans(prog))
 
-func encodeLine(line *parser.Line, w io.Writer) error {
-	if err := binary.Write(w, binary.LittleEndian, uint16(line.Number)); err != nil {
-		return err
+	if opts.Debug {
+		c.AddSection(SectionDebug, 0, FeatureDebug,
+			encodeStringList([]string{opts.SourceFile, opts.Source}))
 	}
 
-	// Compter uniquement les statements non-nil
-	count := uint16(0)
-	for _, stmt := range line.Stmts {
-		if stmt != nil {
-			count++
+	if opts.Scramble {
+		if err := Scramble(c); err != nil {
+			return nil, err
 		}
 	}
 
-	if err := binary.Write(w, binary.LittleEndian, count); err != nil {
-		return err
-	}
-
-	for _, stmt := range line.Stmts {
-		if stmt == nil {
-			// REM → ignoré
-			continue
-		}
-		if err := encodeStatement(stmt, w); err != nil {
-			return err
+	// la signature couvre tout le reste : elle est ajoutée en dernier
+	if opts.SignKey != nil {
+		if err := Sign(c, opts.SignKey); err != nil {
+			return nil, err
 		}
 	}
 
-	return nil
+	return c, nil
 }
 
-func encodeStatement(stmt parser.Statement, w io.Writer) error {
-	if stmt == nil {
-		// REM → ignoré
-		return nil
-	}
-
-	switch s := stmt.(type) {
-	case *parser.LetStmt:
-		if err := writeByte(w, 0x01); err != nil {
-			return err
-		}
-		if err := writeString(w, s.Name); err != nil {
-			return err
-		}
-		if err := encodeExpression(s.Value, w); err != nil {
-			return err
-		}
-
-	case *parser.PrintStmt:
-		if err := writeByte(w, 0x02); err != nil {
-			return err
-		}
-		exprCount := uint16(len(s.Exprs))
-		if err := binary.Write(w, binary.LittleEndian, exprCount); err != nil {
-			return err
-		}
-		for _, e := range s.Exprs {
-			if err := encodeExpression(e, w); err != nil {
-				return err
-			}
-		}
-
-	case *parser.ForStmt:
-		if err := writeByte(w, 0x03); err != nil {
-			return err
-		}
-		if err := writeString(w, s.Var); err != nil {
-			return err
-		}
-		if err := encodeExpression(s.Start, w); err != nil {
-			return err
-		}
-		if err := encodeExpression(s.End, w); err != nil {
-			return err
-		}
-		step := s.Step
-		if step == nil {
-			step = &parser.NumberLiteral{Value: 1}
-		}
-		if err := encodeExpression(step, w); err != nil {
-			return err
-		}
-
-	case *parser.NextStmt:
-		if err := writeByte(w, 0x04); err != nil {
-			return err
-		}
-		if err := writeString(w, s.Var); err != nil {
-			return err
-		}
-
-	default:
-		return fmt.Errorf("encoder: statement not supported %T", stmt)
+// sectionTags retourne la liste des étiquettes pour l'affichage
+func sectionTags(c *Container) string {
+	tags := make([]string, len(c.Sections))
+	for i, s := range c.Sections {
+		tags[i] = strings.TrimSpace(s.Tag)
 	}
-	return nil
+	return strings.Join(tags, ", ")
 }
 
-func encodeExpression(expr parser.Expression, w io.Writer) error {
-	switch e := expr.(type) {
-	case *parser.NumberLiteral:
-		if err := writeByte(w, 0x10); err != nil {
-			return err
-		}
-		if err := binary.Write(w, binary.LittleEndian, e.Value); err != nil {
-			return err
-		}
-	case *parser.StringLiteral:
-		if err := writeByte(w, 0x11); err != nil {
-			return err
-		}
-		if err := writeString(w, e.Value); err != nil {
-			return err
-		}
-	case *parser.Identifier:
-		if err := writeByte(w, 0x12); err != nil {
-			return err
-		}
-		if err := writeString(w, e.Name); err != nil {
-			return err
-		}
-	case *parser.PrefixExpr:
-		if err := writeByte(w, 0x13); err != nil {
-			return err
-		}
-		if err := writeString(w, e.Op); err != nil {
-			return err
-		}
-		if err := encodeExpression(e.Right, w); err != nil {
-			return err
-		}
-	case *parser.InfixExpr:
-		if err := writeByte(w, 0x14); err != nil {
-			return err
-		}
-		if err := writeString(w, e.Op); err != nil {
-			return err
-		}
-		if err := encodeExpression(e.Left, w); err != nil {
-			return err
-		}
-		if err := encodeExpression(e.Right, w); err != nil {
-			return err
+// countNodes compte les nœuds de l'AST : lignes, instructions et
+// expressions
+func countNodes(prog *parser.Program) int {
+	count := 0
+	parser.Inspect(prog, func(node any) bool {
+		if _, ok := node.(*parser.Program); !ok {
+			count++
 		}
-	default:
-		return fmt.Errorf("encoder: expression not supported %T", expr)
-	}
-	return nil
-}
-
-func writeByte(w io.Writer, b byte) error {
-	return binary.Write(w, binary.LittleEndian, b)
+		return true
+	})
+	return count
 }
 
 func writeString(w io.Writer, s string) error {
@@ -302,3 +160,12 @@ func writeString(w io.Writer, s string) error {
 	_, err := w.Write([]byte(s))
 	return err
 }
+
+// writeLongString écrit une chaîne précédée de sa longueur sur 4 octets
+func writeLongString(w io.Writer, s string) error {
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(s))); err != nil {
+		return err
+	}
+	_, err := w.Write([]byte(s))
+	return err
+}
@@ -1,7 +1,9 @@
 package binary
 
+// MagicString identifie un fichier binaire BASICS v1
 const MagicString = "BASC"
 
+// Header est l'en-tête d'un fichier v1 : l'AST suit directement
 type Header struct {
 	Magic     [4]byte // "BASC"
 	BasicType byte
@@ -9,3 +11,41 @@ type Header struct {
 	NodeCount uint32
 	CRC32     uint32
 }
+
+// MagicV2 identifie un conteneur binaire BASICS v2
+const MagicV2 = "BASX"
+
+// FormatV2 est la version du format de conteneur écrite par cet encodeur
+const FormatV2 = 2
+
+// HeaderV2 est l'en-tête d'un conteneur v2, suivi de SectionCount sections
+type HeaderV2 struct {
+	Magic        [4]byte // "BASX"
+	Format       byte    // version du format de conteneur
+	BasicType    byte
+	Version      byte   // version du BASIC (constants.BasicVersion)
+	Reserved     byte   // 0
+	Features     uint32 // fonctionnalités présentes dans le fichier
+	Required     uint32 // fonctionnalités indispensables à la lecture
+	NodeCount    uint32
+	SectionCount uint16
+	Reserved2    uint16 // 0
+}
+
+// Fonctionnalités d'un conteneur v2. Un lecteur refuse un fichier dont
+// Required contient une fonctionnalité inconnue ; les autres sont
+// ignorées.
+const (
+	FeaturePool      uint32 = 1 << iota // l'AST référence le pool de constantes
+	FeatureSymbols                      // table des symboles
+	FeatureLineMap                      // table des lignes
+	FeatureData                         // pool des DATA
+	FeatureDebug                        // informations de débogage
+	FeatureSignature                    // signature Ed25519
+	FeatureScrambled                    // pool de constantes brouillé
+	FeatureSpans                        // positions des nœuds
+
+	// KnownFeatures regroupe les fonctionnalités comprises par ce lecteur
+	KnownFeatures = FeaturePool | FeatureSymbols | FeatureLineMap | FeatureData | FeatureDebug |
+		FeatureSignature | FeatureScrambled | FeatureSpans
+)
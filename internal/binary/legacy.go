@@ -0,0 +1,173 @@
+package binary
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"basics/internal/parser"
+)
+
+// Décodeur du format v1, figé : seules les instructions LET, PRINT, FOR
+// et NEXT y étaient écrites, avec les chaînes et les nombres en ligne et
+// sans position dans le source.
+
+// decodeV1Lines lit les lignes jusqu'à la fin des données
+func decodeV1Lines(data []byte) (*parser.Program, error) {
+	r := bytes.NewReader(data)
+	prog := &parser.Program{}
+
+	for r.Len() > 0 {
+		line, err := decodeV1Line(r)
+		if err != nil {
+			return nil, err
+		}
+		prog.Lines = append(prog.Lines, line)
+	}
+
+	return prog, nil
+}
+
+func decodeV1Line(r io.Reader) (*parser.Line, error) {
+	lineNum, err := readUint16(r)
+	if err != nil {
+		return nil, errTruncated(err)
+	}
+
+	stmtCount, err := readUint16(r)
+	if err != nil {
+		return nil, errTruncated(err)
+	}
+
+	line := &parser.Line{
+		Number: int(lineNum),
+	}
+
+	for i := 0; i < int(stmtCount); i++ {
+		stmt, err := decodeV1Statement(r)
+		if err != nil {
+			return nil, errTruncated(err)
+		}
+		line.Stmts = append(line.Stmts, stmt)
+	}
+
+	return line, nil
+}
+
+func decodeV1Statement(r io.Reader) (parser.Statement, error) {
+	op, err := readByte(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+
+	case opLet:
+		name, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+		val, err := decodeV1Expression(r, 0)
+		if err != nil {
+			return nil, err
+		}
+		return &parser.LetStmt{Name: name, Value: val}, nil
+
+	case opPrint:
+		n, err := readUint16(r)
+		if err != nil {
+			return nil, err
+		}
+		var exprs []parser.Expression
+		for i := 0; i < int(n); i++ {
+			e, err := decodeV1Expression(r, 0)
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, e)
+		}
+		return &parser.PrintStmt{Exprs: exprs}, nil
+
+	case opFor:
+		s := &parser.ForStmt{}
+		if s.Var, err = readString(r); err != nil {
+			return nil, err
+		}
+		for _, e := range []*parser.Expression{&s.Start, &s.End, &s.Step} {
+			if *e, err = decodeV1Expression(r, 0); err != nil {
+				return nil, err
+			}
+		}
+		return s, nil
+
+	case opNext:
+		name, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+		return &parser.NextStmt{Var: name}, nil
+
+	default:
+		return nil, fmt.Errorf("decoder: unknown statement opcode 0x%X", op)
+	}
+}
+
+func decodeV1Expression(r io.Reader, depth int) (parser.Expression, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("decoder: expressions nested too deeply")
+	}
+
+	op, err := readByte(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+
+	case opNumber:
+		v, err := readFloat64(r)
+		if err != nil {
+			return nil, err
+		}
+		return &parser.NumberLiteral{Value: v}, nil
+
+	case opString, opIdent:
+		s, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+		if op == opString {
+			return &parser.StringLiteral{Value: s}, nil
+		}
+		return &parser.Identifier{Name: s}, nil
+
+	case opPrefix:
+		opStr, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+		right, err := decodeV1Expression(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return &parser.PrefixExpr{Op: opStr, Right: right}, nil
+
+	case opInfix:
+		opStr, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+		left, err := decodeV1Expression(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		right, err := decodeV1Expression(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return &parser.InfixExpr{Op: opStr, Left: left, Right: right}, nil
+
+	default:
+		return nil, fmt.Errorf("decoder: unknown expression opcode 0x%X", op)
+	}
+}
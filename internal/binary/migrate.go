@@ -0,0 +1,100 @@
+package binary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+
+	"basics/internal/constants"
+	"basics/internal/parser"
+)
+
+// decodeV1 décode un fichier v1 : en-tête fixe, puis AST avec chaînes et
+// nombres en ligne. verbose affiche l'en-tête.
+func decodeV1(data []byte, verbose bool) (*parser.Program, error) {
+	r := bytes.NewReader(data)
+
+	// =========================
+	// 1️⃣ Lire le HEADER
+	// =========================
+	var header Header
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+
+	// Magic
+	if string(header.Magic[:]) != MagicString {
+		return nil, fmt.Errorf("⚠️ invalid magic string")
+	}
+
+	// BASIC type et version
+	if err := checkBasic(header.BasicType, header.Version); err != nil {
+		return nil, err
+	}
+
+	// =========================
+	// 2️⃣ Vérifier CRC32
+	// =========================
+	astData := data[len(data)-r.Len():]
+
+	crc := crc32.ChecksumIEEE(astData)
+	if crc != header.CRC32 {
+		return nil, fmt.Errorf("%w (expected 0x%08X, got 0x%08X)",
+			ErrChecksum, header.CRC32, crc)
+	}
+
+	// =========================
+	// 3️⃣ Décoder l’AST
+	// =========================
+	prog, err := decodeV1Lines(astData)
+	if err != nil {
+		return nil, err
+	}
+
+	// =========================
+	// 4️⃣ Infos header
+	// =========================
+	if verbose {
+		fmt.Println("📦 BINARY HEADER (v1)")
+		fmt.Printf("Magic     : %s\n", header.Magic)
+		fmt.Printf("Basic type: %s\n", constants.BasicName[header.BasicType])
+		fmt.Printf("Version   : %d\n", header.Version)
+		fmt.Printf("Nodes     : %d\n", header.NodeCount)
+		fmt.Printf("CRC32     : 0x%08X\n\n", header.CRC32)
+	}
+
+	return prog, nil
+}
+
+// migrateV1 convertit un fichier v1 en conteneur v2
+func migrateV1(data []byte) (*Container, error) {
+	prog, err := decodeV1(data, false)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := EncodeContainer(prog, data[4], Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	// la version du BASIC d'origine est conservée
+	c.Header.Version = data[5]
+	return c, nil
+}
+
+// Migrate convertit un fichier v1 au format v2. Un fichier déjà au
+// format v2 est retourné tel quel après vérification.
+func Migrate(data []byte) ([]byte, error) {
+	c, err := Load(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isV1(data) {
+		return data, nil
+	}
+
+	return c.Bytes()
+}
@@ -0,0 +1,138 @@
+package binary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// maxPoolEntries : les références au pool sont écrites sur 2 octets
+const maxPoolEntries = math.MaxUint16 + 1
+
+// Pool est le pool de constantes d'un conteneur v2 : noms, opérateurs et
+// chaînes littérales d'un côté, constantes numériques de l'autre. Chaque
+// valeur n'y figure qu'une fois.
+type Pool struct {
+	Strings []string
+	Numbers []float64
+
+	stringIndex map[string]int
+	numberIndex map[uint64]int
+}
+
+// NewPool retourne un pool vide
+func NewPool() *Pool {
+	return &Pool{
+		stringIndex: map[string]int{},
+		numberIndex: map[uint64]int{},
+	}
+}
+
+// AddString retourne l'indice de la chaîne, ajoutée si besoin
+func (p *Pool) AddString(s string) (uint16, error) {
+	if i, ok := p.stringIndex[s]; ok {
+		return uint16(i), nil
+	}
+	if len(p.Strings) >= maxPoolEntries {
+		return 0, fmt.Errorf("string pool overflow")
+	}
+
+	p.stringIndex[s] = len(p.Strings)
+	p.Strings = append(p.Strings, s)
+	return uint16(len(p.Strings) - 1), nil
+}
+
+// AddNumber retourne l'indice de la constante, ajoutée si besoin
+func (p *Pool) AddNumber(v float64) (uint16, error) {
+	bits := math.Float64bits(v)
+	if i, ok := p.numberIndex[bits]; ok {
+		return uint16(i), nil
+	}
+	if len(p.Numbers) >= maxPoolEntries {
+		return 0, fmt.Errorf("constant pool overflow")
+	}
+
+	p.numberIndex[bits] = len(p.Numbers)
+	p.Numbers = append(p.Numbers, v)
+	return uint16(len(p.Numbers) - 1), nil
+}
+
+// String retourne la chaîne d'indice i
+func (p *Pool) String(i uint16) (string, error) {
+	if int(i) >= len(p.Strings) {
+		return "", fmt.Errorf("string pool index %d out of range", i)
+	}
+	return p.Strings[i], nil
+}
+
+// Number retourne la constante d'indice i
+func (p *Pool) Number(i uint16) (float64, error) {
+	if int(i) >= len(p.Numbers) {
+		return 0, fmt.Errorf("constant pool index %d out of range", i)
+	}
+	return p.Numbers[i], nil
+}
+
+// Bytes sérialise le pool : nombre de chaînes, chaînes, nombre de
+// constantes, constantes
+func (p *Pool) Bytes() []byte {
+	var buf bytes.Buffer
+
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(p.Strings)))
+	for _, s := range p.Strings {
+		_ = writeString(&buf, s)
+	}
+
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(p.Numbers)))
+	for _, v := range p.Numbers {
+		_ = binary.Write(&buf, binary.LittleEndian, v)
+	}
+
+	return buf.Bytes()
+}
+
+// parsePool lit une section POOL
+func parsePool(data []byte) (*Pool, error) {
+	r := bytes.NewReader(data)
+	p := NewPool()
+
+	count, err := readUint32(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < count; i++ {
+		s, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+		p.Strings = append(p.Strings, s)
+	}
+
+	count, err = readUint32(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < count; i++ {
+		v, err := readFloat64(r)
+		if err != nil {
+			return nil, err
+		}
+		p.Numbers = append(p.Numbers, v)
+	}
+
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d unexpected bytes after pool", r.Len())
+	}
+
+	return p, nil
+}
+
+// errTruncated remplace io.EOF au milieu d'une structure
+func errTruncated(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package binary
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+)
+
+// seedSize est la taille de la graine placée devant une section brouillée
+const seedSize = 8
+
+// sections brouillées : les chaînes du programme et son source
+var scrambledSections = []string{SectionPool, SectionDebug}
+
+// Scramble brouille les sections POOL et DBUG pour que les chaînes du
+// programme ne soient pas lisibles dans le fichier. Ce n'est pas un
+// chiffrement : la graine est stockée dans chaque section. Un lecteur qui
+// ne connaît pas FeatureScrambled refuse le fichier.
+func Scramble(c *Container) error {
+	if c.Header.Features&FeatureScrambled != 0 {
+		return nil
+	}
+
+	for _, tag := range scrambledSections {
+		s := c.Section(tag)
+		if s == nil {
+			continue
+		}
+
+		seed := make([]byte, seedSize)
+		if _, err := rand.Read(seed); err != nil {
+			return err
+		}
+		s.Data = append(seed, xorKeystream(seed, s.Data)...)
+	}
+
+	c.Header.Features |= FeatureScrambled
+	c.Header.Required |= FeatureScrambled
+	return nil
+}
+
+// sectionData retourne le contenu d'origine d'une section, éventuellement
+// brouillée
+func (c *Container) sectionData(s *Section) ([]byte, error) {
+	if c.Header.Features&FeatureScrambled == 0 {
+		return s.Data, nil
+	}
+	for _, tag := range scrambledSections {
+		if s.Tag == tag {
+			return unscramble(s.Data)
+		}
+	}
+	return s.Data, nil
+}
+
+// unscramble retourne le contenu d'origine d'une section brouillée
+func unscramble(data []byte) ([]byte, error) {
+	if len(data) < seedSize {
+		return nil, fmt.Errorf("scrambled section too short")
+	}
+	return xorKeystream(data[:seedSize], data[seedSize:]), nil
+}
+
+// xorKeystream combine les données avec SHA-256(graine, compteur)
+func xorKeystream(seed, data []byte) []byte {
+	out := make([]byte, len(data))
+
+	var block [sha256.Size]byte
+	for i := range data {
+		if i%sha256.Size == 0 {
+			input := binary.LittleEndian.AppendUint64(append([]byte(nil), seed...), uint64(i/sha256.Size))
+			block = sha256.Sum256(input)
+		}
+		out[i] = data[i] ^ block[i%sha256.Size]
+	}
+
+	return out
+}
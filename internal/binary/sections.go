@@ -0,0 +1,356 @@
+package binary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+
+	"basics/internal/parser"
+)
+
+// SymbolKind est le type d'une variable, donné par son suffixe
+type SymbolKind byte
+
+const (
+	SymbolReal    SymbolKind = iota // A
+	SymbolInteger                   // A%
+	SymbolString                    // A$
+)
+
+// Symbol est une variable du programme
+type Symbol struct {
+	Name string
+	Kind SymbolKind
+}
+
+// LineEntry associe un numéro de ligne à sa position dans la section AST
+type LineEntry struct {
+	Line   int
+	Offset uint32
+}
+
+// DebugInfo conserve le source d'origine du programme
+type DebugInfo struct {
+	SourceFile string
+	Source     string
+}
+
+// =========================
+// Table des symboles
+// =========================
+
+// collectSymbols retourne les variables du programme, triées par nom
+func collectSymbols(prog *parser.Program) []Symbol {
+	seen := map[string]bool{}
+
+	add := func(name string) {
+		if name != "" {
+			seen[name] = true
+		}
+	}
+
+	parser.Inspect(prog, func(node any) bool {
+		switch n := node.(type) {
+		case *parser.LetStmt:
+			add(n.Name)
+		case *parser.ForStmt:
+			add(n.Var)
+		case *parser.NextStmt:
+			add(n.Var)
+		case *parser.Identifier:
+			add(n.Name)
+		}
+		return true
+	})
+
+	symbols := make([]Symbol, 0, len(seen))
+	for name := range seen {
+		kind := SymbolReal
+		switch {
+		case strings.HasSuffix(name, "$"):
+			kind = SymbolString
+		case strings.HasSuffix(name, "%"):
+			kind = SymbolInteger
+		}
+		symbols = append(symbols, Symbol{Name: name, Kind: kind})
+	}
+
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i].Name < symbols[j].Name })
+	return symbols
+}
+
+// encodeSymbols écrit la table des symboles : indice du nom dans le pool
+// et type de chaque variable
+func encodeSymbols(symbols []Symbol, pool *Pool) ([]byte, error) {
+	var buf bytes.Buffer
+
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(len(symbols)))
+	for _, s := range symbols {
+		i, err := pool.AddString(s.Name)
+		if err != nil {
+			return nil, err
+		}
+		_ = binary.Write(&buf, binary.LittleEndian, i)
+		buf.WriteByte(byte(s.Kind))
+	}
+
+	return buf.Bytes(), nil
+}
+
+func parseSymbols(data []byte, pool *Pool) ([]Symbol, error) {
+	r := bytes.NewReader(data)
+
+	count, err := readUint16(r)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := make([]Symbol, 0, count)
+	for i := 0; i < int(count); i++ {
+		index, err := readUint16(r)
+		if err != nil {
+			return nil, errTruncated(err)
+		}
+		kind, err := readByte(r)
+		if err != nil {
+			return nil, errTruncated(err)
+		}
+		name, err := pool.String(index)
+		if err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, Symbol{Name: name, Kind: SymbolKind(kind)})
+	}
+
+	return symbols, nil
+}
+
+// =========================
+// Table des lignes
+// =========================
+
+func encodeLineMap(entries []LineEntry) []byte {
+	var buf bytes.Buffer
+
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(entries)))
+	for _, e := range entries {
+		_ = binary.Write(&buf, binary.LittleEndian, uint16(e.Line))
+		_ = binary.Write(&buf, binary.LittleEndian, e.Offset)
+	}
+
+	return buf.Bytes()
+}
+
+// =========================
+// Positions des nœuds
+// =========================
+
+// spannedNodes retourne les nœuds qui ont une position, dans l'ordre de
+// parser.Inspect
+func spannedNodes(prog *parser.Program) []parser.Spanned {
+	var nodes []parser.Spanned
+	parser.Inspect(prog, func(node any) bool {
+		if n, ok := node.(parser.Spanned); ok {
+			nodes = append(nodes, n)
+		}
+		return true
+	})
+	return nodes
+}
+
+// encodeSpans écrit la position de chaque nœud : ligne et colonne de
+// début et de fin
+func encodeSpans(prog *parser.Program) []byte {
+	var buf bytes.Buffer
+
+	nodes := spannedNodes(prog)
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(nodes)))
+	for _, n := range nodes {
+		r := n.Range()
+		for _, v := range []int{r.Start.Line, r.Start.Column, r.End.Line, r.End.Column} {
+			_ = binary.Write(&buf, binary.LittleEndian, uint16(v))
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// applySpans rend leur position aux nœuds d'un programme décodé
+func applySpans(prog *parser.Program, data []byte) error {
+	r := bytes.NewReader(data)
+
+	count, err := readUint32(r)
+	if err != nil {
+		return err
+	}
+	nodes := spannedNodes(prog)
+	if int(count) != len(nodes) || int64(count)*8 > int64(r.Len()) {
+		return fmt.Errorf("%d positions for %d nodes", count, len(nodes))
+	}
+
+	for _, n := range nodes {
+		var v [4]uint16
+		_ = binary.Read(r, binary.LittleEndian, &v)
+		n.SetRange(parser.Span{
+			Start: parser.Position{Line: int(v[0]), Column: int(v[1])},
+			End:   parser.Position{Line: int(v[2]), Column: int(v[3])},
+		})
+	}
+	return nil
+}
+
+func parseLineMap(data []byte) ([]LineEntry, error) {
+	r := bytes.NewReader(data)
+
+	count, err := readUint32(r)
+	if err != nil {
+		return nil, err
+	}
+	if int64(count)*6 > int64(r.Len()) {
+		return nil, fmt.Errorf("truncated line map")
+	}
+
+	entries := make([]LineEntry, 0, count)
+	for i := 0; i < int(count); i++ {
+		line, _ := readUint16(r)
+		offset, _ := readUint32(r)
+		entries = append(entries, LineEntry{Line: int(line), Offset: offset})
+	}
+
+	return entries, nil
+}
+
+// =========================
+// Listes de chaînes (DATA, débogage)
+// =========================
+
+func encodeStringList(values []string) []byte {
+	var buf bytes.Buffer
+
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(values)))
+	for _, v := range values {
+		_ = writeLongString(&buf, v)
+	}
+
+	return buf.Bytes()
+}
+
+func parseStringList(data []byte) ([]string, error) {
+	r := bytes.NewReader(data)
+
+	count, err := readUint32(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for i := uint32(0); i < count; i++ {
+		v, err := readLongString(r)
+		if err != nil {
+			return nil, errTruncated(err)
+		}
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// =========================
+// Accès aux sections
+// =========================
+
+// Pool retourne le pool de constantes du conteneur
+func (c *Container) Pool() (*Pool, error) {
+	s := c.Section(SectionPool)
+	if s == nil {
+		return NewPool(), nil
+	}
+
+	data, err := c.sectionData(s)
+	if err != nil {
+		return nil, err
+	}
+	return parsePool(data)
+}
+
+// Program décode l'AST du conteneur
+func (c *Container) Program() (*parser.Program, error) {
+	s := c.Section(SectionAST)
+	if s == nil {
+		return nil, fmt.Errorf("⚠️ missing %q section", SectionAST)
+	}
+
+	pool, err := c.Pool()
+	if err != nil {
+		return nil, fmt.Errorf("⚠️ invalid %q section: %w", SectionPool, err)
+	}
+
+	d := &astDecoder{r: bytes.NewReader(s.Data), pool: pool}
+	prog, err := d.lines()
+	if err != nil {
+		return nil, err
+	}
+
+	if s := c.Section(SectionSpans); s != nil {
+		if err := applySpans(prog, s.Data); err != nil {
+			return nil, fmt.Errorf("⚠️ invalid %q section: %w", SectionSpans, err)
+		}
+	}
+	return prog, nil
+}
+
+// Symbols retourne la table des symboles (nil si absente)
+func (c *Container) Symbols() ([]Symbol, error) {
+	s := c.Section(SectionSymbols)
+	if s == nil {
+		return nil, nil
+	}
+
+	pool, err := c.Pool()
+	if err != nil {
+		return nil, err
+	}
+	return parseSymbols(s.Data, pool)
+}
+
+// LineMap retourne la table des lignes (nil si absente)
+func (c *Container) LineMap() ([]LineEntry, error) {
+	s := c.Section(SectionLineMap)
+	if s == nil {
+		return nil, nil
+	}
+	return parseLineMap(s.Data)
+}
+
+// Data retourne les valeurs des instructions DATA (nil si absentes)
+func (c *Container) Data() ([]string, error) {
+	s := c.Section(SectionData)
+	if s == nil {
+		return nil, nil
+	}
+	return parseStringList(s.Data)
+}
+
+// Debug retourne les informations de débogage (nil si absentes)
+func (c *Container) Debug() (*DebugInfo, error) {
+	s := c.Section(SectionDebug)
+	if s == nil {
+		return nil, nil
+	}
+
+	data, err := c.sectionData(s)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := parseStringList(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 2 {
+		return nil, fmt.Errorf("invalid %q section", SectionDebug)
+	}
+	return &DebugInfo{SourceFile: values[0], Source: values[1]}, nil
+}
@@ -0,0 +1,206 @@
+package binary
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// SectionSign contient la clé publique et la signature Ed25519 du fichier
+const SectionSign = "SIGN"
+
+// Status est l'état de la signature d'un fichier binaire
+type Status int
+
+const (
+	Unsigned Status = iota // aucune signature
+	Signed                 // signature valide
+	Tampered               // signature invalide ou données altérées
+)
+
+func (s Status) String() string {
+	switch s {
+	case Signed:
+		return "signed"
+	case Tampered:
+		return "tampered"
+	default:
+		return "unsigned"
+	}
+}
+
+// ErrTampered signale un fichier modifié après sa compilation
+var ErrTampered = errors.New("binary program has been tampered with")
+
+// Sign ajoute la section SIGN : clé publique puis signature de tout le
+// conteneur, section SIGN exclue. Toute autre modification du conteneur
+// doit être faite avant.
+func Sign(c *Container, key ed25519.PrivateKey) error {
+	c.removeSection(SectionSign)
+	c.Header.Features |= FeatureSignature
+
+	message, err := signedMessage(c)
+	if err != nil {
+		return err
+	}
+
+	data := append([]byte(nil), key.Public().(ed25519.PublicKey)...)
+	data = append(data, ed25519.Sign(key, message)...)
+	c.AddSection(SectionSign, 0, FeatureSignature, data)
+	return nil
+}
+
+// SignatureStatus vérifie la signature avec la clé publique qu'elle
+// contient
+func (c *Container) SignatureStatus() Status {
+	s := c.Section(SectionSign)
+	if s == nil {
+		if c.Header.Features&FeatureSignature != 0 {
+			return Tampered // signature retirée
+		}
+		return Unsigned
+	}
+
+	if len(s.Data) != ed25519.PublicKeySize+ed25519.SignatureSize {
+		return Tampered
+	}
+	if c.verify(ed25519.PublicKey(s.Data[:ed25519.PublicKeySize])) != nil {
+		return Tampered
+	}
+	return Signed
+}
+
+// Verify vérifie que le conteneur est signé par la clé privée associée à
+// la clé publique donnée
+func (c *Container) Verify(pub ed25519.PublicKey) error {
+	if c.Section(SectionSign) == nil {
+		return fmt.Errorf("binary program is not signed")
+	}
+	return c.verify(pub)
+}
+
+func (c *Container) verify(pub ed25519.PublicKey) error {
+	s := c.Section(SectionSign)
+	if len(s.Data) != ed25519.PublicKeySize+ed25519.SignatureSize {
+		return ErrTampered
+	}
+
+	message, err := signedMessage(c)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(pub, message, s.Data[ed25519.PublicKeySize:]) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// signedMessage sérialise le conteneur sans sa section SIGN
+func signedMessage(c *Container) ([]byte, error) {
+	unsigned := &Container{Header: c.Header}
+	for _, s := range c.Sections {
+		if s.Tag != SectionSign {
+			unsigned.Sections = append(unsigned.Sections, s)
+		}
+	}
+	return unsigned.Bytes()
+}
+
+// removeSection retire une section du conteneur
+func (c *Container) removeSection(tag string) {
+	kept := c.Sections[:0]
+	for _, s := range c.Sections {
+		if s.Tag != tag {
+			kept = append(kept, s)
+		}
+	}
+	c.Sections = kept
+	c.Header.SectionCount = uint16(len(kept))
+}
+
+// =========================
+// Fichiers de clés (PEM)
+// =========================
+
+// GenerateKeyFiles crée une paire de clés Ed25519 : base.key (privée,
+// PKCS#8) et base.pub (publique, PKIX)
+func GenerateKeyFiles(base string) error {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return err
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return err
+	}
+
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})
+	if err := os.WriteFile(base+".key", privPEM, 0o600); err != nil {
+		return err
+	}
+
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+	return os.WriteFile(base+".pub", pubPEM, 0o644)
+}
+
+// LoadPrivateKey lit une clé privée Ed25519 au format PEM (PKCS#8)
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	der, err := readPEM(path, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an Ed25519 private key", path)
+	}
+	return priv, nil
+}
+
+// LoadPublicKey lit une clé publique Ed25519 au format PEM (PKIX)
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	der, err := readPEM(path, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an Ed25519 public key", path)
+	}
+	return pub, nil
+}
+
+func readPEM(path, blockType string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(bytes.TrimSpace(data))
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("%s: %s PEM block expected", path, blockType)
+	}
+	return block.Bytes, nil
+}
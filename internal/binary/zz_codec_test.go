@@ -0,0 +1,174 @@
+package binary
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"basics/internal/constants"
+	"basics/internal/dialect"
+	"basics/internal/lexer"
+	"basics/internal/parser"
+	"basics/testutils"
+)
+
+// roundTrip encode puis décode un programme via un conteneur v2 complet
+func roundTrip(t *testing.T, prog *parser.Program, basicType byte) (*parser.Program, error) {
+	t.Helper()
+
+	c, err := EncodeContainer(prog, basicType, Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	read, err := Load(encodeBytes(t, c))
+	if err != nil {
+		return nil, err
+	}
+	return read.Program()
+}
+
+// Decode(Encode(p)) == p pour chaque exemple, positions comprises
+func TestRoundTrip_Examples(t *testing.T) {
+	files, err := filepath.Glob("../../examples/*/*.bas")
+	testutils.True(t, "examples found", err == nil && len(files) > 0)
+
+	more, _ := filepath.Glob("../../examples/*/*/*.bas")
+	files = append(files, more...)
+
+	checked := 0
+	for _, file := range files {
+		d, basicType := dialect.Applesoft, constants.BASIC_APPLE
+		if strings.Contains(filepath.ToSlash(file), "/cpc/") {
+			d, basicType = dialect.Locomotive, constants.BASIC_AMS
+		}
+
+		src, err := os.ReadFile(file)
+		testutils.True(t, "read "+file, err == nil)
+
+		tokens, err := lexer.Scan(string(src), d)
+		if err != nil {
+			continue
+		}
+		prog, errs := parser.NewWithDialect(tokens, d).ParseProgram()
+		if len(errs) > 0 {
+			continue // exemples d'erreurs de syntaxe
+		}
+
+		decoded, err := roundTrip(t, prog, basicType)
+		testutils.True(t, fmt.Sprintf("%s: %v", file, err), err == nil)
+		testutils.True(t, file+" unchanged", reflect.DeepEqual(decoded, prog))
+		checked++
+	}
+
+	testutils.True(t, fmt.Sprintf("%d examples checked", checked), checked > 60)
+}
+
+func TestRoundTrip_AllNodes(t *testing.T) {
+	src := `10 HOME : INPUT "NAME"; N$, A : GET K$
+20 HTAB 5 : VTAB INT(A) + ABS(-2) * SGN(A)
+30 IF A > 1 THEN PRINT "BIG"; A, : GOTO 50
+40 IF A THEN 60 ELSE GOSUB 100
+50 REM COMMENT
+60 END
+100 RETURN
+`
+	tokens, err := lexer.Scan(src, dialect.Applesoft)
+	testutils.True(t, "lexed", err == nil)
+	prog, errs := parser.New(tokens).ParseProgram()
+	testutils.Equal(t, "parsed", len(errs), 0)
+
+	decoded, err := roundTrip(t, prog, constants.BASIC_APPLE)
+	testutils.True(t, fmt.Sprintf("no error: %v", err), err == nil)
+	testutils.True(t, "unchanged", reflect.DeepEqual(decoded, prog))
+}
+
+func TestRoundTrip_CPCAndJumps(t *testing.T) {
+	prog := &parser.Program{Lines: []*parser.Line{
+		{Number: 10, Stmts: []parser.Statement{
+			&parser.ModeStmt{Expr: &parser.NumberLiteral{Value: 1, Token: "1"}},
+			&parser.ClsStmt{},
+			&parser.LocateStmt{X: &parser.NumberLiteral{Value: 2}, Y: &parser.NumberLiteral{Value: 3}},
+			&parser.InkStmt{Ink: &parser.NumberLiteral{Value: 1}, Color1: &parser.NumberLiteral{Value: 24}},
+			&parser.PenStmt{Expr: &parser.NumberLiteral{Value: 1}},
+			&parser.PaperStmt{Expr: &parser.NumberLiteral{Value: 0}},
+			&parser.BorderStmt{Color1: &parser.NumberLiteral{Value: 6}, Color2: &parser.NumberLiteral{Value: 9}},
+			&parser.PlotStmt{X: &parser.NumberLiteral{Value: 0}, Y: &parser.NumberLiteral{Value: 0}},
+			&parser.DrawStmt{X: &parser.NumberLiteral{Value: 639}, Y: &parser.NumberLiteral{Value: 399}, Ink: &parser.NumberLiteral{Value: 2}},
+			&parser.IfJumpStmt{Cond: &parser.Identifier{Name: "A"}, Target: 70000},
+			&parser.IfStmt{Cond: &parser.Identifier{Name: "A"}, Then: []parser.Statement{nil}, Else: []parser.Statement{}},
+		}},
+	}}
+
+	decoded, err := roundTrip(t, prog, constants.BASIC_AMS)
+	testutils.True(t, fmt.Sprintf("no error: %v", err), err == nil)
+	testutils.True(t, "unchanged", reflect.DeepEqual(decoded, prog))
+}
+
+// unknownStmt et unknownExpr ne sont connus d'aucun codec
+type unknownStmt struct{ parser.Statement }
+type unknownExpr struct{ parser.Expression }
+
+func TestEncode_UnknownNodes(t *testing.T) {
+	prog := &parser.Program{Lines: []*parser.Line{
+		{Number: 10, Stmts: []parser.Statement{&unknownStmt{}}},
+	}}
+	_, err := EncodeContainer(prog, constants.BASIC_APPLE, Options{})
+	testutils.True(t, "unknown statement", err != nil)
+
+	prog.Lines[0].Stmts[0] = &parser.LetStmt{Name: "A", Value: &unknownExpr{}}
+	_, err = EncodeContainer(prog, constants.BASIC_APPLE, Options{})
+	testutils.True(t, "unknown expression", err != nil)
+
+	prog.Lines[0] = &parser.Line{Number: 70000}
+	_, err = EncodeContainer(prog, constants.BASIC_APPLE, Options{})
+	testutils.True(t, "line number out of range", err != nil)
+}
+
+func TestDecode_HardErrors(t *testing.T) {
+	pool := NewPool()
+	_, _ = pool.AddString("A")
+
+	decode := func(data ...byte) error {
+		d := &astDecoder{r: bytes.NewReader(data), pool: pool}
+		_, err := d.lines()
+		return err
+	}
+
+	// ligne 10, 1 instruction
+	line := []byte{10, 0, 1, 0}
+
+	testutils.True(t, "valid END", decode(append(line, opEnd)...) == nil)
+	testutils.True(t, "unknown statement", decode(append(line, 0x7F)...) != nil)
+	testutils.True(t, "missing statement", decode(line...) != nil)
+	testutils.True(t, "truncated line", decode(10) != nil)
+	testutils.True(t, "unknown expression", decode(append(line, opGoto, 0x7F)...) != nil)
+	testutils.True(t, "pool index", decode(append(line, opNext, 5, 0, 0, 0)...) != nil)
+	testutils.True(t, "ELSE flag", decode(append(line, opIf, opNil, 0, 0, 2)...) != nil)
+
+	// GOTO -(-(-(...))) trop imbriqué
+	deep := append([]byte{}, line...)
+	deep = append(deep, opGoto)
+	for i := 0; i <= maxDepth; i++ {
+		deep = append(deep, opPrefix, 0, 0)
+	}
+	testutils.True(t, "too deep", decode(deep...) != nil)
+}
+
+func TestDecodeV1_HardErrors(t *testing.T) {
+	// 10 LET A = <opcode inconnu>
+	_, err := decodeV1Lines([]byte{10, 0, 1, 0, opLet, 1, 0, 'A', 0x7F})
+	testutils.True(t, "unknown expression", err != nil)
+
+	// 10 PRINT avec une expression tronquée
+	_, err = decodeV1Lines([]byte{10, 0, 1, 0, opPrint, 1, 0, opNumber, 0, 0})
+	testutils.True(t, "truncated", err != nil)
+
+	// opcode v2 absent du format v1
+	_, err = decodeV1Lines([]byte{10, 0, 1, 0, opEnd})
+	testutils.True(t, "v2 opcode", err != nil)
+}
@@ -0,0 +1,310 @@
+package binary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"basics/internal/constants"
+	"basics/internal/lexer"
+	"basics/internal/parser"
+	"basics/internal/unparse"
+	"basics/testutils"
+)
+
+const containerSource = `10 A = 1
+20 FOR I = 1 TO 3 STEP 1
+25 N$ = "N"
+30 PRINT A * I
+40 NEXT I
+50 B% = -A
+`
+
+func parseProgram(t *testing.T, src string) *parser.Program {
+	t.Helper()
+
+	p := parser.New(lexer.Lex(src))
+	prog, errs := p.ParseProgram()
+	if len(errs) > 0 {
+		t.Fatalf("parse errors: %v", errs)
+	}
+	return prog
+}
+
+func encodeBytes(t *testing.T, c *Container) []byte {
+	t.Helper()
+
+	data, err := c.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// encodeV1 produit un fichier au format v1, tel qu'écrit par les
+// versions précédentes (LET, PRINT, FOR, NEXT)
+func encodeV1(t *testing.T, prog *parser.Program, basicType byte) []byte {
+	t.Helper()
+
+	var ast bytes.Buffer
+	u16 := func(v int) { _ = binary.Write(&ast, binary.LittleEndian, uint16(v)) }
+	str := func(s string) { _ = writeString(&ast, s) }
+
+	var expr func(e parser.Expression)
+	expr = func(e parser.Expression) {
+		switch x := e.(type) {
+		case *parser.NumberLiteral:
+			ast.WriteByte(opNumber)
+			_ = binary.Write(&ast, binary.LittleEndian, x.Value)
+		case *parser.StringLiteral:
+			ast.WriteByte(opString)
+			str(x.Value)
+		case *parser.Identifier:
+			ast.WriteByte(opIdent)
+			str(x.Name)
+		case *parser.PrefixExpr:
+			ast.WriteByte(opPrefix)
+			str(x.Op)
+			expr(x.Right)
+		case *parser.InfixExpr:
+			ast.WriteByte(opInfix)
+			str(x.Op)
+			expr(x.Left)
+			expr(x.Right)
+		default:
+			t.Fatalf("v1: expression %T", e)
+		}
+	}
+
+	for _, line := range prog.Lines {
+		u16(line.Number)
+		u16(len(line.Stmts))
+		for _, stmt := range line.Stmts {
+			switch s := stmt.(type) {
+			case *parser.LetStmt:
+				ast.WriteByte(opLet)
+				str(s.Name)
+				expr(s.Value)
+			case *parser.PrintStmt:
+				ast.WriteByte(opPrint)
+				u16(len(s.Exprs))
+				for _, e := range s.Exprs {
+					expr(e)
+				}
+			case *parser.ForStmt:
+				ast.WriteByte(opFor)
+				str(s.Var)
+				expr(s.Start)
+				expr(s.End)
+				expr(s.Step)
+			case *parser.NextStmt:
+				ast.WriteByte(opNext)
+				str(s.Var)
+			default:
+				t.Fatalf("v1: statement %T", stmt)
+			}
+		}
+	}
+
+	header := Header{
+		BasicType: basicType,
+		Version:   constants.BasicVersion[basicType],
+		NodeCount: uint32(countNodes(prog)),
+		CRC32:     crc32.ChecksumIEEE(ast.Bytes()),
+	}
+	copy(header.Magic[:], MagicString)
+
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, &header)
+	buf.Write(ast.Bytes())
+	return buf.Bytes()
+}
+
+func TestContainer_RoundTrip(t *testing.T) {
+	prog := parseProgram(t, containerSource)
+
+	c, err := EncodeContainer(prog, constants.BASIC_APPLE, Options{})
+	testutils.True(t, "no error", err == nil)
+
+	read, err := Load(encodeBytes(t, c))
+	testutils.True(t, "load", err == nil)
+	testutils.Equal(t, "basic type", read.Header.BasicType, constants.BASIC_APPLE)
+	testutils.Equal(t, "format", read.Header.Format, byte(FormatV2))
+	testutils.Equal(t, "features", read.Header.Features, FeaturePool|FeatureSymbols|FeatureLineMap|FeatureSpans)
+	testutils.Equal(t, "required", read.Header.Required, FeaturePool)
+
+	decoded, err := read.Program()
+	testutils.True(t, "decode", err == nil)
+	testutils.Equal(t, "program", unparse.Program(decoded), unparse.Program(prog))
+}
+
+func TestContainer_PoolDeduplication(t *testing.T) {
+	prog := parseProgram(t, "10 A = 1 + 1\n20 A = A + 1\n")
+
+	c, _ := EncodeContainer(prog, constants.BASIC_APPLE, Options{})
+	pool, err := c.Pool()
+	testutils.True(t, "no error", err == nil)
+	testutils.Equal(t, "strings", len(pool.Strings), 3) // A, +, "1" (texte du nombre)
+	testutils.Equal(t, "numbers", len(pool.Numbers), 1)
+}
+
+func TestContainer_SymbolsAndLineMap(t *testing.T) {
+	prog := parseProgram(t, containerSource)
+	c, _ := EncodeContainer(prog, constants.BASIC_APPLE, Options{})
+
+	symbols, err := c.Symbols()
+	testutils.True(t, "symbols", err == nil)
+	testutils.Equal(t, "symbol count", len(symbols), 4)
+	testutils.Equal(t, "first symbol", symbols[0], Symbol{Name: "A", Kind: SymbolReal})
+	testutils.Equal(t, "integer", symbols[1], Symbol{Name: "B%", Kind: SymbolInteger})
+	testutils.Equal(t, "string", symbols[3], Symbol{Name: "N$", Kind: SymbolString})
+
+	lines, err := c.LineMap()
+	testutils.True(t, "line map", err == nil)
+	testutils.Equal(t, "line count", len(lines), 6)
+	testutils.Equal(t, "first line", lines[0], LineEntry{Line: 10, Offset: 0})
+
+	// chaque position désigne le début de la ligne dans l'AST
+	ast := c.Section(SectionAST).Data
+	for _, e := range lines {
+		testutils.Equal(t, "line number at offset", int(binary.LittleEndian.Uint16(ast[e.Offset:])), e.Line)
+	}
+}
+
+func TestContainer_Debug(t *testing.T) {
+	prog := parseProgram(t, containerSource)
+	c, _ := EncodeContainer(prog, constants.BASIC_APPLE, Options{
+		Debug: true, SourceFile: "loop.bas", Source: containerSource,
+	})
+
+	read, _ := ParseContainer(encodeBytes(t, c))
+	info, err := read.Debug()
+	testutils.True(t, "no error", err == nil)
+	testutils.Equal(t, "file", info.SourceFile, "loop.bas")
+	testutils.Equal(t, "source", info.Source, containerSource)
+	testutils.True(t, "feature", read.Header.Features&FeatureDebug != 0)
+}
+
+func TestContainer_Data(t *testing.T) {
+	c := NewContainer(constants.BASIC_APPLE, 10)
+	c.AddSection(SectionData, 0, FeatureData, encodeStringList([]string{"1", "HELLO"}))
+
+	read, _ := ParseContainer(encodeBytes(t, c))
+	values, err := read.Data()
+	testutils.True(t, "no error", err == nil)
+	testutils.Equal(t, "values", len(values), 2)
+	testutils.Equal(t, "second value", values[1], "HELLO")
+}
+
+func TestContainer_UnknownOptionalSection(t *testing.T) {
+	prog := parseProgram(t, containerSource)
+	c, _ := EncodeContainer(prog, constants.BASIC_APPLE, Options{})
+	c.AddSection("XTRA", 0, 1<<30, []byte{1, 2, 3})
+
+	// un format plus récent reste lisible
+	c.Header.Format = FormatV2 + 1
+
+	read, err := Load(encodeBytes(t, c))
+	testutils.True(t, "loaded", err == nil)
+	testutils.True(t, "section kept", read.Section("XTRA") != nil)
+
+	_, err = read.Program()
+	testutils.True(t, "program decoded", err == nil)
+}
+
+func TestContainer_UnknownRequired(t *testing.T) {
+	prog := parseProgram(t, containerSource)
+
+	c, _ := EncodeContainer(prog, constants.BASIC_APPLE, Options{})
+	c.AddSection("XTRA", SectionRequired, 0, []byte{1})
+	_, err := Load(encodeBytes(t, c))
+	testutils.True(t, "unknown required section", err != nil)
+
+	c, _ = EncodeContainer(prog, constants.BASIC_APPLE, Options{})
+	c.Header.Required |= 1 << 31
+	_, err = Load(encodeBytes(t, c))
+	testutils.True(t, "unknown required feature", err != nil)
+}
+
+func TestContainer_Corrupted(t *testing.T) {
+	prog := parseProgram(t, containerSource)
+	c, _ := EncodeContainer(prog, constants.BASIC_APPLE, Options{})
+	data := encodeBytes(t, c)
+
+	corrupted := append([]byte(nil), data...)
+	corrupted[len(corrupted)-1] ^= 0xFF
+	_, err := Load(corrupted)
+	testutils.True(t, "CRC mismatch", err != nil)
+
+	_, err = Load(data[:len(data)-3])
+	testutils.True(t, "truncated", err != nil)
+
+	newer := NewContainer(constants.BASIC_APPLE, constants.BasicVersion[constants.BASIC_APPLE]+1)
+	_, err = Load(encodeBytes(t, newer))
+	testutils.True(t, "newer BASIC version", err != nil)
+}
+
+func TestMigrate_V1(t *testing.T) {
+	prog := parseProgram(t, containerSource)
+	v1 := encodeV1(t, prog, constants.BASIC_C64)
+
+	// lecture directe d'un fichier v1
+	c, err := Load(v1)
+	testutils.True(t, "v1 loaded", err == nil)
+	decoded, _ := c.Program()
+	testutils.Equal(t, "v1 program", unparse.Program(decoded), unparse.Program(prog))
+
+	// conversion au format v2
+	v2, err := Migrate(v1)
+	testutils.True(t, "migrated", err == nil)
+	testutils.Equal(t, "magic", string(v2[:4]), MagicV2)
+
+	read, err := Load(v2)
+	testutils.True(t, "v2 loaded", err == nil)
+	testutils.Equal(t, "basic type kept", read.Header.BasicType, constants.BASIC_C64)
+
+	same, _ := Migrate(v2)
+	testutils.True(t, "v2 unchanged", bytes.Equal(same, v2))
+
+	v1[len(v1)-1] ^= 0xFF
+	_, err = Migrate(v1)
+	testutils.True(t, "v1 CRC checked", err != nil)
+}
+
+func TestFiles_V1AndV2(t *testing.T) {
+	dir := t.TempDir()
+	prog := parseProgram(t, containerSource)
+
+	v1File := filepath.Join(dir, "old.bin")
+	_ = os.WriteFile(v1File, encodeV1(t, prog, constants.BASIC_APPLE), 0o644)
+
+	v2File := filepath.Join(dir, "new.bas")
+	testutils.CaptureStdout(t, func() {
+		testutils.True(t, "encoded", EncodeProgram(prog, v2File, constants.BASIC_AMS) == nil)
+	})
+	v2File = filepath.Join(dir, "new.bin")
+
+	for _, file := range []string{v1File, v2File} {
+		status, err := IsValidBasicsBinary(file)
+		testutils.True(t, file+" valid", err == nil)
+		testutils.Equal(t, file+" unsigned", status, Unsigned)
+
+		var decoded *parser.Program
+		testutils.CaptureStdout(t, func() {
+			decoded, err = DecodeProgram(file)
+		})
+		testutils.True(t, file+" decoded", err == nil)
+		testutils.Equal(t, file+" program", unparse.Program(decoded), unparse.Program(prog))
+	}
+
+	header, err := ReadHeader(v2File)
+	testutils.True(t, "header", err == nil)
+	testutils.Equal(t, "v2 basic type", header.BasicType, constants.BASIC_AMS)
+	testutils.Equal(t, "v2 magic", string(header.Magic[:]), MagicV2)
+
+	header, _ = ReadHeader(v1File)
+	testutils.Equal(t, "v1 basic type", header.BasicType, constants.BASIC_APPLE)
+}
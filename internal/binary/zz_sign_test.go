@@ -0,0 +1,165 @@
+package binary
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"basics/internal/constants"
+	"basics/testutils"
+)
+
+func newKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv
+}
+
+func signedContainer(t *testing.T, opts Options) *Container {
+	t.Helper()
+
+	prog := parseProgram(t, containerSource)
+	c, err := EncodeContainer(prog, constants.BASIC_APPLE, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := Load(encodeBytes(t, c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return read
+}
+
+func TestSign_Verify(t *testing.T) {
+	key := newKey(t)
+	c := signedContainer(t, Options{SignKey: key})
+
+	testutils.Equal(t, "status", c.SignatureStatus(), Signed)
+	testutils.True(t, "feature", c.Header.Features&FeatureSignature != 0)
+	testutils.True(t, "verified", c.Verify(key.Public().(ed25519.PublicKey)) == nil)
+
+	other := newKey(t)
+	testutils.True(t, "other key refused", c.Verify(other.Public().(ed25519.PublicKey)) != nil)
+}
+
+func TestSign_Unsigned(t *testing.T) {
+	c := signedContainer(t, Options{})
+
+	testutils.Equal(t, "status", c.SignatureStatus(), Unsigned)
+	testutils.True(t, "not signed", c.Verify(newKey(t).Public().(ed25519.PublicKey)) != nil)
+}
+
+func TestSign_Tampered(t *testing.T) {
+	c := signedContainer(t, Options{SignKey: newKey(t)})
+
+	// modification avec un CRC32 recalculé
+	ast := c.Section(SectionAST)
+	ast.Data[len(ast.Data)-1] ^= 0x01
+
+	read, err := ParseContainer(encodeBytes(t, c))
+	testutils.True(t, "CRC32 is valid", err == nil)
+	testutils.Equal(t, "tampered", read.SignatureStatus(), Tampered)
+
+	// signature retirée
+	c = signedContainer(t, Options{SignKey: newKey(t)})
+	c.removeSection(SectionSign)
+	testutils.Equal(t, "signature removed", c.SignatureStatus(), Tampered)
+
+	// nouvelle signature avec une autre clé
+	key := newKey(t)
+	c = signedContainer(t, Options{SignKey: key})
+	testutils.True(t, "re-signed", Sign(c, newKey(t)) == nil)
+	testutils.Equal(t, "valid for its own key", c.SignatureStatus(), Signed)
+	testutils.True(t, "refused by the trusted key", c.Verify(key.Public().(ed25519.PublicKey)) != nil)
+}
+
+func TestScramble(t *testing.T) {
+	c := signedContainer(t, Options{
+		Scramble: true, Debug: true, Source: containerSource, SignKey: newKey(t),
+	})
+
+	data := encodeBytes(t, c)
+	testutils.False(t, "names hidden", bytes.Contains(data, []byte("B%")))
+	testutils.False(t, "source hidden", bytes.Contains(data, []byte("FOR I")))
+	testutils.True(t, "required", c.Header.Required&FeatureScrambled != 0)
+	testutils.Equal(t, "still signed", c.SignatureStatus(), Signed)
+
+	prog, err := c.Program()
+	testutils.True(t, "decoded", err == nil)
+	testutils.True(t, "same program", reflect.DeepEqual(prog, parseProgram(t, containerSource)))
+
+	info, err := c.Debug()
+	testutils.True(t, "debug decoded", err == nil)
+	testutils.Equal(t, "source", info.Source, containerSource)
+
+	symbols, _ := c.Symbols()
+	testutils.Equal(t, "symbols", len(symbols), 4)
+}
+
+func TestIsValidBasicsBinary_Status(t *testing.T) {
+	dir := t.TempDir()
+	key := newKey(t)
+
+	write := func(name string, c *Container) string {
+		path := filepath.Join(dir, name)
+		_ = os.WriteFile(path, encodeBytes(t, c), 0o644)
+		return path
+	}
+
+	unsigned := write("unsigned.bin", signedContainer(t, Options{}))
+	signed := write("signed.bin", signedContainer(t, Options{SignKey: key}))
+
+	c := signedContainer(t, Options{SignKey: key})
+	c.Section(SectionAST).Data[0] ^= 0x01
+	tampered := write("tampered.bin", c)
+
+	data := encodeBytes(t, signedContainer(t, Options{}))
+	data[len(data)-1] ^= 0x01
+	corrupted := filepath.Join(dir, "corrupted.bin")
+	_ = os.WriteFile(corrupted, data, 0o644)
+
+	status, err := IsValidBasicsBinary(unsigned)
+	testutils.True(t, "unsigned valid", err == nil && status == Unsigned)
+
+	status, err = IsValidBasicsBinary(signed)
+	testutils.True(t, "signed valid", err == nil && status == Signed)
+
+	status, err = IsValidBasicsBinary(tampered)
+	testutils.True(t, "tampered refused", err != nil && status == Tampered)
+
+	status, err = IsValidBasicsBinary(corrupted)
+	testutils.True(t, "corrupted refused", err != nil && status == Tampered)
+
+	pub := key.Public().(ed25519.PublicKey)
+	testutils.True(t, "verify signed", VerifyFile(signed, pub) == nil)
+	testutils.True(t, "verify unsigned", VerifyFile(unsigned, pub) != nil)
+	testutils.True(t, "verify tampered", VerifyFile(tampered, pub) != nil)
+}
+
+func TestKeyFiles(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "teacher")
+	testutils.True(t, "generated", GenerateKeyFiles(base) == nil)
+
+	priv, err := LoadPrivateKey(base + ".key")
+	testutils.True(t, "private key", err == nil)
+	pub, err := LoadPublicKey(base + ".pub")
+	testutils.True(t, "public key", err == nil)
+	testutils.True(t, "pair", bytes.Equal(priv.Public().(ed25519.PublicKey), pub))
+
+	_, err = LoadPublicKey(base + ".key")
+	testutils.True(t, "wrong PEM type", err != nil)
+	_, err = LoadPrivateKey(base + ".missing")
+	testutils.True(t, "missing file", err != nil)
+
+	testutils.Equal(t, "status names", Tampered.String()+" "+Signed.String()+" "+Unsigned.String(),
+		"tampered signed unsigned")
+}
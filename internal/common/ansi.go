@@ -1,6 +1,19 @@
 package common
 
-import "regexp"
+import (
+	"regexp"
+	"strings"
+)
+
+// Codes ANSI de mise en forme (diagnostics en couleur)
+const (
+	Reset  = "\x1b[0m"
+	Bold   = "\x1b[1m"
+	Red    = "\x1b[31m"
+	Yellow = "\x1b[33m"
+	Blue   = "\x1b[34m"
+	Cyan   = "\x1b[36m"
+)
 
 var ansiRegexp = regexp.MustCompile(
 	`\x1b\[[0-9;]*[a-zA-Z]`,
@@ -9,3 +22,12 @@ var ansiRegexp = regexp.MustCompile(
 func StripANSI(s string) string {
 	return ansiRegexp.ReplaceAllString(s, "")
 }
+
+// Colorize met s en forme avec les codes donnés ; sans code, s est
+// retourné tel quel
+func Colorize(s string, codes ...string) string {
+	if len(codes) == 0 || s == "" {
+		return s
+	}
+	return strings.Join(codes, "") + s + Reset
+}
@@ -49,3 +49,16 @@ func TestStripANSI(t *testing.T) {
 		})
 	}
 }
+
+func TestColorize(t *testing.T) {
+	got := Colorize("error", Bold, Red)
+	if got != "\x1b[1m\x1b[31merror\x1b[0m" {
+		t.Errorf("Colorize = %q", got)
+	}
+	if StripANSI(got) != "error" {
+		t.Errorf("StripANSI(Colorize) = %q", StripANSI(got))
+	}
+	if Colorize("plain") != "plain" {
+		t.Errorf("Colorize without codes = %q", Colorize("plain"))
+	}
+}
@@ -0,0 +1,222 @@
+package cover
+
+import (
+	"strconv"
+	"strings"
+
+	"basics/internal/dialect"
+	"basics/internal/interpreter"
+	"basics/internal/lexer"
+	"basics/internal/parser"
+	"basics/internal/token"
+)
+
+// Pos est une position dans le fichier source : ligne et colonne (en
+// caractères) à partir de 1
+type Pos struct {
+	Line int
+	Col  int
+}
+
+// Block est une zone du source couverte par une ou plusieurs instructions
+// BASIC. End est la position qui suit la zone.
+type Block struct {
+	Start Pos
+	End   Pos
+	Stmts int // nombre d'instructions
+	Count int // exécutions
+}
+
+// Branch est une branche d'un IF : THEN (Else false) ou ELSE, explicite ou
+// non (instruction suivante)
+type Branch struct {
+	Line  int // ligne du fichier
+	If    int // numéro du IF dans le programme
+	Else  bool
+	Taken int // -1 : IF jamais exécuté
+}
+
+// Report est la couverture d'un programme source
+type Report struct {
+	Path     string
+	Lines    []string // lignes du fichier
+	Blocks   []Block
+	Branches []Branch
+
+	Statements int // instructions du programme
+	Covered    int // instructions exécutées
+}
+
+// New associe les instructions exécutées par l'interpréteur au source du
+// programme. Chaque instruction devient un bloc du source ; une ligne
+// dont les instructions ne peuvent pas être délimitées devient un seul
+// bloc.
+func New(path, source string, d *dialect.Dialect, stmts []interpreter.CoveredStatement) (*Report, error) {
+	tokens, err := lexer.Scan(source, d)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Report{Path: path, Lines: splitLines(source)}
+	segments := r.segments(tokens)
+
+	counts := make(map[parser.Statement]int, len(stmts))
+	firstLine := map[int]int{} // ligne BASIC → ligne du fichier
+	for _, s := range stmts {
+		counts[s.Node] = s.Count
+		r.Statements++
+		if s.Count > 0 {
+			r.Covered++
+		}
+	}
+
+	// instructions groupées par ligne BASIC, dans l'ordre
+	for start := 0; start < len(stmts); {
+		end := start
+		for end < len(stmts) && stmts[end].Line == stmts[start].Line {
+			end++
+		}
+		line := stmts[start:end]
+		segs := segments[line[0].Line]
+
+		if len(segs) == len(line) {
+			for k, s := range line {
+				r.Blocks = append(r.Blocks, Block{Start: segs[k].start, End: segs[k].end, Stmts: 1, Count: s.Count})
+			}
+		} else if len(segs) > 0 {
+			hits := 0
+			for _, s := range line {
+				hits = max(hits, s.Count)
+			}
+			r.Blocks = append(r.Blocks, Block{
+				Start: segs[0].start, End: segs[len(segs)-1].end, Stmts: len(line), Count: hits,
+			})
+		}
+		if len(segs) > 0 {
+			firstLine[line[0].Line] = segs[0].start.Line
+		}
+		start = end
+	}
+
+	// branches des IF
+	ifs := 0
+	for _, s := range stmts {
+		n, ok := s.Node.(*parser.IfStmt)
+		if !ok {
+			continue
+		}
+		ifs++
+
+		then, taken := -1, -1
+		if s.Count > 0 && len(n.Then) > 0 {
+			then = counts[n.Then[0]]
+		}
+		if s.Count > 0 {
+			switch {
+			case len(n.Else) > 0:
+				taken = counts[n.Else[0]]
+			case then >= 0:
+				taken = s.Count - then
+			}
+		}
+
+		line := firstLine[s.Line]
+		r.Branches = append(r.Branches,
+			Branch{Line: line, If: ifs, Taken: then},
+			Branch{Line: line, If: ifs, Else: true, Taken: taken},
+		)
+	}
+
+	return r, nil
+}
+
+// Percent retourne la part des instructions exécutées
+func (r *Report) Percent() float64 {
+	if r.Statements == 0 {
+		return 0
+	}
+	return 100 * float64(r.Covered) / float64(r.Statements)
+}
+
+// segment délimite une instruction dans le source
+type segment struct {
+	start, end Pos
+}
+
+// segments découpe chaque ligne BASIC en instructions, dans l'ordre du
+// compilateur : ':' les sépare, THEN termine l'instruction IF et ELSE
+// commence une nouvelle instruction. REM n'est pas une instruction.
+func (r *Report) segments(tokens []token.Token) map[int][]segment {
+	segs := map[int][]segment{}
+	line := 0
+	var open *segment
+	rem := false
+	last := token.Token{}
+
+	closeAt := func(end Pos) {
+		if open != nil && !rem {
+			open.end = r.trim(open.start, end)
+			segs[line] = append(segs[line], *open)
+		}
+		open, rem = nil, false
+	}
+
+	for _, tok := range tokens {
+		switch {
+		case tok.Type == token.LINENUM:
+			line, _ = strconv.Atoi(tok.Literal)
+
+		case tok.Type == token.EOL || tok.Type == token.EOF:
+			closeAt(Pos{last.Line, r.lineEnd(last.Line)})
+
+		case tok.Type == token.COLON:
+			closeAt(Pos{tok.Line, tok.Column})
+
+		case tok.Type == token.KEYWORD && tok.Literal == "ELSE":
+			closeAt(Pos{tok.Line, tok.Column})
+
+		case tok.Type == token.KEYWORD && tok.Literal == "THEN":
+			closeAt(Pos{tok.Line, tok.Column + len([]rune(tok.Literal))})
+
+		default:
+			if open == nil {
+				open = &segment{start: Pos{tok.Line, tok.Column}}
+				rem = tok.Type == token.KEYWORD && tok.Literal == "REM"
+			}
+		}
+		last = tok
+	}
+	return segs
+}
+
+// trim retire les espaces qui terminent une instruction
+func (r *Report) trim(start, end Pos) Pos {
+	if end.Line < 1 || end.Line > len(r.Lines) {
+		return end
+	}
+	runes := []rune(r.Lines[end.Line-1])
+	for end.Col > 1 && end.Col-2 < len(runes) && (end.Line > start.Line || end.Col > start.Col+1) {
+		if ch := runes[end.Col-2]; ch != ' ' && ch != '\t' {
+			break
+		}
+		end.Col--
+	}
+	return end
+}
+
+// lineEnd retourne la colonne qui suit la fin d'une ligne du fichier
+func (r *Report) lineEnd(line int) int {
+	if line < 1 || line > len(r.Lines) {
+		return 1
+	}
+	return len([]rune(r.Lines[line-1])) + 1
+}
+
+// splitLines découpe le source en lignes, sans fin de ligne
+func splitLines(source string) []string {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	if n := len(lines); n > 0 && lines[n-1] == "" {
+		lines = lines[:n-1]
+	}
+	return lines
+}
@@ -0,0 +1,90 @@
+package cover
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// WriteLcov écrit la couverture au format lcov (tracefile .info) : les
+// exécutions de chaque ligne du fichier (son instruction la plus exécutée)
+// et les branches THEN / ELSE des IF
+func (r *Report) WriteLcov(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "TN:\nSF:%s\n", r.Path)
+
+	for _, br := range r.Branches {
+		taken := "-"
+		if br.Taken >= 0 {
+			taken = fmt.Sprint(br.Taken)
+		}
+		branch := 0
+		if br.Else {
+			branch = 1
+		}
+		fmt.Fprintf(bw, "BRDA:%d,%d,%d,%s\n", br.Line, br.If, branch, taken)
+	}
+	hit := 0
+	for _, br := range r.Branches {
+		if br.Taken > 0 {
+			hit++
+		}
+	}
+	fmt.Fprintf(bw, "BRF:%d\nBRH:%d\n", len(r.Branches), hit)
+
+	lines := r.lineCounts()
+	numbers := make([]int, 0, len(lines))
+	for line := range lines {
+		numbers = append(numbers, line)
+	}
+	sort.Ints(numbers)
+
+	hit = 0
+	for _, line := range numbers {
+		fmt.Fprintf(bw, "DA:%d,%d\n", line, lines[line])
+		if lines[line] > 0 {
+			hit++
+		}
+	}
+	fmt.Fprintf(bw, "LF:%d\nLH:%d\nend_of_record\n", len(numbers), hit)
+
+	return bw.Flush()
+}
+
+// WriteGo écrit la couverture au format des profils de go test -coverprofile
+// (mode count), un bloc par instruction ; les colonnes sont en octets
+func (r *Report) WriteGo(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "mode: count")
+
+	for _, b := range r.Blocks {
+		fmt.Fprintf(bw, "%s:%d.%d,%d.%d %d %d\n", r.Path,
+			b.Start.Line, r.byteCol(b.Start), b.End.Line, r.byteCol(b.End), b.Stmts, b.Count)
+	}
+	return bw.Flush()
+}
+
+// lineCounts retourne les exécutions de chaque ligne du fichier qui
+// contient une instruction
+func (r *Report) lineCounts() map[int]int {
+	lines := map[int]int{}
+	for _, b := range r.Blocks {
+		for line := b.Start.Line; line <= b.End.Line; line++ {
+			lines[line] = max(lines[line], b.Count)
+		}
+	}
+	return lines
+}
+
+// byteCol convertit une colonne en caractères en colonne en octets
+func (r *Report) byteCol(p Pos) int {
+	if p.Line < 1 || p.Line > len(r.Lines) {
+		return p.Col
+	}
+	runes := []rune(r.Lines[p.Line-1])
+	if p.Col-1 > len(runes) {
+		return len(r.Lines[p.Line-1]) + 1
+	}
+	return len(string(runes[:p.Col-1])) + 1
+}
@@ -0,0 +1,90 @@
+package cover
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+const htmlHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s: %.1f%% coverage</title>
+<style>
+body { background: #fff; color: #222; font-family: sans-serif; }
+pre { font-family: Menlo, Consolas, monospace; line-height: 1.4; }
+.num { color: #999; display: inline-block; width: 4em; text-align: right; }
+.hits { color: #666; display: inline-block; width: 6em; text-align: right; margin-right: 1em; }
+.cov { background: #c8f0c8; }
+.uncov { background: #f6c6c6; }
+</style>
+</head>
+<body>
+<h1>%s</h1>
+<p>%d of %d statements executed (%.1f%%). <span class="cov">executed</span> <span class="uncov">not executed</span></p>
+<pre>
+`
+
+// WriteHTML écrit le listing du programme annoté : les instructions
+// exécutées sur fond vert, les autres sur fond rouge, et le nombre
+// d'exécutions de chaque ligne dans la marge
+func (r *Report) WriteHTML(w io.Writer) error {
+	var sb strings.Builder
+	title := html.EscapeString(r.Path)
+	fmt.Fprintf(&sb, htmlHeader, title, r.Percent(), title, r.Covered, r.Statements, r.Percent())
+
+	counts := r.lineCounts()
+	for k, text := range r.Lines {
+		line := k + 1
+
+		hits := ""
+		if n, ok := counts[line]; ok {
+			hits = fmt.Sprintf("%d×", n)
+		}
+		fmt.Fprintf(&sb, `<span class="num">%d</span><span class="hits">%s</span>`, line, hits)
+		sb.WriteString(r.annotate(line, text))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("</pre>\n</body>\n</html>\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// annotate entoure les blocs d'une ligne du fichier
+func (r *Report) annotate(line int, text string) string {
+	runes := []rune(text)
+	var sb strings.Builder
+
+	col := 1
+	for _, b := range r.Blocks {
+		if b.Start.Line > line || b.End.Line < line {
+			continue
+		}
+		from, to := 1, len(runes)+1
+		if b.Start.Line == line {
+			from = b.Start.Col
+		}
+		if b.End.Line == line {
+			to = b.End.Col
+		}
+		from, to = clamp(from, col, len(runes)+1), clamp(to, col, len(runes)+1)
+
+		class := "cov"
+		if b.Count == 0 {
+			class = "uncov"
+		}
+		sb.WriteString(html.EscapeString(string(runes[col-1 : from-1])))
+		fmt.Fprintf(&sb, `<span class="%s" title="%d">%s</span>`, class, b.Count,
+			html.EscapeString(string(runes[from-1:to-1])))
+		col = to
+	}
+	sb.WriteString(html.EscapeString(string(runes[col-1:])))
+	return sb.String()
+}
+
+func clamp(x, lo, hi int) int {
+	return min(max(x, lo), hi)
+}
@@ -0,0 +1,11 @@
+package cover
+
+import (
+	"testing"
+
+	"basics/testutils"
+)
+
+func TestMain(m *testing.M) {
+	testutils.RunWithAssertTracking(m)
+}
@@ -0,0 +1,92 @@
+package cover
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"basics/internal/constants"
+	"basics/internal/dialect"
+	"basics/internal/interpreter"
+	"basics/internal/lexer"
+	"basics/internal/machines"
+	"basics/internal/parser"
+	"basics/testutils"
+)
+
+const program = `10 REM COUVERTURE
+20 A = 2 : B = 0
+30 IF A = 1 THEN PRINT "UN" : B = 1 ELSE PRINT "AUTRE"
+40 IF A > 1 THEN 60
+50 PRINT "JAMAIS"
+60 END
+`
+
+// run exécute le programme et retourne son rapport de couverture
+func run(t *testing.T, src string) *Report {
+	t.Helper()
+
+	prog, errs := parser.New(lexer.Lex(src)).ParseProgram()
+	if len(errs) > 0 {
+		t.Fatalf("parse errors: %v", errs)
+	}
+	rt, err := machines.NewRuntime(constants.BASIC_TTY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt.Video.SetOutput(&bytes.Buffer{})
+
+	interp := interpreter.New(rt)
+	coverage := interpreter.NewCoverage()
+	interp.SetCoverage(coverage)
+	interp.Run(prog)
+
+	r, err := New("prog.bas", src, dialect.Applesoft, coverage.Statements())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestNew_Blocks(t *testing.T) {
+	r := run(t, program)
+
+	testutils.Equal(t, "statements", r.Statements, 10)
+	testutils.Equal(t, "covered", r.Covered, 7)
+
+	// une instruction par bloc ; THEN 60 est un GOTO implicite
+	testutils.Equal(t, "blocks", len(r.Blocks), 10)
+	testutils.Equal(t, "A = 2", r.Blocks[0], Block{Start: Pos{2, 4}, End: Pos{2, 9}, Stmts: 1, Count: 1})
+	testutils.Equal(t, "IF", r.Blocks[2], Block{Start: Pos{3, 4}, End: Pos{3, 17}, Stmts: 1, Count: 1})
+	testutils.Equal(t, "THEN", r.Blocks[3], Block{Start: Pos{3, 18}, End: Pos{3, 28}, Stmts: 1, Count: 0})
+	testutils.Equal(t, "ELSE", r.Blocks[5], Block{Start: Pos{3, 42}, End: Pos{3, 55}, Stmts: 1, Count: 1})
+	testutils.Equal(t, "THEN 60", r.Blocks[7], Block{Start: Pos{4, 18}, End: Pos{4, 20}, Stmts: 1, Count: 1})
+	testutils.Equal(t, "not executed", r.Blocks[8].Count, 0)
+
+	testutils.Equal(t, "branches", len(r.Branches), 4)
+	testutils.Equal(t, "THEN not taken", r.Branches[0], Branch{Line: 3, If: 1, Taken: 0})
+	testutils.Equal(t, "ELSE taken", r.Branches[1], Branch{Line: 3, If: 1, Else: true, Taken: 1})
+	testutils.Equal(t, "implicit ELSE", r.Branches[3], Branch{Line: 4, If: 2, Else: true, Taken: 0})
+}
+
+func TestReport_Formats(t *testing.T) {
+	r := run(t, program)
+
+	var lcov bytes.Buffer
+	testutils.True(t, "lcov", r.WriteLcov(&lcov) == nil)
+	testutils.True(t, "lcov source", strings.HasPrefix(lcov.String(), "TN:\nSF:prog.bas\n"))
+	testutils.True(t, "lcov branch", strings.Contains(lcov.String(), "BRDA:3,1,0,0\n"))
+	testutils.True(t, "lcov line", strings.Contains(lcov.String(), "DA:5,0\n"))
+	testutils.True(t, "lcov totals", strings.Contains(lcov.String(), "LF:5\nLH:4\nend_of_record\n"))
+
+	var goCover bytes.Buffer
+	testutils.True(t, "go", r.WriteGo(&goCover) == nil)
+	lines := strings.Split(goCover.String(), "\n")
+	testutils.Equal(t, "go mode", lines[0], "mode: count")
+	testutils.Equal(t, "go block", lines[1], "prog.bas:2.4,2.9 1 1")
+
+	var html bytes.Buffer
+	testutils.True(t, "html", r.WriteHTML(&html) == nil)
+	testutils.True(t, "html uncovered", strings.Contains(html.String(), `<span class="uncov" title="0">PRINT &#34;JAMAIS&#34;</span>`))
+	testutils.True(t, "html summary", strings.Contains(html.String(), "7 of 10 statements executed (70.0%)"))
+}
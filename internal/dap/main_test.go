@@ -0,0 +1,11 @@
+package dap
+
+import (
+	"testing"
+
+	"basics/testutils"
+)
+
+func TestMain(m *testing.M) {
+	testutils.RunWithAssertTracking(m)
+}
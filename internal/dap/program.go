@@ -0,0 +1,104 @@
+package dap
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"basics/internal/dialect"
+	"basics/internal/lexer"
+	"basics/internal/parser"
+	"basics/internal/token"
+)
+
+// program est le programme lancé, avec la correspondance entre les lignes
+// du fichier (éditeur) et les numéros de ligne BASIC
+type program struct {
+	path string
+	ast  *parser.Program
+
+	basic    map[int]int // ligne du fichier → numéro de ligne BASIC
+	physical map[int]int // numéro de ligne BASIC → ligne du fichier
+}
+
+// load lit et analyse un programme source
+func load(path string, d *dialect.Dialect) (*program, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := lexer.Scan(string(data), d)
+	if err != nil {
+		return nil, err
+	}
+	ast, errs := parser.NewWithDialect(tokens, d).ParseProgram()
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	p := &program{path: path, ast: ast, basic: map[int]int{}, physical: map[int]int{}}
+
+	// chaque ligne du fichier appartient à la ligne BASIC de ses tokens
+	// (lignes de continuation comprises) ; les lignes vides à aucune
+	current := 0
+	for _, tok := range tokens {
+		switch tok.Type {
+		case token.LINENUM:
+			current, _ = strconv.Atoi(tok.Literal)
+			if _, ok := p.physical[current]; !ok {
+				p.physical[current] = tok.Line
+			}
+		case token.EOL, token.EOF:
+			continue
+		}
+		if current > 0 {
+			p.basic[tok.Line] = current
+		}
+	}
+	return p, nil
+}
+
+// source décrit le fichier du programme
+func (p *program) source() Source {
+	name := p.path
+	if k := strings.LastIndexAny(name, `/\`); k >= 0 {
+		name = name[k+1:]
+	}
+	return Source{Name: name, Path: p.path}
+}
+
+// input fournit aux INPUT et GET le texte donné au lancement. Une fois ce
+// texte lu, le programme attend la fin de la session.
+type input struct {
+	r      *bufio.Reader
+	closed chan struct{}
+}
+
+func newInput(text string, closed chan struct{}) *input {
+	return &input{r: bufio.NewReader(strings.NewReader(text)), closed: closed}
+}
+
+func (in *input) ReadLine() (string, error) {
+	line, err := in.r.ReadString('\n')
+	if err != nil && line == "" {
+		<-in.closed
+		return "", io.EOF
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (in *input) GetChar() (rune, error) {
+	ch, _, err := in.r.ReadRune()
+	if err != nil {
+		<-in.closed
+		return 0, io.EOF
+	}
+	return ch, nil
+}
+
+// errNotLaunched est l'erreur des requêtes reçues avant launch
+var errNotLaunched = errors.New("no program launched")
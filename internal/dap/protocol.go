@@ -0,0 +1,186 @@
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+// Messages du Debug Adapter Protocol. Les lignes et les colonnes comptent
+// à partir de 1 (valeurs par défaut du protocole).
+
+// request est une requête du client
+type request struct {
+	Seq       int             `json:"seq"`
+	Type      string          `json:"type"`
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+type response struct {
+	Seq        int    `json:"seq"`
+	Type       string `json:"type"`
+	RequestSeq int    `json:"request_seq"`
+	Success    bool   `json:"success"`
+	Command    string `json:"command"`
+	Message    string `json:"message,omitempty"`
+	Body       any    `json:"body,omitempty"`
+}
+
+type event struct {
+	Seq   int    `json:"seq"`
+	Type  string `json:"type"`
+	Event string `json:"event"`
+	Body  any    `json:"body,omitempty"`
+}
+
+// LaunchArguments : programme à déboguer et dialecte ; Input est le texte
+// lu par INPUT et GET, la session n'ayant pas de clavier
+type LaunchArguments struct {
+	Program     string `json:"program"`
+	StopOnEntry bool   `json:"stopOnEntry"`
+	NoDebug     bool   `json:"noDebug"`
+	Basic       string `json:"basic"`
+	Crunched    bool   `json:"crunched"`
+	Lowercase   bool   `json:"lowercase"`
+	Input       string `json:"input"`
+}
+
+type Source struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+type SourceBreakpoint struct {
+	Line      int    `json:"line"`
+	Condition string `json:"condition,omitempty"`
+}
+
+type SetBreakpointsArguments struct {
+	Source      Source             `json:"source"`
+	Breakpoints []SourceBreakpoint `json:"breakpoints"`
+}
+
+type Breakpoint struct {
+	Verified bool   `json:"verified"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+type StackFrame struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Source Source `json:"source"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+type Scope struct {
+	Name               string `json:"name"`
+	VariablesReference int    `json:"variablesReference"`
+	Expensive          bool   `json:"expensive"`
+}
+
+type Variable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+type VariablesArguments struct {
+	VariablesReference int `json:"variablesReference"`
+}
+
+type EvaluateArguments struct {
+	Expression string `json:"expression"`
+}
+
+type Thread struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// conn lit et écrit des messages encadrés par un en-tête Content-Length ;
+// les numéros de séquence des messages envoyés sont attribués à l'écriture
+type conn struct {
+	in  *textproto.Reader
+	out io.Writer
+	mu  sync.Mutex
+	seq int
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{in: textproto.NewReader(bufio.NewReader(r)), out: w}
+}
+
+// read lit la requête suivante
+func (c *conn) read() (*request, error) {
+	body, err := c.readBody()
+	if err != nil {
+		return nil, err
+	}
+
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// readBody lit le contenu du message suivant
+func (c *conn) readBody() ([]byte, error) {
+	header, err := c.in.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length header %q", header.Get("Content-Length"))
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.in.R, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// write numérote et écrit un message ; set reçoit son numéro de séquence
+func (c *conn) write(msg any, set func(seq int)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.seq++
+	set(c.seq)
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(c.out, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.out.Write(body)
+	return err
+}
+
+// reply répond à une requête ; une erreur non nulle est un échec
+func (c *conn) reply(req *request, body any, err error) error {
+	resp := &response{Type: "response", RequestSeq: req.Seq, Success: err == nil, Command: req.Command, Body: body}
+	if err != nil {
+		resp.Message = err.Error()
+	}
+	return c.write(resp, func(seq int) { resp.Seq = seq })
+}
+
+// event envoie un événement
+func (c *conn) event(name string, body any) error {
+	ev := &event{Type: "event", Event: name, Body: body}
+	return c.write(ev, func(seq int) { ev.Seq = seq })
+}
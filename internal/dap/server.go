@@ -0,0 +1,315 @@
+package dap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"basics/internal/common"
+	"basics/internal/constants"
+	"basics/internal/dialect"
+	"basics/internal/interpreter"
+	"basics/internal/logger"
+	"basics/internal/machines"
+	"basics/internal/unparse"
+)
+
+// threadID est l'unique thread d'exécution d'un programme BASIC
+const threadID = 1
+
+// Références des scopes de la requête variables
+const (
+	variablesRef = 1
+	loopsRef     = 2
+)
+
+// Server est un adaptateur Debug Adapter Protocol : il lance un programme
+// BASIC sous le débogueur de l'interpréteur et le pilote pour l'IDE
+type Server struct {
+	conn    *conn
+	dialect *dialect.Dialect
+
+	prog   *program
+	interp *interpreter.Interpreter
+	debug  *interpreter.Debugger
+	closed chan struct{}
+}
+
+// NewServer crée un adaptateur lisant r et écrivant w ; le dialecte peut
+// être remplacé par les arguments de launch
+func NewServer(r io.Reader, w io.Writer, d *dialect.Dialect) *Server {
+	return &Server{conn: newConn(r, w), dialect: d, closed: make(chan struct{})}
+}
+
+// Serve traite les requêtes jusqu'à disconnect ou la fin du flux
+func (s *Server) Serve() error {
+	defer close(s.closed)
+
+	for {
+		req, err := s.conn.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		logger.Debug("DAP " + req.Command)
+
+		body, err := s.handle(req)
+		if err := s.conn.reply(req, body, err); err != nil {
+			return err
+		}
+
+		switch req.Command {
+		case "launch":
+			if err == nil {
+				// prêt à recevoir les points d'arrêt
+				if err := s.conn.event("initialized", nil); err != nil {
+					return err
+				}
+			}
+		case "disconnect":
+			return nil
+		}
+	}
+}
+
+// handle exécute une requête et retourne le corps de sa réponse
+func (s *Server) handle(req *request) (any, error) {
+	if s.debug == nil {
+		switch req.Command {
+		case "initialize", "launch", "disconnect":
+		default:
+			return nil, errNotLaunched
+		}
+	}
+
+	switch req.Command {
+	case "initialize":
+		return map[string]any{
+			"supportsConfigurationDoneRequest": true,
+			"supportsConditionalBreakpoints":   true,
+			"supportsEvaluateForHovers":        true,
+			"supportsTerminateRequest":         true,
+		}, nil
+
+	case "launch":
+		var args LaunchArguments
+		if err := decode(req.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return nil, s.launch(&args)
+
+	case "setBreakpoints":
+		var args SetBreakpointsArguments
+		if err := decode(req.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return map[string]any{"breakpoints": s.setBreakpoints(args.Breakpoints)}, nil
+
+	case "configurationDone":
+		s.start()
+		return nil, nil
+
+	case "threads":
+		return map[string]any{"threads": []Thread{{ID: threadID, Name: "BASIC program"}}}, nil
+
+	case "stackTrace":
+		frames := s.stackTrace()
+		return map[string]any{"stackFrames": frames, "totalFrames": len(frames)}, nil
+
+	case "scopes":
+		return map[string]any{"scopes": []Scope{
+			{Name: "Variables", VariablesReference: variablesRef},
+			{Name: "FOR loops", VariablesReference: loopsRef},
+		}}, nil
+
+	case "variables":
+		var args VariablesArguments
+		if err := decode(req.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return map[string]any{"variables": s.variables(args.VariablesReference)}, nil
+
+	case "evaluate":
+		var args EvaluateArguments
+		if err := decode(req.Arguments, &args); err != nil {
+			return nil, err
+		}
+		val, err := s.debug.Evaluate(args.Expression)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"result": interpreter.FormatValue(val), "variablesReference": 0}, nil
+
+	case "continue":
+		s.debug.Resume(interpreter.Continue)
+		return map[string]any{"allThreadsContinued": true}, nil
+	case "next":
+		s.debug.Resume(interpreter.StepOver)
+		return nil, nil
+	case "stepIn":
+		s.debug.Resume(interpreter.StepIn)
+		return nil, nil
+	case "stepOut":
+		s.debug.Resume(interpreter.StepOut)
+		return nil, nil
+	case "pause":
+		s.debug.Pause()
+		return nil, nil
+
+	case "terminate", "disconnect":
+		if s.debug != nil {
+			s.debug.Terminate()
+		}
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("unsupported command %s", req.Command)
+}
+
+// launch charge le programme et prépare son exécution, lancée par
+// configurationDone
+func (s *Server) launch(args *LaunchArguments) error {
+	d := s.dialect
+	if args.Basic != "" {
+		switch strings.ToUpper(args.Basic) {
+		case "APPLE":
+			d = dialect.ForType(constants.BASIC_APPLE)
+		case "C64":
+			d = dialect.ForType(constants.BASIC_C64)
+		case "AMS":
+			d = dialect.ForType(constants.BASIC_AMS)
+		default:
+			return fmt.Errorf("unknown BASIC type %s", args.Basic)
+		}
+	}
+	if args.Crunched {
+		d = d.WithCrunched()
+	}
+	if args.Lowercase {
+		d = d.WithLowercase()
+	}
+
+	prog, err := load(args.Program, d)
+	if err != nil {
+		return err
+	}
+
+	// le programme s'exécute en mode texte ; sa sortie est envoyée à l'IDE
+	rt, err := machines.NewRuntime(constants.BASIC_TTY)
+	if err != nil {
+		return err
+	}
+	rt.SetOutput(output{s.conn})
+	rt.Input = newInput(args.Input, s.closed)
+
+	s.prog = prog
+	s.interp = interpreter.New(rt)
+	s.debug = interpreter.NewDebugger(d)
+	s.debug.StopOnEntry = args.StopOnEntry
+	s.interp.SetDebugger(s.debug)
+
+	if args.NoDebug {
+		s.debug.Detach()
+	}
+	return nil
+}
+
+// start exécute le programme et transmet ses arrêts à l'IDE
+func (s *Server) start() {
+	go s.interp.Run(s.prog.ast)
+
+	go func() {
+		for stop := range s.debug.Stops() {
+			reason := string(stop.Reason)
+			if stop.Reason == interpreter.StopWatch {
+				reason = "data breakpoint"
+			}
+			_ = s.conn.event("stopped", map[string]any{
+				"reason": reason, "threadId": threadID, "allThreadsStopped": true,
+			})
+		}
+		_ = s.conn.event("exited", map[string]any{"exitCode": 0})
+		_ = s.conn.event("terminated", nil)
+	}()
+}
+
+// setBreakpoints remplace les points d'arrêt ; une ligne du fichier désigne
+// la ligne BASIC qui la contient
+func (s *Server) setBreakpoints(requested []SourceBreakpoint) []Breakpoint {
+	s.debug.ClearBreakpoints()
+
+	bps := make([]Breakpoint, 0, len(requested))
+	for _, r := range requested {
+		line, ok := s.prog.basic[r.Line]
+		if !ok {
+			bps = append(bps, Breakpoint{Line: r.Line, Message: "no BASIC line here"})
+			continue
+		}
+
+		err := s.debug.SetBreakpoint(interpreter.Breakpoint{Line: line, Cond: r.Condition})
+		if err != nil {
+			bps = append(bps, Breakpoint{Line: r.Line, Message: err.Error()})
+			continue
+		}
+		bps = append(bps, Breakpoint{Verified: true, Line: s.prog.physical[line]})
+	}
+	return bps
+}
+
+// stackTrace retourne l'instruction courante puis les GOSUB en cours
+func (s *Server) stackTrace() []StackFrame {
+	var frames []StackFrame
+	for k, f := range s.debug.Frames() {
+		frames = append(frames, StackFrame{
+			ID:     k,
+			Name:   fmt.Sprintf("%d %s", f.Location.Line, unparse.Statement(f.Stmt)),
+			Source: s.prog.source(),
+			Line:   s.prog.physical[f.Location.Line],
+			Column: 1,
+		})
+	}
+	return frames
+}
+
+// variables retourne les variables affectées ou les boucles FOR ouvertes
+func (s *Server) variables(ref int) []Variable {
+	vars := []Variable{}
+
+	switch ref {
+	case variablesRef:
+		for _, v := range s.debug.Variables() {
+			if v.Set {
+				vars = append(vars, Variable{Name: v.Symbol.Name, Value: interpreter.FormatValue(v.Value), Type: v.Symbol.Kind.String()})
+			}
+		}
+	case loopsRef:
+		for _, l := range s.debug.Loops() {
+			vars = append(vars, Variable{
+				Name:  l.Var,
+				Value: fmt.Sprintf("TO %g STEP %g (line %d)", l.End, l.Step, l.Location.Line),
+			})
+		}
+	}
+	return vars
+}
+
+// output envoie la sortie du programme à l'IDE
+type output struct {
+	c *conn
+}
+
+func (o output) Write(p []byte) (int, error) {
+	err := o.c.event("output", map[string]any{"category": "stdout", "output": common.StripANSI(string(p))})
+	return len(p), err
+}
+
+// decode lit les arguments d'une requête
+func decode(args json.RawMessage, v any) error {
+	if len(args) == 0 {
+		return nil
+	}
+	return json.Unmarshal(args, v)
+}
@@ -0,0 +1,227 @@
+package dap
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"basics/internal/dialect"
+	"basics/testutils"
+)
+
+// message est un message reçu par le client de test
+type message struct {
+	Type       string          `json:"type"`
+	Event      string          `json:"event"`
+	RequestSeq int             `json:"request_seq"`
+	Success    bool            `json:"success"`
+	Message    string          `json:"message"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// client pilote un adaptateur lancé dans le même processus
+type client struct {
+	t      *testing.T
+	conn   *conn
+	seq    int
+	events []message
+	done   chan error
+}
+
+func newClient(t *testing.T) *client {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+
+	c := &client{t: t, conn: newConn(outR, inW), done: make(chan error, 1)}
+	go func() {
+		err := NewServer(inR, outW, dialect.Applesoft).Serve()
+		outW.Close()
+		c.done <- err
+	}()
+	return c
+}
+
+// receive lit le message suivant, avec un délai maximum
+func (c *client) receive() message {
+	c.t.Helper()
+
+	type result struct {
+		body []byte
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		body, err := c.conn.readBody()
+		ch <- result{body, err}
+	}()
+
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			c.t.Fatalf("read: %v", r.err)
+		}
+		var msg message
+		if err := json.Unmarshal(r.body, &msg); err != nil {
+			c.t.Fatalf("unmarshal: %v", err)
+		}
+		return msg
+	case <-time.After(5 * time.Second):
+		c.t.Fatal("no message from the adapter")
+	}
+	return message{}
+}
+
+// request envoie une requête et attend sa réponse ; les événements reçus
+// entre-temps sont conservés
+func (c *client) request(command string, args any, body any) message {
+	c.t.Helper()
+
+	c.seq++
+	req := map[string]any{"seq": c.seq, "type": "request", "command": command, "arguments": args}
+	if err := c.conn.write(req, func(int) {}); err != nil {
+		c.t.Fatalf("write: %v", err)
+	}
+
+	for {
+		msg := c.receive()
+		if msg.Type == "event" {
+			c.events = append(c.events, msg)
+			continue
+		}
+		testutils.Equal(c.t, command+" answers its request", msg.RequestSeq, c.seq)
+		if body != nil && msg.Success {
+			if err := json.Unmarshal(msg.Body, body); err != nil {
+				c.t.Fatalf("body of %s: %v", command, err)
+			}
+		}
+		return msg
+	}
+}
+
+// event attend un événement
+func (c *client) event(name string) message {
+	c.t.Helper()
+
+	for {
+		for k, ev := range c.events {
+			if ev.Event == name {
+				c.events = append(c.events[:k], c.events[k+1:]...)
+				return ev
+			}
+		}
+		c.events = append(c.events, c.receive())
+	}
+}
+
+// stopped attend un arrêt et retourne sa cause
+func (c *client) stopped() string {
+	c.t.Helper()
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.Unmarshal(c.event("stopped").Body, &body)
+	return body.Reason
+}
+
+func (c *client) stackTrace() []StackFrame {
+	var body struct {
+		StackFrames []StackFrame `json:"stackFrames"`
+	}
+	c.request("stackTrace", map[string]any{"threadId": threadID}, &body)
+	return body.StackFrames
+}
+
+// session scriptée sur un exemple : table de 4 avec un GOSUB par tour
+func TestServer_DebugSession(t *testing.T) {
+	c := newClient(t)
+	program, _ := filepath.Abs(filepath.Join("..", "..", "examples", "flow_control", "gosub-03-example.bas"))
+
+	resp := c.request("initialize", map[string]any{"adapterID": "basics"}, nil)
+	testutils.True(t, "initialize", resp.Success)
+
+	resp = c.request("stackTrace", map[string]any{}, nil)
+	testutils.False(t, "no program yet", resp.Success)
+
+	resp = c.request("launch", LaunchArguments{Program: program, StopOnEntry: true}, nil)
+	testutils.True(t, "launch", resp.Success)
+	c.event("initialized")
+
+	// ligne 8 du fichier : 100 V = I * 4
+	var bps struct {
+		Breakpoints []Breakpoint `json:"breakpoints"`
+	}
+	c.request("setBreakpoints", SetBreakpointsArguments{
+		Source:      Source{Path: program},
+		Breakpoints: []SourceBreakpoint{{Line: 8, Condition: "I = 3"}, {Line: 42}},
+	}, &bps)
+	testutils.Equal(t, "breakpoints", len(bps.Breakpoints), 2)
+	testutils.True(t, "breakpoint verified", bps.Breakpoints[0].Verified)
+	testutils.False(t, "no line 42", bps.Breakpoints[1].Verified)
+
+	c.request("configurationDone", nil, nil)
+	testutils.Equal(t, "stop on entry", c.stopped(), "entry")
+
+	frames := c.stackTrace()
+	testutils.Equal(t, "entry frame after REM", frames[0].Line, 2)
+
+	c.request("continue", map[string]any{"threadId": threadID}, nil)
+	testutils.Equal(t, "conditional breakpoint", c.stopped(), "breakpoint")
+
+	frames = c.stackTrace()
+	testutils.Equal(t, "frames", len(frames), 2)
+	testutils.Equal(t, "subroutine frame", frames[0].Line, 8)
+	testutils.Equal(t, "subroutine name", frames[0].Name, "100 V = I*4")
+	testutils.Equal(t, "GOSUB frame", frames[1].Line, 4)
+
+	var vars struct {
+		Variables []Variable `json:"variables"`
+	}
+	c.request("variables", VariablesArguments{VariablesReference: variablesRef}, &vars)
+	found := false
+	for _, v := range vars.Variables {
+		found = found || (v.Name == "I" && v.Value == "3")
+	}
+	testutils.True(t, "variable I", found)
+
+	c.request("variables", VariablesArguments{VariablesReference: loopsRef}, &vars)
+	testutils.Equal(t, "loop", vars.Variables[0].Value, "TO 10 STEP 1 (line 20)")
+
+	var eval struct {
+		Result string `json:"result"`
+	}
+	c.request("evaluate", EvaluateArguments{Expression: "I * 4"}, &eval)
+	testutils.Equal(t, "evaluate", eval.Result, "12")
+	resp = c.request("evaluate", EvaluateArguments{Expression: "I *"}, nil)
+	testutils.False(t, "invalid expression", resp.Success)
+
+	c.request("stepOut", map[string]any{"threadId": threadID}, nil)
+	testutils.Equal(t, "step out", c.stopped(), "step")
+	testutils.Equal(t, "back after GOSUB", c.stackTrace()[0].Line, 5)
+
+	c.request("next", map[string]any{"threadId": threadID}, nil)
+	c.stopped()
+	testutils.Equal(t, "next", c.stackTrace()[0].Line, 6)
+
+	// sans point d'arrêt, jusqu'à la fin
+	c.request("setBreakpoints", SetBreakpointsArguments{Source: Source{Path: program}}, nil)
+	c.request("continue", map[string]any{"threadId": threadID}, nil)
+	c.event("terminated")
+
+	var out strings.Builder
+	for _, ev := range c.events {
+		if ev.Event == "output" {
+			var body struct {
+				Output string `json:"output"`
+			}
+			_ = json.Unmarshal(ev.Body, &body)
+			out.WriteString(body.Output)
+		}
+	}
+	testutils.True(t, "program output", strings.Contains(out.String(), "40"))
+
+	c.request("disconnect", nil, nil)
+	testutils.True(t, "serve", <-c.done == nil)
+}
@@ -0,0 +1,78 @@
+package diag
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"basics/internal/errors"
+	"basics/internal/parser"
+)
+
+// Diagnostic est une erreur d'analyse située dans le fichier source : la
+// zone s'arrête avant EndColumn
+type Diagnostic struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"endLine"`
+	EndColumn int    `json:"endColumn"`
+	Severity  string `json:"severity"`
+	Code      string `json:"code"` // lexical, syntax, semantic
+	Message   string `json:"message"`
+	Hint      string `json:"hint,omitempty"`
+}
+
+// FromErrors situe les erreurs du parser dans le fichier. prog est le
+// programme analysé, même partiel : il donne la ligne du fichier des
+// erreurs qui portent un numéro de ligne BASIC.
+func FromErrors(file string, prog *parser.Program, errs []*errors.Error) []Diagnostic {
+	lines := map[int]parser.Span{}
+	if prog != nil {
+		for _, l := range prog.Lines {
+			lines[l.Number] = l.Range() // la dernière ligne d'un numéro en double
+		}
+	}
+
+	diags := make([]Diagnostic, 0, len(errs))
+	for _, e := range errs {
+		d := Diagnostic{
+			File:     file,
+			Line:     e.Line,
+			Column:   e.Column,
+			Severity: "error",
+			Code:     strings.ToLower(strings.TrimSuffix(e.Kind.String(), " ERROR")),
+			Message:  e.Msg,
+			Hint:     e.Hint,
+		}
+		width := len([]rune(e.Token))
+
+		// ligne BASIC et non ligne du fichier (comme le serveur LSP)
+		if e.Kind == errors.Semantic || e.Msg == "MISSING NEXT" {
+			if span, ok := lines[e.Line]; ok {
+				d.Line = span.Start.Line
+				if d.Column == 0 {
+					// le numéro de la ligne
+					d.Column = span.Start.Column
+					width = len(strconv.Itoa(e.Line))
+				}
+			}
+		}
+
+		d.EndLine = d.Line
+		d.EndColumn = d.Column + max(width, 1)
+		diags = append(diags, d)
+	}
+	return diags
+}
+
+// WriteJSON écrit les diagnostics sous forme de tableau JSON
+func WriteJSON(w io.Writer, diags []Diagnostic) error {
+	out, err := json.MarshalIndent(diags, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(out, '\n'))
+	return err
+}
@@ -0,0 +1,11 @@
+package diag
+
+import (
+	"testing"
+
+	"basics/testutils"
+)
+
+func TestMain(m *testing.M) {
+	testutils.RunWithAssertTracking(m)
+}
@@ -0,0 +1,71 @@
+package diag
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"basics/internal/common"
+)
+
+// Render écrit les diagnostics à la manière d'un compilateur : le message,
+// la position, la ligne du source avec la zone soulignée et la suggestion.
+// color active les couleurs ANSI.
+func Render(w io.Writer, source string, diags []Diagnostic, color bool) error {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+
+	paint := func(s string, codes ...string) string {
+		if !color {
+			return s
+		}
+		return common.Colorize(s, codes...)
+	}
+
+	var sb strings.Builder
+	for _, d := range diags {
+		fmt.Fprintf(&sb, "%s%s\n", paint(d.Severity+":", common.Bold, common.Red), paint(" "+d.Message, common.Bold))
+
+		gutter := strings.Repeat(" ", len(strconv.Itoa(d.Line)))
+		fmt.Fprintf(&sb, "%s%s %s:%d:%d\n", gutter, paint("-->", common.Blue), d.File, d.Line, d.Column)
+
+		if d.Line >= 1 && d.Line <= len(lines) {
+			text := []rune(lines[d.Line-1])
+			bar := paint("|", common.Blue)
+			fmt.Fprintf(&sb, "%s %s\n", gutter, bar)
+			fmt.Fprintf(&sb, "%s %s %s\n", paint(strconv.Itoa(d.Line), common.Blue), bar, string(text))
+			fmt.Fprintf(&sb, "%s %s %s%s\n", gutter, bar, indent(text, d.Column), paint(underline(d), common.Bold, common.Red))
+		}
+
+		if d.Hint != "" {
+			fmt.Fprintf(&sb, "%s %s %s\n", gutter, paint("=", common.Blue), paint("help: "+d.Hint, common.Cyan))
+		}
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// indent reproduit le début de la ligne jusqu'à la colonne, tabulations
+// comprises, pour aligner le soulignement
+func indent(text []rune, column int) string {
+	var sb strings.Builder
+	for k := 0; k < column-1; k++ {
+		if k < len(text) && text[k] == '\t' {
+			sb.WriteRune('\t')
+		} else {
+			sb.WriteRune(' ')
+		}
+	}
+	return sb.String()
+}
+
+// underline souligne la zone du diagnostic sur sa première ligne
+func underline(d Diagnostic) string {
+	width := 1
+	if d.EndLine == d.Line && d.EndColumn > d.Column {
+		width = d.EndColumn - d.Column
+	}
+	return strings.Repeat("^", width)
+}
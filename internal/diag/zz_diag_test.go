@@ -0,0 +1,67 @@
+package diag
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"basics/internal/common"
+	"basics/internal/lexer"
+	"basics/internal/parser"
+	"basics/testutils"
+)
+
+const source = "10 PRNT \"A\"\n20 FOR I = 1 TO 2\n30 END\n30 END\n"
+
+func diagnostics(t *testing.T) []Diagnostic {
+	t.Helper()
+	prog, errs := parser.New(lexer.Lex(source)).ParseProgram()
+	return FromErrors("prog.bas", prog, errs)
+}
+
+func TestFromErrors(t *testing.T) {
+	diags := diagnostics(t)
+	testutils.Equal(t, "diagnostics", len(diags), 3)
+
+	testutils.Equal(t, "syntax", diags[0], Diagnostic{
+		File: "prog.bas", Line: 1, Column: 4, EndLine: 1, EndColumn: 8,
+		Severity: "error", Code: "syntax", Message: "EXPECTED '='", Hint: "did you mean PRINT?",
+	})
+
+	// ligne BASIC en double : le numéro de la seconde ligne 30
+	testutils.Equal(t, "duplicate line", diags[1].Line, 4)
+	testutils.Equal(t, "duplicate column", diags[1].Column, 1)
+	testutils.Equal(t, "duplicate end", diags[1].EndColumn, 3)
+	testutils.Equal(t, "semantic", diags[1].Code, "semantic")
+
+	// FOR sans NEXT : le mot-clé FOR, ligne du fichier
+	testutils.Equal(t, "missing NEXT line", diags[2].Line, 2)
+	testutils.Equal(t, "missing NEXT column", diags[2].Column, 4)
+}
+
+func TestRender(t *testing.T) {
+	var buf bytes.Buffer
+	testutils.True(t, "render", Render(&buf, source, diagnostics(t)[:1], false) == nil)
+
+	want := `error: EXPECTED '='
+ --> prog.bas:1:4
+  |
+1 | 10 PRNT "A"
+  |    ^^^^
+  = help: did you mean PRINT?
+
+`
+	testutils.Equal(t, "text", buf.String(), want)
+
+	buf.Reset()
+	testutils.True(t, "colour", Render(&buf, source, diagnostics(t)[:1], true) == nil)
+	testutils.True(t, "ANSI codes", strings.Contains(buf.String(), common.Red))
+	testutils.Equal(t, "same text", common.StripANSI(buf.String()), want)
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	testutils.True(t, "json", WriteJSON(&buf, diagnostics(t)[:1]) == nil)
+	testutils.True(t, "fields", strings.Contains(buf.String(), `"endColumn": 8,`))
+	testutils.True(t, "hint", strings.Contains(buf.String(), `"hint": "did you mean PRINT?"`))
+}
@@ -0,0 +1,66 @@
+package dialect
+
+import "basics/internal/constants"
+
+// Applesoft est le dialecte Applesoft BASIC (Apple II).
+// Seuls les 2 premiers caractères d'un nom de variable sont significatifs.
+var Applesoft = &Dialect{
+	Name:             "Applesoft BASIC",
+	BasicType:        constants.BASIC_APPLE,
+	Keywords:         applesoftKeywords,
+	Extensions:       extensions,
+	NameSignificance: 2,
+	Suffixes:         "$%",
+}
+
+var applesoftKeywords = map[string]bool{
+	// Contrôle
+	"FOR": true, "TO": true, "STEP": true, "NEXT": true,
+	"IF": true, "THEN": true, "ELSE": true,
+	"GOTO": true, "GOSUB": true, "RETURN": true, "POP": true,
+	"ON": true, "ONERR": true, "RESUME": true,
+	"END": true, "STOP": true,
+	"RUN": true, "CONT": true,
+
+	// Variables & logique
+	"LET": true, "DIM": true, "DEF": true, "FN": true,
+	"REM": true, "CLEAR": true,
+	"AND": true, "OR": true, "NOT": true,
+
+	// I/O
+	"PRINT": true, "INPUT": true,
+	"GET":  true,
+	"LOAD": true, "SAVE": true, "RECALL": true, "STORE": true,
+	"SHLOAD": true,
+
+	// Math
+	"SIN": true, "COS": true, "TAN": true, "ATN": true,
+	"INT": true, "ABS": true, "RND": true,
+	"SGN": true, "SQR": true, "EXP": true, "LOG": true,
+
+	// Chaînes
+	"LEN": true, "VAL": true, "ASC": true,
+	"STR$": true, "CHR$": true,
+	"LEFT$": true, "RIGHT$": true, "MID$": true,
+
+	// Graphique / écran
+	"GR": true, "HGR": true, "HGR2": true, "TEXT": true,
+	"PLOT": true, "HPLOT": true,
+	"HLIN": true, "VLIN": true, "AT": true,
+	"COLOR": true, "HCOLOR": true,
+	"DRAW": true, "XDRAW": true, "ROT": true, "SCALE": true,
+	"SCRN": true,
+	"HOME": true,
+
+	// DATA
+	"DATA": true, "READ": true, "RESTORE": true,
+
+	// Autres
+	"POKE": true, "PEEK": true, "CALL": true, "USR": true,
+	"WAIT": true, "FRE": true, "PDL": true, "POS": true,
+	"HIMEM": true, "LOMEM": true,
+	"TAB": true, "SPC": true, "VTAB": true, "HTAB": true,
+	"INVERSE": true, "NORMAL": true, "FLASH": true,
+	"SPEED": true, "TRACE": true, "NOTRACE": true,
+	"LIST": true, "NEW": true, "DEL": true,
+}
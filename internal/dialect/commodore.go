@@ -0,0 +1,53 @@
+package dialect
+
+import "basics/internal/constants"
+
+// CommodoreV2 est le dialecte Commodore BASIC V2 (Commodore 64).
+// Comme en Applesoft, seuls les 2 premiers caractères d'un nom de variable
+// sont significatifs.
+var CommodoreV2 = &Dialect{
+	Name:             "Commodore BASIC V2",
+	BasicType:        constants.BASIC_C64,
+	Keywords:         commodoreKeywords,
+	Extensions:       extensions,
+	NameSignificance: 2,
+	Suffixes:         "$%",
+}
+
+var commodoreKeywords = map[string]bool{
+	// Contrôle
+	"FOR": true, "TO": true, "STEP": true, "NEXT": true,
+	"IF": true, "THEN": true,
+	"GOTO": true, "GO": true, "GOSUB": true, "RETURN": true,
+	"ON": true, "END": true, "STOP": true,
+	"RUN": true, "CONT": true,
+
+	// Variables & logique
+	"LET": true, "DIM": true, "DEF": true, "FN": true,
+	"REM": true, "CLR": true,
+	"AND": true, "OR": true, "NOT": true,
+
+	// I/O
+	"PRINT": true, "INPUT": true, "GET": true,
+	"OPEN": true, "CLOSE": true, "CMD": true,
+	"LOAD": true, "SAVE": true, "VERIFY": true,
+
+	// Math
+	"SIN": true, "COS": true, "TAN": true, "ATN": true,
+	"INT": true, "ABS": true, "RND": true,
+	"SGN": true, "SQR": true, "EXP": true, "LOG": true,
+
+	// Chaînes
+	"LEN": true, "VAL": true, "ASC": true,
+	"STR$": true, "CHR$": true,
+	"LEFT$": true, "RIGHT$": true, "MID$": true,
+
+	// DATA
+	"DATA": true, "READ": true, "RESTORE": true,
+
+	// Autres
+	"POKE": true, "PEEK": true, "SYS": true, "USR": true,
+	"WAIT": true, "FRE": true, "POS": true,
+	"TAB": true, "SPC": true,
+	"LIST": true, "NEW": true,
+}
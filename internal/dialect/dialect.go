@@ -0,0 +1,115 @@
+package dialect
+
+import (
+	"strings"
+
+	"basics/internal/constants"
+)
+
+// Dialect décrit les règles lexicales et syntaxiques d'un BASIC :
+// mots-clés, significativité des noms de variables, suffixes de type
+// et instructions d'extension propres à BASICS.
+type Dialect struct {
+	Name      string
+	BasicType byte
+
+	// Mots réservés du BASIC d'origine
+	Keywords map[string]bool
+
+	// Instructions ajoutées par BASICS (ex: SLEEP)
+	Extensions map[string]bool
+
+	// Nombre de caractères significatifs d'un nom de variable
+	// (0 = tous les caractères sont significatifs)
+	NameSignificance int
+
+	// Suffixes de type autorisés en fin d'identifiant ($, %, !)
+	Suffixes string
+
+	// Source "crunchée" : les mots-clés sont reconnus n'importe où, même
+	// sans espace (10FORI=1TO10), comme le tokenizer de l'Apple II
+	Crunched bool
+
+	// Mots-clés reconnus aussi en minuscules (print), convertis en
+	// majuscules comme sur Apple IIe
+	Lowercase bool
+}
+
+// IsKeyword indique si le mot est réservé dans ce dialecte
+// (mot-clé d'origine ou extension BASICS)
+func (d *Dialect) IsKeyword(word string) bool {
+	return d.Keywords[word] || d.Extensions[word]
+}
+
+// IsExtension indique si le mot est une instruction d'extension BASICS
+func (d *Dialect) IsExtension(word string) bool {
+	return d.Extensions[word]
+}
+
+// IsSuffix indique si le caractère est un suffixe de type du dialecte
+func (d *Dialect) IsSuffix(ch rune) bool {
+	return strings.ContainsRune(d.Suffixes, ch)
+}
+
+// SignificantName retourne la partie significative d'un nom de variable :
+// les NameSignificance premiers caractères, suivis du suffixe de type.
+// Ex: en Applesoft, COUNT et CO désignent la même variable CO.
+func (d *Dialect) SignificantName(name string) string {
+	if d.NameSignificance <= 0 {
+		return name
+	}
+
+	base, suffix := name, ""
+	if n := len(name); n > 0 && d.IsSuffix(rune(name[n-1])) {
+		base, suffix = name[:n-1], name[n-1:]
+	}
+
+	if len(base) > d.NameSignificance {
+		base = base[:d.NameSignificance]
+	}
+	return base + suffix
+}
+
+// AllKeywords retourne la table complète des mots réservés
+func (d *Dialect) AllKeywords() map[string]bool {
+	all := make(map[string]bool, len(d.Keywords)+len(d.Extensions))
+	for kw := range d.Keywords {
+		all[kw] = true
+	}
+	for kw := range d.Extensions {
+		all[kw] = true
+	}
+	return all
+}
+
+// WithCrunched retourne une copie du dialecte dont le lexer reconnaît les
+// mots-clés sans espaces
+func (d *Dialect) WithCrunched() *Dialect {
+	c := *d
+	c.Crunched = true
+	return &c
+}
+
+// WithLowercase retourne une copie du dialecte dont le lexer reconnaît les
+// mots-clés en minuscules
+func (d *Dialect) WithLowercase() *Dialect {
+	c := *d
+	c.Lowercase = true
+	return &c
+}
+
+// ForType retourne le dialecte associé à un type BASIC (header BasicType).
+// Le mode TTY utilise le dialecte Applesoft.
+func ForType(basicType byte) *Dialect {
+	switch basicType {
+	case constants.BASIC_C64:
+		return CommodoreV2
+	case constants.BASIC_AMS:
+		return Locomotive
+	default:
+		return Applesoft
+	}
+}
+
+// Default est le dialecte utilisé quand aucun BASIC n'est précisé
+var Default = Applesoft
@@ -0,0 +1,7 @@
+package dialect
+
+// extensions liste les instructions ajoutées par BASICS, communes à tous
+// les dialectes
+var extensions = map[string]bool{
+	"SLEEP": true,
+}
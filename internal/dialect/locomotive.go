@@ -0,0 +1,60 @@
+package dialect
+
+import "basics/internal/constants"
+
+// Locomotive est le dialecte Locomotive BASIC 1.1 (Amstrad CPC 6128).
+// Les noms de variables sont significatifs sur toute leur longueur et le
+// suffixe ! désigne une variable réelle.
+var Locomotive = &Dialect{
+	Name:             "Locomotive BASIC 1.1",
+	BasicType:        constants.BASIC_AMS,
+	Keywords:         locomotiveKeywords,
+	Extensions:       extensions,
+	NameSignificance: 0,
+	Suffixes:         "$%!",
+}
+
+var locomotiveKeywords = map[string]bool{
+	// Contrôle
+	"FOR": true, "TO": true, "STEP": true, "NEXT": true,
+	"IF": true, "THEN": true, "ELSE": true,
+	"GOTO": true, "GOSUB": true, "RETURN": true,
+	"END": true, "STOP": true,
+	"WHILE": true, "WEND": true, "ON": true,
+	"AFTER": true, "EVERY": true,
+
+	// Variables & logique
+	"LET": true, "DIM": true, "ERASE": true,
+	"DEFINT": true, "DEFREAL": true, "DEFSTR": true,
+	"REM": true,
+	"AND": true, "OR": true, "XOR": true, "NOT": true, "MOD": true,
+
+	// I/O
+	"PRINT": true, "INPUT": true, "LINE": true,
+	"INKEY": true, "INKEY$": true,
+
+	// Math
+	"SIN": true, "COS": true, "TAN": true, "ATN": true,
+	"INT": true, "ABS": true, "RND": true, "FIX": true,
+	"SGN": true, "SQR": true, "EXP": true, "LOG": true,
+	"ROUND": true, "PI": true,
+
+	// Écran
+	"MODE": true, "CLS": true, "LOCATE": true,
+	"INK": true, "PEN": true, "PAPER": true, "BORDER": true,
+	"WINDOW": true,
+
+	// Graphique
+	"PLOT": true, "PLOTR": true,
+	"DRAW": true, "DRAWR": true,
+	"MOVE": true, "MOVER": true,
+	"CLG": true, "ORIGIN": true,
+
+	// DATA
+	"DATA": true, "READ": true, "RESTORE": true,
+
+	// Autres
+	"POKE": true, "PEEK": true, "CALL": true,
+	"TAB": true, "SPC": true,
+	"SOUND": true,
+}
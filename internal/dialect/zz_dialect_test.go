@@ -0,0 +1,129 @@
+package dialect
+
+import (
+	"testing"
+
+	"basics/internal/constants"
+	"basics/testutils"
+)
+
+func TestForType(t *testing.T) {
+	tests := []struct {
+		basicType byte
+		expected  *Dialect
+	}{
+		{constants.BASIC_TTY, Applesoft},
+		{constants.BASIC_APPLE, Applesoft},
+		{constants.BASIC_C64, CommodoreV2},
+		{constants.BASIC_AMS, Locomotive},
+		{0xFF, Applesoft},
+	}
+
+	for _, tt := range tests {
+		t.Run(constants.BasicName[tt.basicType], func(t *testing.T) {
+			testutils.True(t, "dialect", ForType(tt.basicType) == tt.expected)
+		})
+	}
+}
+
+func TestDialect_BasicType(t *testing.T) {
+	testutils.Equal(t, "Applesoft", Applesoft.BasicType, constants.BASIC_APPLE)
+	testutils.Equal(t, "Commodore", CommodoreV2.BasicType, constants.BASIC_C64)
+	testutils.Equal(t, "Locomotive", Locomotive.BasicType, constants.BASIC_AMS)
+}
+
+func TestDialect_IsKeyword(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  *Dialect
+		word     string
+		expected bool
+	}{
+		{"Applesoft HOME", Applesoft, "HOME", true},
+		{"Applesoft CLS", Applesoft, "CLS", false},
+		{"Commodore HOME", CommodoreV2, "HOME", false},
+		{"Commodore ELSE", CommodoreV2, "ELSE", false},
+		{"Commodore CHR$", CommodoreV2, "CHR$", true},
+		{"Locomotive CLS", Locomotive, "CLS", true},
+		{"Locomotive GET", Locomotive, "GET", false},
+		{"Applesoft SLEEP extension", Applesoft, "SLEEP", true},
+		{"Locomotive SLEEP extension", Locomotive, "SLEEP", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutils.Equal(t, tt.word, tt.dialect.IsKeyword(tt.word), tt.expected)
+		})
+	}
+}
+
+func TestDialect_Extensions(t *testing.T) {
+	testutils.True(t, "SLEEP is an extension", Applesoft.IsExtension("SLEEP"))
+	testutils.False(t, "PRINT is not an extension", Applesoft.IsExtension("PRINT"))
+	testutils.False(t, "SLEEP is not an Applesoft keyword", Applesoft.Keywords["SLEEP"])
+}
+
+func TestDialect_Suffixes(t *testing.T) {
+	testutils.True(t, "Applesoft $", Applesoft.IsSuffix('$'))
+	testutils.True(t, "Applesoft %", Applesoft.IsSuffix('%'))
+	testutils.False(t, "Applesoft !", Applesoft.IsSuffix('!'))
+	testutils.True(t, "Locomotive !", Locomotive.IsSuffix('!'))
+}
+
+func TestDialect_NameSignificance(t *testing.T) {
+	testutils.Equal(t, "Applesoft", Applesoft.NameSignificance, 2)
+	testutils.Equal(t, "Commodore", CommodoreV2.NameSignificance, 2)
+	testutils.Equal(t, "Locomotive", Locomotive.NameSignificance, 0)
+}
+
+func TestDialect_AllKeywords(t *testing.T) {
+	all := Applesoft.AllKeywords()
+
+	testutils.True(t, "contains PRINT", all["PRINT"])
+	testutils.True(t, "contains SLEEP", all["SLEEP"])
+	testutils.Equal(t, "size", len(all), len(Applesoft.Keywords)+len(Applesoft.Extensions))
+
+	// la table retournée est une copie
+	all["FOO"] = true
+	testutils.False(t, "copy is independent", Applesoft.IsKeyword("FOO"))
+}
+
+func TestDialect_SignificantName(t *testing.T) {
+	tests := []struct {
+		dialect  *Dialect
+		name     string
+		expected string
+	}{
+		{Applesoft, "COUNT", "CO"},
+		{Applesoft, "CO", "CO"},
+		{Applesoft, "C", "C"},
+		{Applesoft, "NAME$", "NA$"},
+		{Applesoft, "INDEX%", "IN%"},
+		{CommodoreV2, "SCORE", "SC"},
+		{Locomotive, "COUNT", "COUNT"},
+		{Locomotive, "RATE!", "RATE!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect.Name+" "+tt.name, func(t *testing.T) {
+			testutils.Equal(t, tt.name, tt.dialect.SignificantName(tt.name), tt.expected)
+		})
+	}
+}
+
+func TestDialect_WithCrunched(t *testing.T) {
+	d := Applesoft.WithCrunched()
+
+	testutils.True(t, "copy is crunched", d.Crunched)
+	testutils.False(t, "original is untouched", Applesoft.Crunched)
+	testutils.Equal(t, "same BASIC", d.BasicType, Applesoft.BasicType)
+	testutils.True(t, "same keywords", d.IsKeyword("HOME"))
+}
+
+func TestDialect_WithLowercase(t *testing.T) {
+	d := Applesoft.WithCrunched().WithLowercase()
+
+	testutils.True(t, "copy accepts lowercase", d.Lowercase)
+	testutils.True(t, "still crunched", d.Crunched)
+	testutils.False(t, "original is untouched", Applesoft.Lowercase)
+}
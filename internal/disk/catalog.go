@@ -0,0 +1,204 @@
+package disk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FileType est le type d'un fichier DOS 3.3
+type FileType byte
+
+const (
+	TypeText      FileType = 0x00 // T
+	TypeInteger   FileType = 0x01 // I
+	TypeApplesoft FileType = 0x02 // A
+	TypeBinary    FileType = 0x04 // B
+	TypeS         FileType = 0x08 // S
+	TypeRelocate  FileType = 0x10 // R
+	TypeNewA      FileType = 0x20 // A (nouveau)
+	TypeNewB      FileType = 0x40 // B (nouveau)
+
+	lockedFlag = 0x80
+)
+
+// Letter retourne la lettre affichée par CATALOG
+func (t FileType) Letter() string {
+	switch t {
+	case TypeText:
+		return "T"
+	case TypeInteger:
+		return "I"
+	case TypeApplesoft, TypeNewA:
+		return "A"
+	case TypeBinary, TypeNewB:
+		return "B"
+	case TypeS:
+		return "S"
+	case TypeRelocate:
+		return "R"
+	default:
+		return "?"
+	}
+}
+
+// Structure d'un secteur de catalogue
+const (
+	catalogNextTrack  = 0x01
+	catalogNextSector = 0x02
+	catalogEntries    = 0x0B
+	entrySize         = 0x23
+	entriesPerSector  = 7
+
+	entryTrack   = 0x00
+	entrySector  = 0x01
+	entryType    = 0x02
+	entryName    = 0x03
+	entryLength  = 0x21
+	nameLength   = 30
+	deletedTrack = 0xFF
+)
+
+// ErrFileNotFound est retournée quand un fichier est absent du catalogue
+var ErrFileNotFound = errors.New("FILE NOT FOUND")
+
+// Entry est une entrée du catalogue
+type Entry struct {
+	Name    string
+	Type    FileType
+	Locked  bool
+	Sectors int // longueur en secteurs, listes T/S comprises
+
+	track, sector int // première liste T/S
+	slot          []byte
+}
+
+// String retourne la ligne affichée par CATALOG
+func (e Entry) String() string {
+	lock := " "
+	if e.Locked {
+		lock = "*"
+	}
+	return fmt.Sprintf("%s%s %03d %s", lock, e.Type.Letter(), e.Sectors%1000, e.Name)
+}
+
+// Catalog retourne les fichiers de la disquette dans l'ordre du catalogue
+func (img *Image) Catalog() ([]Entry, error) {
+	var entries []Entry
+
+	err := img.eachSlot(func(slot []byte) bool {
+		if slot[entryTrack] != 0 && slot[entryTrack] != deletedTrack {
+			entries = append(entries, readEntry(slot))
+		}
+		return true
+	})
+
+	return entries, err
+}
+
+// Lookup retourne l'entrée d'un fichier. La casse du nom est ignorée.
+func (img *Image) Lookup(name string) (Entry, error) {
+	entries, err := img.Catalog()
+	if err != nil {
+		return Entry{}, err
+	}
+
+	for _, e := range entries {
+		if strings.EqualFold(e.Name, strings.TrimSpace(name)) {
+			return e, nil
+		}
+	}
+
+	return Entry{}, fmt.Errorf("%w: %s", ErrFileNotFound, name)
+}
+
+// eachSlot parcourt les emplacements du catalogue jusqu'à ce que fn
+// retourne false
+func (img *Image) eachSlot(fn func(slot []byte) bool) error {
+	v, err := img.readVTOC()
+	if err != nil {
+		return err
+	}
+
+	track, sector := int(v[vtocCatalogTrack]), int(v[vtocCatalogSector])
+	seen := map[int]bool{}
+
+	for track != 0 {
+		if seen[track*Sectors+sector] {
+			return fmt.Errorf("catalog loop at %d/%d", track, sector)
+		}
+		seen[track*Sectors+sector] = true
+
+		data, err := img.Sector(track, sector)
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < entriesPerSector; i++ {
+			start := catalogEntries + i*entrySize
+			if !fn(data[start : start+entrySize]) {
+				return nil
+			}
+		}
+
+		track, sector = int(data[catalogNextTrack]), int(data[catalogNextSector])
+	}
+
+	return nil
+}
+
+// readEntry décode une entrée du catalogue. Les noms sont stockés en
+// ASCII avec le bit 7 à 1, complétés par des espaces.
+func readEntry(slot []byte) Entry {
+	name := make([]byte, nameLength)
+	for i := range name {
+		name[i] = slot[entryName+i] & 0x7F
+	}
+
+	return Entry{
+		Name:    strings.TrimRight(string(name), " "),
+		Type:    FileType(slot[entryType] &^ lockedFlag),
+		Locked:  slot[entryType]&lockedFlag != 0,
+		Sectors: int(slot[entryLength]) | int(slot[entryLength+1])<<8,
+		track:   int(slot[entryTrack]),
+		sector:  int(slot[entrySector]),
+		slot:    slot,
+	}
+}
+
+// writeEntry écrit une entrée du catalogue
+func writeEntry(slot []byte, e Entry) {
+	slot[entryTrack] = byte(e.track)
+	slot[entrySector] = byte(e.sector)
+	slot[entryType] = byte(e.Type)
+	if e.Locked {
+		slot[entryType] |= lockedFlag
+	}
+
+	for i := 0; i < nameLength; i++ {
+		c := byte(' ')
+		if i < len(e.Name) {
+			c = e.Name[i]
+		}
+		slot[entryName+i] = c | 0x80
+	}
+
+	slot[entryLength] = byte(e.Sectors)
+	slot[entryLength+1] = byte(e.Sectors >> 8)
+}
+
+// validName vérifie qu'un nom de fichier peut être écrit au catalogue
+func validName(name string) error {
+	if name == "" || len(name) > nameLength {
+		return fmt.Errorf("invalid file name %q (1 to %d characters)", name, nameLength)
+	}
+	if name[0] < 'A' || name[0] > 'Z' {
+		return fmt.Errorf("invalid file name %q (must start with a letter)", name)
+	}
+	for _, r := range name {
+		if r < 0x20 || r >= 0x7F || r == ',' {
+			return fmt.Errorf("invalid file name %q", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,330 @@
+package disk
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Structure d'un secteur de liste T/S (track/sector)
+const (
+	listNextTrack  = 0x01
+	listNextSector = 0x02
+	listOffset     = 0x05
+	listPairs      = 0x0C
+	pairsPerList   = 122
+)
+
+// ErrFileLocked est retournée quand on remplace un fichier verrouillé
+var ErrFileLocked = errors.New("FILE LOCKED")
+
+// File est un fichier lu sur la disquette
+type File struct {
+	Entry
+
+	// Address est l'adresse de chargement d'un fichier binaire (B)
+	Address int
+
+	// Data est le contenu utile du fichier : programme tokenisé (A),
+	// données (B), texte ASCII avec des fins de ligne '\n' (T) ou contenu
+	// brut pour les autres types.
+	Data []byte
+}
+
+// ReadFile lit et décode un fichier du catalogue
+func (img *Image) ReadFile(name string) (*File, error) {
+	entry, err := img.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := img.readSectors(entry)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", entry.Name, err)
+	}
+
+	f := &File{Entry: entry}
+
+	switch entry.Type {
+	case TypeApplesoft, TypeInteger, TypeNewA:
+		// longueur sur 2 octets puis programme
+		if len(raw) < 2 {
+			return nil, fmt.Errorf("%s: missing program length", entry.Name)
+		}
+		length := int(binary.LittleEndian.Uint16(raw))
+		if 2+length > len(raw) {
+			return nil, fmt.Errorf("%s: program length %d exceeds file size", entry.Name, length)
+		}
+		f.Data = raw[2 : 2+length]
+
+	case TypeBinary, TypeNewB:
+		// adresse et longueur sur 2 octets puis données
+		if len(raw) < 4 {
+			return nil, fmt.Errorf("%s: missing binary header", entry.Name)
+		}
+		f.Address = int(binary.LittleEndian.Uint16(raw))
+		length := int(binary.LittleEndian.Uint16(raw[2:]))
+		if 4+length > len(raw) {
+			return nil, fmt.Errorf("%s: binary length %d exceeds file size", entry.Name, length)
+		}
+		f.Data = raw[4 : 4+length]
+
+	case TypeText:
+		f.Data = decodeText(raw)
+
+	default:
+		f.Data = raw
+	}
+
+	return f, nil
+}
+
+// readSectors retourne les secteurs de données d'un fichier, dans l'ordre
+// des listes T/S. Les trous des fichiers texte à accès direct sont
+// remplis de zéros.
+func (img *Image) readSectors(entry Entry) ([]byte, error) {
+	var data []byte
+	used := 0
+
+	err := img.eachList(entry, func(list []byte) error {
+		for i := 0; i < pairsPerList; i++ {
+			track := int(list[listPairs+2*i])
+			sector := int(list[listPairs+2*i+1])
+
+			if track == 0 {
+				data = append(data, make([]byte, SectorSize)...)
+				continue
+			}
+
+			s, err := img.Sector(track, sector)
+			if err != nil {
+				return err
+			}
+			data = append(data, s...)
+			used = len(data)
+		}
+		return nil
+	})
+
+	return data[:used], err
+}
+
+// eachList parcourt les listes T/S d'un fichier
+func (img *Image) eachList(entry Entry, fn func(list []byte) error) error {
+	track, sector := entry.track, entry.sector
+	seen := map[int]bool{}
+
+	for track != 0 {
+		if seen[track*Sectors+sector] {
+			return fmt.Errorf("track/sector list loop at %d/%d", track, sector)
+		}
+		seen[track*Sectors+sector] = true
+
+		list, err := img.Sector(track, sector)
+		if err != nil {
+			return err
+		}
+		if err := fn(list); err != nil {
+			return err
+		}
+
+		track, sector = int(list[listNextTrack]), int(list[listNextSector])
+	}
+
+	return nil
+}
+
+// WriteApplesoft enregistre un programme Applesoft tokenisé, comme SAVE
+func (img *Image) WriteApplesoft(name string, program []byte) error {
+	raw := binary.LittleEndian.AppendUint16(nil, uint16(len(program)))
+	return img.WriteFile(name, TypeApplesoft, append(raw, program...))
+}
+
+// WriteBinary enregistre un fichier binaire, comme BSAVE
+func (img *Image) WriteBinary(name string, address int, data []byte) error {
+	raw := binary.LittleEndian.AppendUint16(nil, uint16(address))
+	raw = binary.LittleEndian.AppendUint16(raw, uint16(len(data)))
+	return img.WriteFile(name, TypeBinary, append(raw, data...))
+}
+
+// WriteText enregistre un fichier texte
+func (img *Image) WriteText(name string, text string) error {
+	raw, err := encodeText(text)
+	if err != nil {
+		return err
+	}
+	return img.WriteFile(name, TypeText, raw)
+}
+
+// WriteFile enregistre le contenu brut d'un fichier. Un fichier existant
+// du même nom est remplacé, sauf s'il est verrouillé.
+func (img *Image) WriteFile(name string, t FileType, raw []byte) error {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if err := validName(name); err != nil {
+		return err
+	}
+
+	dataSectors := (len(raw) + SectorSize - 1) / SectorSize
+	lists := max(1, (dataSectors+pairsPerList-1)/pairsPerList)
+
+	available := img.FreeSectors()
+	existing, err := img.Lookup(name)
+	switch {
+	case err == nil:
+		if existing.Locked {
+			return fmt.Errorf("%w: %s", ErrFileLocked, name)
+		}
+		available += existing.Sectors
+	case !errors.Is(err, ErrFileNotFound):
+		return err
+	}
+
+	if available < dataSectors+lists {
+		return ErrDiskFull
+	}
+
+	if err == nil {
+		if err := img.Delete(name); err != nil {
+			return err
+		}
+	}
+
+	slot, err := img.freeSlot()
+	if err != nil {
+		return err
+	}
+
+	entry := Entry{Name: name, Type: t, Sectors: dataSectors + lists}
+	var list []byte
+
+	for i := 0; i < dataSectors; i++ {
+		if i%pairsPerList == 0 {
+			track, sector, err := img.allocate()
+			if err != nil {
+				return err
+			}
+			if list == nil {
+				entry.track, entry.sector = track, sector
+			} else {
+				list[listNextTrack] = byte(track)
+				list[listNextSector] = byte(sector)
+			}
+			list, _ = img.Sector(track, sector)
+			binary.LittleEndian.PutUint16(list[listOffset:], uint16(i))
+		}
+
+		track, sector, err := img.allocate()
+		if err != nil {
+			return err
+		}
+		pair := listPairs + 2*(i%pairsPerList)
+		list[pair] = byte(track)
+		list[pair+1] = byte(sector)
+
+		data, _ := img.Sector(track, sector)
+		copy(data, raw[i*SectorSize:])
+	}
+
+	// fichier vide : une liste T/S sans secteur de données
+	if list == nil {
+		track, sector, err := img.allocate()
+		if err != nil {
+			return err
+		}
+		entry.track, entry.sector = track, sector
+	}
+
+	writeEntry(slot, entry)
+	return nil
+}
+
+// Delete supprime un fichier et libère ses secteurs, comme DELETE
+func (img *Image) Delete(name string) error {
+	entry, err := img.Lookup(name)
+	if err != nil {
+		return err
+	}
+	if entry.Locked {
+		return fmt.Errorf("%w: %s", ErrFileLocked, entry.Name)
+	}
+
+	err = img.eachList(entry, func(list []byte) error {
+		for i := 0; i < pairsPerList; i++ {
+			if track := int(list[listPairs+2*i]); track != 0 {
+				img.release(track, int(list[listPairs+2*i+1]))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// chaînage déjà vérifié par eachList
+	for track, sector := entry.track, entry.sector; track != 0; {
+		list, _ := img.Sector(track, sector)
+		img.release(track, sector)
+		track, sector = int(list[listNextTrack]), int(list[listNextSector])
+	}
+
+	// DOS garde la piste d'origine dans le dernier octet du nom
+	entry.slot[entryName+nameLength-1] = byte(entry.track)
+	entry.slot[entryTrack] = deletedTrack
+	return nil
+}
+
+// freeSlot retourne le premier emplacement libre du catalogue
+func (img *Image) freeSlot() ([]byte, error) {
+	var free []byte
+
+	err := img.eachSlot(func(slot []byte) bool {
+		if slot[entryTrack] == 0 || slot[entryTrack] == deletedTrack {
+			free = slot
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if free == nil {
+		return nil, fmt.Errorf("%w: catalog is full", ErrDiskFull)
+	}
+
+	return free, nil
+}
+
+// decodeText convertit un fichier texte DOS (bit 7 à 1, fin de ligne
+// CR, terminé par 0) en texte ASCII
+func decodeText(raw []byte) []byte {
+	var text []byte
+	for _, b := range raw {
+		if b == 0 {
+			break
+		}
+		b &= 0x7F
+		if b == '\r' {
+			b = '\n'
+		}
+		text = append(text, b)
+	}
+	return text
+}
+
+// encodeText est l'inverse de decodeText
+func encodeText(text string) ([]byte, error) {
+	var raw []byte
+	for _, r := range text {
+		switch {
+		case r == '\r':
+			continue
+		case r == '\n':
+			r = '\r'
+		case r >= 0x80 || r == 0:
+			return nil, fmt.Errorf("character %q is not available on Apple II", r)
+		}
+		raw = append(raw, byte(r)|0x80)
+	}
+	return append(raw, 0), nil
+}
@@ -0,0 +1,117 @@
+package disk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Géométrie d'une disquette 5"1/4 DOS 3.3 (140 Ko)
+const (
+	Tracks     = 35
+	Sectors    = 16
+	SectorSize = 256
+	ImageSize  = Tracks * Sectors * SectorSize
+)
+
+// Order est l'ordre des secteurs dans le fichier image
+type Order int
+
+const (
+	// DOSOrder : secteurs logiques DOS 3.3 (.dsk, .do)
+	DOSOrder Order = iota
+	// ProDOSOrder : blocs ProDOS (.po)
+	ProDOSOrder
+)
+
+// Entrelacement 16 secteurs : numéro de secteur physique (sur la piste)
+// de chaque secteur logique DOS 3.3 et de chaque demi-bloc ProDOS.
+var (
+	dosPhysical    = [Sectors]int{0, 13, 11, 9, 7, 5, 3, 1, 14, 12, 10, 8, 6, 4, 2, 15}
+	prodosPhysical = [Sectors]int{0, 2, 4, 6, 8, 10, 12, 14, 1, 3, 5, 7, 9, 11, 13, 15}
+)
+
+// Physical retourne le secteur physique d'un secteur logique DOS 3.3
+func Physical(sector int) int {
+	return dosPhysical[sector]
+}
+
+// Image est une image de disquette DOS 3.3 en mémoire. Les secteurs sont
+// toujours adressés en numérotation logique DOS.
+type Image struct {
+	data  []byte
+	order Order
+}
+
+// Read construit une image à partir du contenu d'un fichier
+func Read(data []byte, order Order) (*Image, error) {
+	if len(data) != ImageSize {
+		return nil, fmt.Errorf("invalid disk image size %d (expected %d)", len(data), ImageSize)
+	}
+
+	img := &Image{data: append([]byte(nil), data...), order: order}
+
+	if _, err := img.readVTOC(); err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
+// Open lit un fichier image. L'ordre des secteurs dépend de l'extension.
+func Open(path string) (*Image, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Read(data, OrderOf(path))
+}
+
+// OrderOf retourne l'ordre des secteurs d'après l'extension du fichier
+func OrderOf(path string) Order {
+	if strings.ToLower(filepath.Ext(path)) == ".po" {
+		return ProDOSOrder
+	}
+	return DOSOrder
+}
+
+// Bytes retourne le contenu du fichier image
+func (img *Image) Bytes() []byte {
+	return img.data
+}
+
+// Save écrit l'image dans un fichier
+func (img *Image) Save(path string) error {
+	return os.WriteFile(path, img.data, 0o644)
+}
+
+// Sector retourne le secteur logique demandé. La tranche retournée partage
+// la mémoire de l'image : la modifier modifie la disquette.
+func (img *Image) Sector(track, sector int) ([]byte, error) {
+	if track < 0 || track >= Tracks || sector < 0 || sector >= Sectors {
+		return nil, fmt.Errorf("invalid track/sector %d/%d", track, sector)
+	}
+
+	offset := img.offset(track, sector)
+	return img.data[offset : offset+SectorSize], nil
+}
+
+// offset retourne la position d'un secteur logique dans le fichier image
+func (img *Image) offset(track, sector int) int {
+	pos := sector
+
+	if img.order == ProDOSOrder {
+		// secteur logique → physique → demi-bloc ProDOS
+		physical := dosPhysical[sector]
+		for i, p := range prodosPhysical {
+			if p == physical {
+				pos = i
+				break
+			}
+		}
+	}
+
+	return (track*Sectors + pos) * SectorSize
+}
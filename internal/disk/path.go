@@ -0,0 +1,37 @@
+package disk
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// extensions des fichiers image reconnus
+var imageExtensions = []string{".dsk", ".do", ".po"}
+
+// IsImage indique si le chemin désigne une image de disquette
+func IsImage(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range imageExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitPath sépare une référence "image.dsk:FICHIER" en chemin de l'image
+// et nom du fichier sur la disquette. ok est faux si la référence ne
+// désigne pas un fichier dans une image.
+func SplitPath(ref string) (image, name string, ok bool) {
+	lower := strings.ToLower(ref)
+
+	for _, ext := range imageExtensions {
+		if i := strings.LastIndex(lower, ext+":"); i >= 0 {
+			image = ref[:i+len(ext)]
+			name = ref[i+len(ext)+1:]
+			return image, name, name != ""
+		}
+	}
+
+	return "", "", false
+}
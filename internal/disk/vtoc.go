@@ -0,0 +1,177 @@
+package disk
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Emplacement de la VTOC (Volume Table Of Contents) et du catalogue
+const (
+	VTOCTrack  = 17
+	VTOCSector = 0
+
+	// DefaultVolume est le numéro de volume donné par INIT
+	DefaultVolume = 254
+)
+
+// Champs de la VTOC
+const (
+	vtocCatalogTrack  = 0x01
+	vtocCatalogSector = 0x02
+	vtocRelease       = 0x03
+	vtocVolume        = 0x06
+	vtocMaxPairs      = 0x27
+	vtocLastTrack     = 0x30
+	vtocDirection     = 0x31
+	vtocTracks        = 0x34
+	vtocSectors       = 0x35
+	vtocSectorSize    = 0x36
+	vtocBitmap        = 0x38
+)
+
+// ErrDiskFull est retournée quand il n'y a plus de secteur libre
+var ErrDiskFull = errors.New("DISK FULL")
+
+// vtoc donne accès au secteur de la VTOC
+type vtoc []byte
+
+// New retourne une disquette DOS 3.3 vierge et formatée : VTOC et
+// catalogue vides. Les pistes 0 à 2 sont réservées à DOS mais ne
+// contiennent pas d'image du système : la disquette n'est pas amorçable.
+func New() *Image {
+	img := &Image{data: make([]byte, ImageSize), order: DOSOrder}
+
+	v, _ := img.Sector(VTOCTrack, VTOCSector)
+	v[vtocCatalogTrack] = VTOCTrack
+	v[vtocCatalogSector] = Sectors - 1
+	v[vtocRelease] = 3
+	v[vtocVolume] = DefaultVolume
+	v[vtocMaxPairs] = pairsPerList
+	v[vtocLastTrack] = VTOCTrack
+	v[vtocDirection] = 1
+	v[vtocTracks] = Tracks
+	v[vtocSectors] = Sectors
+	v[vtocSectorSize] = SectorSize & 0xFF
+	v[vtocSectorSize+1] = SectorSize >> 8
+
+	for track := 3; track < Tracks; track++ {
+		if track == VTOCTrack {
+			continue
+		}
+		for sector := 0; sector < Sectors; sector++ {
+			vtoc(v).setFree(track, sector, true)
+		}
+	}
+
+	// Secteurs du catalogue chaînés de 15 à 1
+	for sector := Sectors - 1; sector > 0; sector-- {
+		cat, _ := img.Sector(VTOCTrack, sector)
+		if sector > 1 {
+			cat[catalogNextTrack] = VTOCTrack
+			cat[catalogNextSector] = byte(sector - 1)
+		}
+	}
+
+	return img
+}
+
+// readVTOC retourne la VTOC après vérification de la géométrie
+func (img *Image) readVTOC() (vtoc, error) {
+	v, _ := img.Sector(VTOCTrack, VTOCSector)
+
+	if v[vtocTracks] != Tracks || v[vtocSectors] != Sectors {
+		return nil, fmt.Errorf("not a DOS 3.3 disk (%d tracks, %d sectors)", v[vtocTracks], v[vtocSectors])
+	}
+	if int(v[vtocCatalogTrack]) >= Tracks || int(v[vtocCatalogSector]) >= Sectors {
+		return nil, fmt.Errorf("invalid catalog location %d/%d", v[vtocCatalogTrack], v[vtocCatalogSector])
+	}
+
+	return vtoc(v), nil
+}
+
+// Volume retourne le numéro de volume de la disquette
+func (img *Image) Volume() int {
+	v, _ := img.Sector(VTOCTrack, VTOCSector)
+	return int(v[vtocVolume])
+}
+
+// FreeSectors retourne le nombre de secteurs libres
+func (img *Image) FreeSectors() int {
+	v, _ := img.readVTOC()
+
+	count := 0
+	for track := 0; track < Tracks; track++ {
+		for sector := 0; sector < Sectors; sector++ {
+			if v.isFree(track, sector) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// bitmapBit retourne la position du bit d'un secteur : 4 octets par
+// piste, le bit 7 du premier octet correspond au secteur 15.
+func bitmapBit(track, sector int) (int, byte) {
+	pos := vtocBitmap + track*4
+	if sector < 8 {
+		pos++
+	}
+	return pos, 1 << (sector % 8)
+}
+
+func (v vtoc) isFree(track, sector int) bool {
+	pos, mask := bitmapBit(track, sector)
+	return v[pos]&mask != 0
+}
+
+func (v vtoc) setFree(track, sector int, free bool) {
+	pos, mask := bitmapBit(track, sector)
+	if free {
+		v[pos] |= mask
+	} else {
+		v[pos] &^= mask
+	}
+}
+
+// allocate réserve un secteur libre. Comme DOS, la recherche part de la
+// piste du catalogue et s'en éloigne, en commençant par le secteur 15.
+func (img *Image) allocate() (int, int, error) {
+	v, err := img.readVTOC()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for distance := 1; distance < Tracks; distance++ {
+		for _, track := range []int{VTOCTrack + distance, VTOCTrack - distance} {
+			if track < 0 || track >= Tracks {
+				continue
+			}
+			for sector := Sectors - 1; sector >= 0; sector-- {
+				if v.isFree(track, sector) {
+					v.setFree(track, sector, false)
+					v[vtocLastTrack] = byte(track)
+					if track > VTOCTrack {
+						v[vtocDirection] = 1
+					} else {
+						v[vtocDirection] = 0xFF
+					}
+
+					data, _ := img.Sector(track, sector)
+					clear(data)
+					return track, sector, nil
+				}
+			}
+		}
+	}
+
+	return 0, 0, ErrDiskFull
+}
+
+// release libère un secteur
+func (img *Image) release(track, sector int) {
+	v, err := img.readVTOC()
+	if err == nil {
+		v.setFree(track, sector, true)
+	}
+}
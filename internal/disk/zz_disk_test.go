@@ -0,0 +1,216 @@
+package disk
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"basics/testutils"
+)
+
+// 10 PRINT "HELLO", tel que sauvegardé par un Apple II
+var helloProgram = []byte{
+	0x0E, 0x08, 0x0A, 0x00, 0xBA, '"', 'H', 'E', 'L', 'L', 'O', '"', 0x00,
+	0x00, 0x00,
+}
+
+func TestNew_EmptyCatalog(t *testing.T) {
+	img := New()
+
+	entries, err := img.Catalog()
+	testutils.True(t, "no error", err == nil)
+	testutils.Equal(t, "no file", len(entries), 0)
+	testutils.Equal(t, "volume", img.Volume(), DefaultVolume)
+
+	// pistes 0 à 2 (DOS) et 17 (catalogue) réservées
+	testutils.Equal(t, "free sectors", img.FreeSectors(), (Tracks-4)*Sectors)
+	testutils.Equal(t, "image size", len(img.Bytes()), ImageSize)
+}
+
+func TestRead_InvalidImage(t *testing.T) {
+	_, err := Read(make([]byte, 1000), DOSOrder)
+	testutils.True(t, "bad size", err != nil)
+
+	_, err = Read(make([]byte, ImageSize), DOSOrder)
+	testutils.True(t, "no VTOC", err != nil)
+}
+
+func TestWriteApplesoft_ReadBack(t *testing.T) {
+	img := New()
+
+	err := img.WriteApplesoft("hello", helloProgram)
+	testutils.True(t, "no error", err == nil)
+
+	f, err := img.ReadFile("HELLO")
+	testutils.True(t, "no read error", err == nil)
+	testutils.Equal(t, "name", f.Name, "HELLO")
+	testutils.Equal(t, "type", f.Type, TypeApplesoft)
+	testutils.Equal(t, "sectors", f.Sectors, 2)
+	testutils.True(t, "content", bytes.Equal(f.Data, helloProgram))
+	testutils.Equal(t, "catalog line", f.String(), " A 002 HELLO")
+}
+
+func TestWriteFile_ReplaceExisting(t *testing.T) {
+	img := New()
+	free := img.FreeSectors()
+
+	testutils.True(t, "first write", img.WriteApplesoft("HELLO", helloProgram) == nil)
+	testutils.True(t, "second write", img.WriteApplesoft("HELLO", helloProgram) == nil)
+
+	entries, _ := img.Catalog()
+	testutils.Equal(t, "one file", len(entries), 1)
+	testutils.Equal(t, "sectors used", img.FreeSectors(), free-2)
+}
+
+func TestWriteFile_LargeFile(t *testing.T) {
+	img := New()
+
+	// 130 secteurs de données → 2 listes T/S
+	data := make([]byte, 130*SectorSize-4)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+
+	err := img.WriteBinary("BIG", 0x2000, data)
+	testutils.True(t, "no error", err == nil)
+
+	f, err := img.ReadFile("BIG")
+	testutils.True(t, "no read error", err == nil)
+	testutils.Equal(t, "address", f.Address, 0x2000)
+	testutils.Equal(t, "sectors", f.Sectors, 132)
+	testutils.True(t, "content", bytes.Equal(f.Data, data))
+}
+
+func TestWriteFile_DiskFull(t *testing.T) {
+	img := New()
+
+	err := img.WriteBinary("BIG", 0x2000, make([]byte, 0xFFFF))
+	testutils.True(t, "first file fits", err == nil)
+
+	err = img.WriteBinary("BIG2", 0x2000, make([]byte, 0xFFFF))
+	testutils.True(t, "disk full", errors.Is(err, ErrDiskFull))
+}
+
+func TestWriteText_ReadBack(t *testing.T) {
+	img := New()
+
+	err := img.WriteText("NOTES", "HELLO\nWORLD\n")
+	testutils.True(t, "no error", err == nil)
+
+	raw, _ := img.readSectors(mustLookup(t, img, "NOTES"))
+	testutils.Equal(t, "high ASCII", raw[0], byte('H'|0x80))
+	testutils.Equal(t, "carriage return", raw[5], byte(0x8D))
+
+	f, _ := img.ReadFile("NOTES")
+	testutils.Equal(t, "text", string(f.Data), "HELLO\nWORLD\n")
+
+	testutils.True(t, "accents refused", img.WriteText("E", "ÉTÉ") != nil)
+}
+
+func TestDelete(t *testing.T) {
+	img := New()
+	free := img.FreeSectors()
+
+	_ = img.WriteApplesoft("HELLO", helloProgram)
+	testutils.True(t, "no error", img.Delete("HELLO") == nil)
+
+	entries, _ := img.Catalog()
+	testutils.Equal(t, "no file", len(entries), 0)
+	testutils.Equal(t, "sectors released", img.FreeSectors(), free)
+
+	_, err := img.ReadFile("HELLO")
+	testutils.True(t, "not found", errors.Is(err, ErrFileNotFound))
+}
+
+func TestWriteFile_Locked(t *testing.T) {
+	img := New()
+	_ = img.WriteApplesoft("HELLO", helloProgram)
+
+	e := mustLookup(t, img, "HELLO")
+	e.slot[entryType] |= lockedFlag
+
+	err := img.WriteApplesoft("HELLO", helloProgram)
+	testutils.True(t, "locked", errors.Is(err, ErrFileLocked))
+	testutils.Equal(t, "catalog line", mustLookup(t, img, "HELLO").String(), "*A 002 HELLO")
+}
+
+func TestWriteFile_InvalidName(t *testing.T) {
+	img := New()
+
+	testutils.True(t, "empty", img.WriteApplesoft("", helloProgram) != nil)
+	testutils.True(t, "digit", img.WriteApplesoft("1HELLO", helloProgram) != nil)
+	testutils.True(t, "comma", img.WriteApplesoft("A,B", helloProgram) != nil)
+	testutils.True(t, "too long", img.WriteApplesoft("ABCDEFGHIJKLMNOPQRSTUVWXYZABCDE", helloProgram) != nil)
+}
+
+func TestProDOSOrder(t *testing.T) {
+	img := New()
+	_ = img.WriteApplesoft("HELLO", helloProgram)
+
+	// conversion .do → .po : chaque secteur logique change de place
+	po := make([]byte, ImageSize)
+	conv := &Image{data: po, order: ProDOSOrder}
+	for track := 0; track < Tracks; track++ {
+		for sector := 0; sector < Sectors; sector++ {
+			src, _ := img.Sector(track, sector)
+			dst, _ := conv.Sector(track, sector)
+			copy(dst, src)
+		}
+	}
+
+	testutils.False(t, "layout differs", bytes.Equal(po, img.Bytes()))
+
+	read, err := Read(po, ProDOSOrder)
+	testutils.True(t, "no error", err == nil)
+	f, err := read.ReadFile("HELLO")
+	testutils.True(t, "no read error", err == nil)
+	testutils.True(t, "content", bytes.Equal(f.Data, helloProgram))
+}
+
+func TestPhysical(t *testing.T) {
+	testutils.Equal(t, "sector 0", Physical(0), 0)
+	testutils.Equal(t, "sector 1", Physical(1), 13)
+	testutils.Equal(t, "sector 15", Physical(15), 15)
+}
+
+func TestOpenSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "game.dsk")
+
+	img := New()
+	_ = img.WriteApplesoft("HELLO", helloProgram)
+	testutils.True(t, "saved", img.Save(path) == nil)
+
+	read, err := Open(path)
+	testutils.True(t, "no error", err == nil)
+	_, err = read.Lookup("hello")
+	testutils.True(t, "found", err == nil)
+}
+
+func TestSplitPath(t *testing.T) {
+	image, name, ok := SplitPath("games/game.dsk:HELLO")
+	testutils.True(t, "ok", ok)
+	testutils.Equal(t, "image", image, "games/game.dsk")
+	testutils.Equal(t, "name", name, "HELLO")
+
+	image, name, ok = SplitPath(`C:\apple\GAME.DO:MY PROG`)
+	testutils.True(t, "ok", ok)
+	testutils.Equal(t, "image", image, `C:\apple\GAME.DO`)
+	testutils.Equal(t, "name", name, "MY PROG")
+
+	_, _, ok = SplitPath("game.dsk")
+	testutils.False(t, "no file", ok)
+	_, _, ok = SplitPath("hello.bas")
+	testutils.False(t, "not an image", ok)
+
+	testutils.True(t, "image", IsImage("GAME.PO"))
+	testutils.False(t, "source", IsImage("hello.bas"))
+}
+
+func mustLookup(t *testing.T, img *Image, name string) Entry {
+	e, err := img.Lookup(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
@@ -8,6 +8,7 @@ type Error struct {
 	Column int
 	Token  string
 	Msg    string
+	Hint   string // suggestion de correction, facultative
 }
 
 func (e *Error) Error() string {
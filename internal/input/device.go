@@ -1,5 +1,10 @@
 package input
 
+import "errors"
+
+// ErrBreak est retournée par une saisie interrompue par Ctrl-C
+var ErrBreak = errors.New("BREAK")
+
 type Device interface {
 	ReadLine() (string, error)
 	GetChar() (rune, error)
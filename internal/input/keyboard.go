@@ -0,0 +1,221 @@
+package input
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Echo affiche sur l'écran la saisie d'un Keyboard
+type Echo interface {
+	EchoRune(r rune)     // caractère tapé
+	EchoBackspace() bool // efface le dernier caractère ; false si impossible
+	EchoNewLine()        // ligne validée
+	HideCursor()         // efface le curseur dessiné dans l'écran
+}
+
+// blinkFrames est la demi-période du curseur (~0.5s à 60 FPS)
+const blinkFrames = 30
+
+// Keyboard est la saisie au clavier des écrans Ebiten : la ligne de INPUT,
+// le caractère de GET, Ctrl-C et le curseur clignotant. L'écran l'embarque
+// et affiche la saisie (Echo).
+//
+// Les touches arrivent dans la goroutine d'Ebiten (Update), ReadLine et
+// GetChar attendent dans celle de l'interpréteur : l'état partagé est
+// protégé par mu ou atomique.
+type Keyboard struct {
+	echo Echo
+
+	// For INPUT
+	mu          sync.Mutex
+	inputBuffer []rune
+	lineReady   bool
+
+	// For GET
+	getActive atomic.Bool
+	getChan   chan rune
+
+	// Ctrl-C pendant une saisie
+	breakReq atomic.Bool
+
+	// Blinking cursor : visible pendant les blinkFrames premières frames
+	// de chaque période
+	blinkCounter atomic.Int32
+	inInput      atomic.Bool
+
+	// Input is allowed
+	allowInput atomic.Bool
+}
+
+func NewKeyboard(echo Echo) *Keyboard {
+	return &Keyboard{
+		echo:        echo,
+		inputBuffer: make([]rune, 0, 64),
+		getChan:     make(chan rune, 1),
+	}
+}
+
+func (k *Keyboard) ReadLine() (string, error) {
+	k.BeginInput()
+	defer k.EndInput()
+
+	for {
+		if k.breakReq.Swap(false) {
+			k.EndInput()
+			k.mu.Lock()
+			k.inputBuffer = k.inputBuffer[:0]
+			k.mu.Unlock()
+			return "", ErrBreak
+		}
+		if line, ok := k.takeLine(); ok {
+			k.echo.EchoNewLine()
+			return line, nil
+		}
+		// attente active mais NON bloquante
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// takeLine retourne la ligne validée par Enter et vide la saisie
+func (k *Keyboard) takeLine() (string, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if !k.lineReady {
+		return "", false
+	}
+	line := string(k.inputBuffer)
+	k.inputBuffer = k.inputBuffer[:0]
+	k.lineReady = false
+	return line, true
+}
+
+func (k *Keyboard) InputRune(r rune) {
+	if !k.allowInput.Load() {
+		return
+	}
+
+	k.hideCursor()
+
+	k.mu.Lock()
+	k.inputBuffer = append(k.inputBuffer, r)
+	k.mu.Unlock()
+	k.echo.EchoRune(r)
+}
+
+func (k *Keyboard) Backspace() {
+	k.mu.Lock()
+	empty := len(k.inputBuffer) == 0
+	k.mu.Unlock()
+	if !k.allowInput.Load() || empty {
+		return
+	}
+
+	k.hideCursor()
+	if k.echo.EchoBackspace() {
+		k.mu.Lock()
+		k.inputBuffer = k.inputBuffer[:len(k.inputBuffer)-1]
+		k.mu.Unlock()
+	}
+}
+
+func (k *Keyboard) Enter() {
+	if !k.allowInput.Load() {
+		return
+	}
+
+	k.EndInput()
+	k.mu.Lock()
+	k.lineReady = true
+	k.mu.Unlock()
+}
+
+func (k *Keyboard) BeginInput() {
+	k.blinkCounter.Store(0)
+	k.inInput.Store(true)
+	k.allowInput.Store(true)
+}
+
+func (k *Keyboard) EndInput() {
+	k.hideCursor()
+	k.inInput.Store(false)
+	k.allowInput.Store(false)
+}
+
+func (k *Keyboard) BeginGet() {
+	// oublie une touche arrivée après le GET précédent
+	select {
+	case <-k.getChan:
+	default:
+	}
+	k.getActive.Store(true)
+}
+
+func (k *Keyboard) EndGet() {
+	k.getActive.Store(false)
+}
+
+func (k *Keyboard) PushGetRune(r rune) {
+	if k.getActive.Load() {
+		select {
+		case k.getChan <- r:
+		default:
+		}
+	}
+}
+
+func (k *Keyboard) GetChar() (rune, error) {
+	k.BeginGet()
+	r := <-k.getChan
+	k.EndGet()
+	if k.breakReq.Swap(false) {
+		return 0, ErrBreak
+	}
+	return r, nil
+}
+
+// Break interrompt la saisie en cours (Ctrl-C) : ReadLine et GetChar
+// retournent ErrBreak
+func (k *Keyboard) Break() {
+	k.breakReq.Store(true)
+	k.PushGetRune(3)
+}
+
+func (k *Keyboard) IsGetActive() bool {
+	return k.getActive.Load()
+}
+
+func (k *Keyboard) DisableKeyboard() {
+	k.allowInput.Store(false)
+	k.inInput.Store(false)
+}
+
+// Blink fait clignoter le curseur pendant une saisie ; appelée à chaque
+// frame (Update)
+func (k *Keyboard) Blink() {
+	if !k.inInput.Load() {
+		k.blinkCounter.Store(0)
+		return
+	}
+	k.blinkCounter.Add(1)
+}
+
+// InInput indique si une ligne est en cours de saisie
+func (k *Keyboard) InInput() bool {
+	return k.inInput.Load()
+}
+
+// CursorOn indique si le curseur de saisie est affiché
+func (k *Keyboard) CursorOn() bool {
+	return k.inInput.Load() && k.blinkCounter.Load()/blinkFrames%2 == 0
+}
+
+// hideCursor efface le curseur avant l'écho d'une touche ; il réapparaît
+// une demi-période plus tard
+func (k *Keyboard) hideCursor() {
+	if k.CursorOn() {
+		k.echo.HideCursor()
+		k.blinkCounter.Store(blinkFrames)
+	}
+}
@@ -10,25 +10,25 @@ import (
 )
 
 type TTYInput struct {
-	in  io.Reader
+	in  *bufio.Reader // partagé par les INPUT successifs
 	out io.Writer
 }
 
 func NewTTYInput(in io.Reader, out io.Writer) *TTYInput {
 	return &TTYInput{
-		in:  in,
+		in:  bufio.NewReader(in),
 		out: out,
 	}
 }
 
 func (t *TTYInput) ReadLine() (string, error) {
-	reader := bufio.NewReader(t.in)
-	line, err := reader.ReadString('\n')
-	if err != nil {
+	line, err := t.in.ReadString('\n')
+	if err != nil && line == "" {
 		return "", err
 	}
 
-	return strings.TrimRight(line, "\r\n"), err
+	// dernière ligne sans fin de ligne
+	return strings.TrimRight(line, "\r\n"), nil
 }
 
 func (t *TTYInput) GetChar() (rune, error) {
@@ -47,5 +47,10 @@ func (t *TTYInput) GetChar() (rune, error) {
 		return 0, err
 	}
 
+	// en mode brut, Ctrl-C n'envoie pas SIGINT : c'est un caractère
+	if buf[0] == 3 {
+		return 0, ErrBreak
+	}
+
 	return rune(buf[0]), nil
 }
@@ -0,0 +1,92 @@
+package input
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"basics/testutils"
+)
+
+// screenEcho enregistre l'écho de la saisie
+type screenEcho struct {
+	sb strings.Builder
+}
+
+func (e *screenEcho) EchoRune(r rune) { e.sb.WriteRune(r) }
+func (e *screenEcho) EchoNewLine()    { e.sb.WriteString("\n") }
+func (e *screenEcho) HideCursor()     {}
+
+func (e *screenEcho) EchoBackspace() bool {
+	e.sb.WriteString("<")
+	return true
+}
+
+// waitInput attend que ReadLine accepte la saisie
+func waitInput(k *Keyboard) {
+	for !k.InInput() {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestKeyboard_ReadLine(t *testing.T) {
+	echo := &screenEcho{}
+	k := NewKeyboard(echo)
+
+	go func() {
+		waitInput(k)
+		k.InputRune('4')
+		k.InputRune('3')
+		k.Backspace()
+		k.InputRune('2')
+		k.Enter()
+	}()
+
+	line, err := k.ReadLine()
+	testutils.Equal(t, "error", err, nil)
+	testutils.Equal(t, "line", line, "42")
+	testutils.Equal(t, "echo", echo.sb.String(), "43<2\n")
+	testutils.False(t, "input ended", k.InInput())
+}
+
+func TestKeyboard_Break(t *testing.T) {
+	k := NewKeyboard(&screenEcho{})
+
+	go func() {
+		waitInput(k)
+		k.Break()
+	}()
+	_, err := k.ReadLine()
+	testutils.Equal(t, "INPUT", err, ErrBreak)
+
+	go func() {
+		for !k.IsGetActive() {
+			time.Sleep(time.Millisecond)
+		}
+		k.Break()
+	}()
+	_, err = k.GetChar()
+	testutils.Equal(t, "GET", err, ErrBreak)
+}
+
+func TestKeyboard_Cursor(t *testing.T) {
+	k := NewKeyboard(&screenEcho{})
+	testutils.False(t, "idle", k.CursorOn())
+
+	k.BeginInput()
+	testutils.True(t, "input", k.CursorOn())
+	for range blinkFrames {
+		k.Blink()
+	}
+	testutils.False(t, "blink off", k.CursorOn())
+	for range blinkFrames {
+		k.Blink()
+	}
+	testutils.True(t, "blink on", k.CursorOn())
+
+	k.InputRune('A')
+	testutils.False(t, "hidden by a key", k.CursorOn())
+
+	k.EndInput()
+	testutils.False(t, "ended", k.CursorOn())
+}
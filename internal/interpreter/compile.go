@@ -0,0 +1,595 @@
+package interpreter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"basics/internal/errors"
+	"basics/internal/parser"
+	"basics/internal/runtime"
+)
+
+//
+// =======================
+// Bytecode
+// =======================
+//
+
+// opcode est une instruction du VM à pile
+type opcode uint8
+
+const (
+	opConst     opcode = iota // empile consts[a]
+	opLoadReal                // empile la variable réelle du slot a
+	opLoadInt                 // empile la variable entière (%) du slot a
+	opLoadStr                 // empile la variable chaîne ($) du slot a
+	opStoreReal               // LET : dépile dans le slot a
+	opStoreInt                //
+	opStoreStr                //
+	opPrefix                  // + ou - unaire, a = operator
+	opInfix                   // opérateur binaire, a = operator
+	opInt                     // INT()
+	opAbs                     // ABS()
+	opSgn                     // SGN()
+	opPrint                   // dépile et affiche, a = séparateur précédent (0 si premier)
+	opNewline                 // fin de PRINT
+	opHome                    // HOME
+	opTrace                   // TRACE
+	opNoTrace                 // NOTRACE
+	opHTab                    // HTAB
+	opVTab                    // VTAB
+	opArg                     // arrondit un argument d'instruction écran
+	opScreen                  // a = screenOp, b = nombre d'arguments
+	opInput                   // INPUT inputs[a]
+	opGet                     // GET gets[a]
+	opFor                     // dépile début, fin et pas ; a = slot, b = type de la variable
+	opNext                    // NEXT (boucle FOR au sommet de la pile)
+	opJump                    // pc = a
+	opJumpFalse               // dépile la condition, pc = a si fausse
+	opGoto                    // GOTO calculé : dépile le numéro de ligne
+	opGosub                   // GOSUB, a = pc cible, b = ligne cible
+	opGosubLine               // GOSUB calculé : dépile le numéro de ligne
+	opReturn                  // RETURN
+	opEnd                     // END
+	opFail                    // erreur différée faults[a]
+	opStmt                    // trace de l'instruction BASIC stmts[a]
+	opCall                    // instruction ajoutée calls[a], b = nombre d'arguments
+	opCallFn                  // fonction ajoutée calls[a], b = nombre d'arguments
+)
+
+var opcodeNames = [...]string{
+	opConst: "CONST", opLoadReal: "LOAD", opLoadInt: "LOAD%", opLoadStr: "LOAD$",
+	opStoreReal: "STORE", opStoreInt: "STORE%", opStoreStr: "STORE$",
+	opPrefix: "PREFIX", opInfix: "INFIX", opInt: "INT", opAbs: "ABS", opSgn: "SGN",
+	opPrint: "PRINT", opNewline: "NEWLINE", opHome: "HOME", opHTab: "HTAB", opVTab: "VTAB",
+	opTrace: "TRACE", opNoTrace: "NOTRACE",
+	opArg: "ARG", opScreen: "SCREEN", opInput: "INPUT", opGet: "GET",
+	opFor: "FOR", opNext: "NEXT", opJump: "JUMP", opJumpFalse: "JUMPF",
+	opGoto: "GOTO", opGosub: "GOSUB", opGosubLine: "GOSUBL", opReturn: "RETURN",
+	opEnd: "END", opFail: "FAIL", opStmt: "STMT", opCall: "CALL", opCallFn: "CALLFN",
+}
+
+func (op opcode) String() string {
+	return opcodeNames[op]
+}
+
+// instr est une instruction du bytecode ; pos indexe sa position dans le
+// source pour les messages d'erreur
+type instr struct {
+	op  opcode
+	a   int32
+	b   int32
+	pos int32
+}
+
+// instructions de lecture et d'écriture de chaque type de variable
+var (
+	loadOps  = [...]opcode{runtime.RealKind: opLoadReal, runtime.IntegerKind: opLoadInt, runtime.StringKind: opLoadStr}
+	storeOps = [...]opcode{runtime.RealKind: opStoreReal, runtime.IntegerKind: opStoreInt, runtime.StringKind: opStoreStr}
+)
+
+// srcPos est la position rapportée par une erreur
+type srcPos struct {
+	line int
+	col  int
+	tok  string
+}
+
+// traced est une instruction BASIC affichée par la trace ou repérée par le
+// débogueur
+type traced struct {
+	line  int
+	index int // rang dans la ligne (Location.Stmt)
+	stmt  parser.Statement
+	pc    int // pc de son opStmt
+}
+
+// Bytecode est un programme compilé pour un environnement : les variables
+// y sont des slots, les constantes sont repliées et les sauts résolus.
+type Bytecode struct {
+	code   []instr
+	consts []runtime.Value
+	pos    []srcPos
+	names  [3][]string // nom de chaque slot, par type (désassemblage)
+	lines  map[int]int // numéro de ligne → pc
+	inputs []*parser.InputStmt
+	gets   []*parser.GetStmt
+	faults []*errors.Error
+	stmts  []traced
+	calls  []string // instructions et fonctions ajoutées
+}
+
+// Len retourne le nombre d'instructions du bytecode
+func (b *Bytecode) Len() int {
+	return len(b.code)
+}
+
+// Disassemble liste le bytecode, une instruction par ligne
+func (b *Bytecode) Disassemble() string {
+	var sb strings.Builder
+
+	for pc, in := range b.code {
+		fmt.Fprintf(&sb, "%04d %-7s", pc, in.op)
+
+		switch in.op {
+		case opConst:
+			v := b.consts[in.a]
+			if v.Type == runtime.STRING {
+				fmt.Fprintf(&sb, " %q", v.Str)
+			} else {
+				fmt.Fprintf(&sb, " %s", printString(v))
+			}
+		case opLoadReal, opStoreReal:
+			fmt.Fprintf(&sb, " %s", b.names[runtime.RealKind][in.a])
+		case opLoadInt, opStoreInt:
+			fmt.Fprintf(&sb, " %s", b.names[runtime.IntegerKind][in.a])
+		case opLoadStr, opStoreStr:
+			fmt.Fprintf(&sb, " %s", b.names[runtime.StringKind][in.a])
+		case opFor:
+			fmt.Fprintf(&sb, " %s", b.names[in.b][in.a])
+		case opPrefix, opInfix:
+			fmt.Fprintf(&sb, " %s", operatorName(operator(in.a)))
+		case opPrint:
+			if in.a != 0 {
+				fmt.Fprintf(&sb, " %c", rune(in.a))
+			}
+		case opScreen:
+			fmt.Fprintf(&sb, " %d %d", in.a, in.b)
+		case opJump, opJumpFalse, opGosub:
+			fmt.Fprintf(&sb, " %04d", in.a)
+		case opFail:
+			fmt.Fprintf(&sb, " %s", b.faults[in.a].Msg)
+		case opStmt:
+			fmt.Fprintf(&sb, " %d %s", b.stmts[in.a].line, parser.StmtName(b.stmts[in.a].stmt))
+		case opCall, opCallFn:
+			fmt.Fprintf(&sb, " %s %d", b.calls[in.a], in.b)
+		}
+
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// statementAt retourne l'instruction BASIC qui contient le pc (bytecode
+// compilé avec trace)
+func (b *Bytecode) statementAt(pc int) traced {
+	k := b.statementIndex(pc)
+	if k < 0 {
+		return traced{}
+	}
+	return b.stmts[k]
+}
+
+// statementIndex retourne l'indice dans stmts de l'instruction BASIC qui
+// contient le pc, -1 avant la première
+func (b *Bytecode) statementIndex(pc int) int {
+	return sort.Search(len(b.stmts), func(k int) bool { return b.stmts[k].pc > pc }) - 1
+}
+
+func operatorName(op operator) string {
+	for name, o := range operators {
+		if o == op {
+			return name
+		}
+	}
+	return "?"
+}
+
+//
+// =======================
+// Compilateur
+// =======================
+//
+
+type compiler struct {
+	out   *Bytecode
+	env   *runtime.Environment
+	trace bool
+
+	line    int          // numéro de la ligne compilée
+	index   int          // rang de la dernière instruction de la ligne
+	defined map[int]bool // lignes existantes
+	fixups  []fixup      // sauts vers des lignes, résolus à la fin
+}
+
+// fixup est un saut dont la cible est un numéro de ligne
+type fixup struct {
+	pc   int
+	line int
+}
+
+// Compile traduit un programme en bytecode. Les variables sont résolues
+// (Resolve) en slots typés de env, qui doit être celui du runtime
+// d'exécution.
+func Compile(prog *parser.Program, env *runtime.Environment) *Bytecode {
+	return compile(prog, env, false)
+}
+
+// compile traduit le programme ; trace ajoute une instruction opStmt au
+// début de chaque instruction BASIC (journal de trace, TRACE, débogueur)
+func compile(prog *parser.Program, env *runtime.Environment, trace bool) *Bytecode {
+	c := &compiler{
+		out: &Bytecode{
+			lines: make(map[int]int),
+		},
+		env:     env,
+		trace:   trace,
+		defined: make(map[int]bool),
+	}
+
+	for _, line := range prog.Lines {
+		c.defined[line.Number] = true
+	}
+
+	for _, sym := range Resolve(prog, env) {
+		c.name(sym)
+	}
+
+	for _, line := range prog.Lines {
+		c.line = line.Number
+		c.index = 0
+
+		// pc de la première instruction de la ligne
+		if _, exists := c.out.lines[line.Number]; !exists {
+			c.out.lines[line.Number] = len(c.out.code)
+		}
+
+		c.statements(line.Stmts)
+	}
+
+	for _, f := range c.fixups {
+		c.out.code[f.pc].a = int32(c.out.lines[f.line])
+	}
+
+	return c.out
+}
+
+// emit ajoute une instruction et retourne son pc
+func (c *compiler) emit(op opcode, a int, pos int32) int {
+	c.out.code = append(c.out.code, instr{op: op, a: int32(a), pos: pos})
+	return len(c.out.code) - 1
+}
+
+// at enregistre une position d'erreur
+func (c *compiler) at(line, col int, tok string) int32 {
+	c.out.pos = append(c.out.pos, srcPos{line: line, col: col, tok: tok})
+	return int32(len(c.out.pos) - 1)
+}
+
+// here est la position des erreurs d'instruction (numéro de ligne BASIC)
+func (c *compiler) here() int32 {
+	return c.at(c.line, 0, "")
+}
+
+func (c *compiler) constant(v runtime.Value) int {
+	c.out.consts = append(c.out.consts, v)
+	return c.emit(opConst, len(c.out.consts)-1, -1)
+}
+
+// call émet l'appel d'une instruction ou d'une fonction ajoutée
+func (c *compiler) call(op opcode, name string, args []parser.Expression, pos int32) {
+	for _, arg := range args {
+		c.expr(arg)
+	}
+	c.out.calls = append(c.out.calls, name)
+	pc := c.emit(op, len(c.out.calls)-1, pos)
+	c.out.code[pc].b = int32(len(args))
+}
+
+// symbol retourne le symbole d'une variable, résolue avant la compilation
+func (c *compiler) symbol(name string) runtime.Symbol {
+	sym := c.env.Declare(name)
+	c.name(sym)
+	return sym
+}
+
+// name retient le nom d'un slot pour le désassemblage
+func (c *compiler) name(sym runtime.Symbol) {
+	names := &c.out.names[sym.Kind]
+	for len(*names) <= sym.Slot {
+		*names = append(*names, "")
+	}
+	if (*names)[sym.Slot] == "" {
+		(*names)[sym.Slot] = sym.Name
+	}
+}
+
+// fail compile une erreur levée seulement si l'instruction est atteinte
+func (c *compiler) fail(err *errors.Error) {
+	c.out.faults = append(c.out.faults, err)
+	c.emit(opFail, len(c.out.faults)-1, -1)
+}
+
+// jump émet un saut vers une ligne, résolu en fin de compilation ; b garde
+// le numéro de la ligne
+func (c *compiler) jump(op opcode, line int) {
+	pc := c.emit(op, 0, c.here())
+	c.out.code[pc].b = int32(line)
+	c.fixups = append(c.fixups, fixup{pc: pc, line: line})
+}
+
+func (c *compiler) statements(stmts []parser.Statement) {
+	for _, stmt := range stmts {
+		c.statement(stmt)
+	}
+}
+
+func (c *compiler) statement(stmt parser.Statement) {
+	if stmt == nil {
+		return // REM
+	}
+
+	c.index++
+	if c.trace {
+		c.out.stmts = append(c.out.stmts, traced{line: c.line, index: c.index, stmt: stmt, pc: len(c.out.code)})
+		c.emit(opStmt, len(c.out.stmts)-1, -1)
+	}
+
+	switch s := stmt.(type) {
+
+	case *parser.HomeStmt:
+		c.emit(opHome, 0, -1)
+
+	case *parser.EndStmt:
+		c.emit(opEnd, 0, -1)
+
+	case *parser.TraceStmt:
+		c.emit(opTrace, 0, -1)
+
+	case *parser.NoTraceStmt:
+		c.emit(opNoTrace, 0, -1)
+
+	case *parser.LetStmt:
+		c.expr(s.Value)
+		sym := c.symbol(s.Name)
+		c.emit(storeOps[sym.Kind], sym.Slot, c.here())
+
+	case *parser.InputStmt:
+		c.out.inputs = append(c.out.inputs, s)
+		c.emit(opInput, len(c.out.inputs)-1, c.here())
+
+	case *parser.GetStmt:
+		c.out.gets = append(c.out.gets, s)
+		c.emit(opGet, len(c.out.gets)-1, c.here())
+
+	case *parser.PrintStmt:
+		for idx, expr := range s.Exprs {
+			c.expr(expr)
+			sep := rune(0)
+			if idx > 0 {
+				sep = s.Separators[idx-1]
+			}
+			c.emit(opPrint, int(sep), -1)
+		}
+		if len(s.Separators) < len(s.Exprs) || len(s.Exprs) == 0 {
+			c.emit(opNewline, 0, -1)
+		}
+
+	case *parser.HTabStmt:
+		c.expr(s.Expr)
+		c.emit(opHTab, 0, -1)
+
+	case *parser.VTabStmt:
+		c.expr(s.Expr)
+		c.emit(opVTab, 0, -1)
+
+	case *parser.ModeStmt, *parser.ClsStmt, *parser.LocateStmt,
+		*parser.InkStmt, *parser.PenStmt, *parser.PaperStmt,
+		*parser.BorderStmt, *parser.PlotStmt, *parser.DrawStmt:
+		op, exprs, _ := screenArgs(s)
+		for _, e := range exprs {
+			c.expr(e)
+			c.emit(opArg, 0, c.here())
+		}
+		pc := c.emit(opScreen, int(op), c.here())
+		c.out.code[pc].b = int32(len(exprs))
+
+	case *parser.ForStmt:
+		c.expr(s.Start)
+		c.expr(s.End)
+		if s.Step != nil {
+			c.expr(s.Step)
+		} else {
+			c.constant(runtime.Value{Type: runtime.NUMBER, Num: 1})
+		}
+		sym := c.symbol(s.Var)
+		pc := c.emit(opFor, sym.Slot, c.here())
+		c.out.code[pc].b = int32(sym.Kind)
+
+	case *parser.NextStmt:
+		c.emit(opNext, 0, c.here())
+
+	case *parser.CallStmt:
+		c.call(opCall, s.Name, s.Args, c.here())
+
+	case *parser.GotoStmt:
+		if line, ok := c.target(s.Expr); ok {
+			c.jump(opJump, line)
+			break
+		}
+		c.expr(s.Expr)
+		c.emit(opGoto, 0, c.here())
+
+	case *parser.GosubStmt:
+		if line, ok := c.target(s.Expr); ok {
+			c.jump(opGosub, line)
+			break
+		}
+		c.expr(s.Expr)
+		c.emit(opGosubLine, 0, c.here())
+
+	case *parser.ReturnStmt:
+		c.emit(opReturn, 0, c.here())
+
+	case *parser.IfStmt:
+		c.expr(s.Cond)
+		jumpFalse := c.emit(opJumpFalse, 0, -1)
+		c.statements(s.Then)
+
+		if len(s.Else) > 0 {
+			jumpEnd := c.emit(opJump, 0, -1)
+			c.out.code[jumpFalse].a = int32(len(c.out.code))
+			c.statements(s.Else)
+			c.out.code[jumpEnd].a = int32(len(c.out.code))
+		} else {
+			c.out.code[jumpFalse].a = int32(len(c.out.code))
+		}
+
+		// IfJumpStmt n'existe que dans les instructions de l'évaluateur
+		// d'AST : il n'est jamais produit par le parser
+	}
+}
+
+// target retourne la ligne d'un GOTO / GOSUB constant vers une ligne
+// existante. Les autres cibles sont calculées à l'exécution.
+func (c *compiler) target(expr parser.Expression) (int, bool) {
+	start := len(c.out.code)
+	c.expr(expr)
+
+	in := c.out.code[len(c.out.code)-1]
+	constant := len(c.out.code) == start+1 && in.op == opConst
+
+	// le code de l'expression est retiré : il sera réémis si besoin
+	c.out.code = c.out.code[:start]
+	if !constant {
+		return 0, false
+	}
+
+	v := c.out.consts[in.a]
+	if v.Type != runtime.NUMBER || !c.defined[int(v.Num)] {
+		return 0, false
+	}
+	return int(v.Num), true
+}
+
+// expr compile une expression ; les sous-expressions constantes sont
+// repliées en une seule opConst
+func (c *compiler) expr(expr parser.Expression) {
+	node, ok := expr.(parser.Node)
+	if !ok {
+		c.fail(errors.NewSemantic(0, "INTERNAL AST ERROR"))
+		return
+	}
+	line, col, tok := node.Pos()
+
+	switch e := expr.(type) {
+
+	case *parser.NumberLiteral:
+		c.constant(runtime.Value{Type: runtime.NUMBER, Num: e.Value})
+
+	case *parser.StringLiteral:
+		c.constant(runtime.Value{Type: runtime.STRING, Str: e.Value})
+
+	case *parser.Identifier:
+		sym := c.symbol(e.Name)
+		c.emit(loadOps[sym.Kind], sym.Slot, c.at(line, col, e.Name))
+
+	case *parser.PrefixExpr:
+		start := len(c.out.code)
+		c.expr(e.Right)
+		op := operatorOf(e.Op)
+		folded := c.fold(start, 1, func(v []runtime.Value) (runtime.Value, string) {
+			return prefix(op, v[0])
+		})
+		if !folded {
+			c.emit(opPrefix, int(op), c.operatorPos(op, line, col, tok, e.Op))
+		}
+
+	case *parser.InfixExpr:
+		start := len(c.out.code)
+		c.expr(e.Left)
+		c.expr(e.Right)
+		op := operatorOf(e.Op)
+		folded := c.fold(start, 2, func(v []runtime.Value) (runtime.Value, string) {
+			return infix(op, v[0], v[1])
+		})
+		if !folded {
+			c.emit(opInfix, int(op), c.operatorPos(op, line, col, tok, e.Op))
+		}
+
+	case *parser.IntExpr:
+		c.function(opInt, intFn, e.Expr, line, col, tok)
+
+	case *parser.AbsExpr:
+		c.function(opAbs, absFn, e.Expr, line, col, tok)
+
+	case *parser.SgnExpr:
+		c.function(opSgn, sgnFn, e.Expr, line, col, tok)
+
+	case *parser.CallExpr:
+		c.call(opCallFn, e.Name, e.Args, c.at(line, col, tok))
+
+	default:
+		c.fail(errors.NewSyntax(line, col, tok, "INVALID EXPRESSION"))
+	}
+}
+
+// function compile INT, ABS et SGN
+func (c *compiler) function(op opcode, fn func(runtime.Value) (runtime.Value, string),
+	arg parser.Expression, line, col int, tok string) {
+	start := len(c.out.code)
+	c.expr(arg)
+	folded := c.fold(start, 1, func(v []runtime.Value) (runtime.Value, string) {
+		return fn(v[0])
+	})
+	if !folded {
+		c.emit(op, 0, c.at(line, col, tok))
+	}
+}
+
+// operatorPos place les erreurs d'un opérateur inconnu sur l'opérateur
+func (c *compiler) operatorPos(op operator, line, col int, tok, name string) int32 {
+	if op == unknownOp {
+		tok = name
+	}
+	return c.at(line, col, tok)
+}
+
+// fold remplace n opérandes constants par le résultat de l'opération, qui
+// n'a alors pas à être émise. Une opération en erreur n'est pas repliée :
+// l'erreur doit rester levée à l'exécution.
+func (c *compiler) fold(start, n int, eval func([]runtime.Value) (runtime.Value, string)) bool {
+	code := c.out.code[start:]
+	if len(code) != n {
+		return false
+	}
+
+	args := make([]runtime.Value, n)
+	for k, in := range code {
+		if in.op != opConst {
+			return false
+		}
+		args[k] = c.out.consts[in.a]
+	}
+
+	v, msg := eval(args)
+	if msg != "" {
+		return false
+	}
+
+	c.out.code = c.out.code[:start]
+	c.constant(v)
+	return true
+}
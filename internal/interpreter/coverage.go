@@ -0,0 +1,55 @@
+package interpreter
+
+import "basics/internal/parser"
+
+// Coverage compte les exécutions de chaque instruction BASIC, branches
+// THEN et ELSE comprises
+type Coverage struct {
+	code   *Bytecode
+	counts []int // exécutions, par indice de Bytecode.stmts
+	done   chan struct{}
+}
+
+// CoveredStatement est une instruction du programme et son nombre
+// d'exécutions
+type CoveredStatement struct {
+	Line  int
+	Stmt  int // rang dans la ligne (Location.Stmt)
+	Node  parser.Statement
+	Count int
+}
+
+// NewCoverage crée une couverture vide
+func NewCoverage() *Coverage {
+	return &Coverage{done: make(chan struct{})}
+}
+
+// SetCoverage attache une mesure de couverture à l'interpréteur, avant Run
+func (i *Interpreter) SetCoverage(c *Coverage) {
+	i.coverage = c
+}
+
+func (c *Coverage) begin(code *Bytecode) {
+	c.code = code
+	c.counts = make([]int, len(code.stmts))
+}
+
+func (c *Coverage) end() {
+	close(c.done)
+}
+
+// Statements retourne toutes les instructions du programme dans l'ordre,
+// exécutées ou non ; nil tant que le programme s'exécute
+func (c *Coverage) Statements() []CoveredStatement {
+	select {
+	case <-c.done:
+	default:
+		return nil
+	}
+
+	stmts := make([]CoveredStatement, len(c.code.stmts))
+	for k, t := range c.code.stmts {
+		stmts[k] = CoveredStatement{Line: t.line, Stmt: t.index, Node: t.stmt, Count: c.counts[k]}
+	}
+	return stmts
+}
@@ -0,0 +1,451 @@
+package interpreter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"basics/internal/dialect"
+	"basics/internal/errors"
+	"basics/internal/lexer"
+	"basics/internal/parser"
+	"basics/internal/runtime"
+	"basics/internal/token"
+)
+
+//
+// =======================
+// Débogueur
+// =======================
+//
+
+// Location repère une instruction BASIC : numéro de ligne et rang de
+// l'instruction dans la ligne (1 = première ; les instructions des blocs
+// THEN et ELSE sont comptées à la suite de leur IF)
+type Location struct {
+	Line int
+	Stmt int
+}
+
+func (l Location) String() string {
+	return fmt.Sprintf("%d:%d", l.Line, l.Stmt)
+}
+
+// Breakpoint est un point d'arrêt sur une ligne ou sur une instruction.
+// Sur une ligne (Stmt = 0), il arrête le programme quand l'exécution entre
+// dans la ligne, pas à chaque tour d'une boucle interne à la ligne.
+type Breakpoint struct {
+	Line int
+	Stmt int    // 0 : toute la ligne
+	Cond string // expression BASIC ; vide : toujours
+	cond parser.Expression
+}
+
+// StopReason est la cause d'un arrêt du programme
+type StopReason string
+
+const (
+	StopEntry      StopReason = "entry"
+	StopBreakpoint StopReason = "breakpoint"
+	StopStep       StopReason = "step"
+	StopWatch      StopReason = "watch"
+	StopPause      StopReason = "pause"
+)
+
+// Stop décrit un arrêt, avant l'exécution de l'instruction Location
+type Stop struct {
+	Reason   StopReason
+	Location Location
+	Stmt     parser.Statement
+
+	// watchpoint : variable modifiée par l'instruction précédente
+	Watch    string
+	Old, New runtime.Value
+}
+
+// Step est la manière de reprendre l'exécution après un arrêt
+type Step int
+
+const (
+	Continue Step = iota // jusqu'au prochain point d'arrêt
+	StepIn               // instruction suivante, y compris dans un GOSUB
+	StepOver             // instruction suivante, GOSUB exécuté d'un bloc
+	StepOut              // jusqu'au RETURN du GOSUB courant
+)
+
+// Frame est un niveau de la pile d'appels : l'instruction courante puis
+// chaque GOSUB en cours, du plus récent au plus ancien
+type Frame struct {
+	Location Location
+	Stmt     parser.Statement
+}
+
+// Loop est une boucle FOR ouverte
+type Loop struct {
+	Var      string
+	End      float64
+	Step     float64
+	Location Location // instruction FOR
+}
+
+// Debugger contrôle l'exécution d'un programme par le VM : points d'arrêt,
+// exécution pas à pas et surveillance de variables. Le programme s'exécute
+// dans sa propre goroutine ; chaque arrêt est envoyé sur Stops() et le
+// programme attend Resume. Variables, Evaluate, Frames et Loops ne doivent
+// être appelés que programme arrêté.
+type Debugger struct {
+	// StopOnEntry arrête le programme avant sa première instruction
+	StopOnEntry bool
+
+	dialect *dialect.Dialect
+	interp  *Interpreter
+	code    *Bytecode
+
+	mu          sync.Mutex
+	breakpoints map[Location]*Breakpoint
+	watches     map[string]runtime.Value
+	step        Step
+	depth       int // profondeur de GOSUB au dernier arrêt
+	pausing     bool
+	terminated  bool
+	detached    bool
+
+	started bool
+	prev    Location
+	current Location
+	stmt    parser.Statement
+
+	stops  chan Stop
+	resume chan Step
+}
+
+// NewDebugger crée un débogueur ; le dialecte sert à lire les conditions
+// et les expressions évaluées
+func NewDebugger(d *dialect.Dialect) *Debugger {
+	return &Debugger{
+		dialect:     d,
+		breakpoints: map[Location]*Breakpoint{},
+		watches:     map[string]runtime.Value{},
+		stops:       make(chan Stop),
+		resume:      make(chan Step, 1),
+	}
+}
+
+// SetDebugger attache un débogueur à l'interpréteur, avant Run
+func (i *Interpreter) SetDebugger(d *Debugger) {
+	i.debug = d
+	d.interp = i
+}
+
+// Stops retourne les arrêts du programme ; le canal est fermé à la fin de
+// l'exécution
+func (d *Debugger) Stops() <-chan Stop {
+	return d.stops
+}
+
+// SetBreakpoint ajoute ou remplace un point d'arrêt
+func (d *Debugger) SetBreakpoint(bp Breakpoint) error {
+	if bp.Cond != "" {
+		cond, err := ParseExpression(bp.Cond, d.dialect)
+		if err != nil {
+			return err
+		}
+		bp.cond = cond
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.breakpoints[Location{bp.Line, bp.Stmt}] = &bp
+	return nil
+}
+
+// ClearBreakpoint retire un point d'arrêt ; Stmt = 0 retire ceux de toute
+// la ligne
+func (d *Debugger) ClearBreakpoint(line, stmt int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for loc := range d.breakpoints {
+		if loc.Line == line && (stmt == 0 || loc.Stmt == stmt) {
+			delete(d.breakpoints, loc)
+		}
+	}
+}
+
+// ClearBreakpoints retire tous les points d'arrêt
+func (d *Debugger) ClearBreakpoints() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.breakpoints = map[Location]*Breakpoint{}
+}
+
+// Breakpoints retourne les points d'arrêt, triés
+func (d *Debugger) Breakpoints() []Breakpoint {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	bps := make([]Breakpoint, 0, len(d.breakpoints))
+	for _, bp := range d.breakpoints {
+		bps = append(bps, *bp)
+	}
+	sort.Slice(bps, func(a, b int) bool {
+		if bps[a].Line != bps[b].Line {
+			return bps[a].Line < bps[b].Line
+		}
+		return bps[a].Stmt < bps[b].Stmt
+	})
+	return bps
+}
+
+// Watch arrête le programme quand la variable change de valeur
+func (d *Debugger) Watch(name string) {
+	name = strings.ToUpper(name)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.watches[name]; !ok {
+		d.watches[name] = d.value(name)
+	}
+}
+
+// Unwatch retire la surveillance d'une variable
+func (d *Debugger) Unwatch(name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.watches, strings.ToUpper(name))
+}
+
+// Watches retourne les variables surveillées, triées
+func (d *Debugger) Watches() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	names := make([]string, 0, len(d.watches))
+	for name := range d.watches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resume reprend l'exécution d'un programme arrêté
+func (d *Debugger) Resume(step Step) {
+	select {
+	case d.resume <- step:
+	default:
+	}
+}
+
+// Pause arrête le programme avant sa prochaine instruction
+func (d *Debugger) Pause() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pausing = true
+}
+
+// Terminate arrête définitivement le programme
+func (d *Debugger) Terminate() {
+	d.mu.Lock()
+	d.terminated = true
+	d.mu.Unlock()
+	d.Resume(Continue)
+}
+
+// Detach laisse le programme s'exécuter jusqu'à sa fin sans plus l'arrêter
+func (d *Debugger) Detach() {
+	d.mu.Lock()
+	d.detached = true
+	d.mu.Unlock()
+	d.Resume(Continue)
+}
+
+// Variables retourne les variables du programme
+func (d *Debugger) Variables() []runtime.Variable {
+	return d.interp.rt.Env.Variables()
+}
+
+// Evaluate évalue une expression BASIC avec les variables du programme
+func (d *Debugger) Evaluate(src string) (runtime.Value, error) {
+	expr, err := ParseExpression(src, d.dialect)
+	if err != nil {
+		return runtime.Value{}, err
+	}
+	val, e := EvalExpr(expr, d.interp.rt)
+	if e != nil {
+		return runtime.Value{}, errors.NewSemantic(0, e.Msg)
+	}
+	return val, nil
+}
+
+// Frames retourne la pile d'appels : l'instruction courante puis les
+// GOSUB en cours
+func (d *Debugger) Frames() []Frame {
+	frames := []Frame{{Location: d.current, Stmt: d.stmt}}
+
+	calls := d.interp.gosubStack.stack
+	for k := len(calls) - 1; k >= 0; k-- {
+		// le GOSUB précède son adresse de retour
+		t := d.code.statementAt(calls[k].ReturnPC - 1)
+		frames = append(frames, Frame{Location: Location{t.line, t.index}, Stmt: t.stmt})
+	}
+	return frames
+}
+
+// Loops retourne les boucles FOR ouvertes, de la plus récente à la plus
+// ancienne
+func (d *Debugger) Loops() []Loop {
+	var loops []Loop
+
+	fors := d.interp.forStack.stack
+	for k := len(fors) - 1; k >= 0; k-- {
+		f := fors[k]
+		t := d.code.statementAt(f.PCStart)
+		loops = append(loops, Loop{Var: f.Var, End: f.End, Step: f.Step, Location: Location{t.line, t.index}})
+	}
+	return loops
+}
+
+// ParseExpression lit une expression BASIC seule (A > 5 AND B$ = "X").
+// Les erreurs n'ont pas de numéro de ligne.
+func ParseExpression(src string, d *dialect.Dialect) (parser.Expression, error) {
+	tokens, err := lexer.Scan(src, d)
+	if err != nil {
+		return nil, errors.NewSemantic(0, "INVALID TOKEN "+tokens[len(tokens)-1].Literal)
+	}
+
+	// sans numéro de ligne, le lexer prend le premier nombre pour un
+	for k := range tokens {
+		if tokens[k].Type == token.LINENUM {
+			tokens[k].Type = token.NUMBER
+		}
+	}
+
+	expr, errs := parser.NewWithDialect(tokens, d).ParseExpression()
+	if len(errs) > 0 {
+		return nil, errors.NewSemantic(0, errs[0].Msg)
+	}
+	return expr, nil
+}
+
+// value retourne la valeur courante d'une variable (0 ou "" si elle n'a
+// pas été affectée)
+func (d *Debugger) value(name string) runtime.Value {
+	if d.interp == nil {
+		return runtime.NewReal(0)
+	}
+	v, _ := d.interp.rt.Env.Get(name)
+	return v
+}
+
+// statement est appelé par le VM avant chaque instruction BASIC. Il
+// retourne false si le programme doit s'arrêter.
+func (d *Debugger) statement(t traced) bool {
+	loc := Location{t.line, t.index}
+	depth := len(d.interp.gosubStack.stack)
+
+	d.mu.Lock()
+	if d.terminated {
+		d.mu.Unlock()
+		return false
+	}
+
+	stop := d.check(loc, depth)
+	d.prev = loc
+	d.started = true
+	d.mu.Unlock()
+
+	if stop == nil {
+		return true
+	}
+
+	stop.Location = loc
+	stop.Stmt = t.stmt
+	d.current, d.stmt = loc, t.stmt
+	d.stops <- *stop
+
+	step := <-d.resume
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.step, d.depth = step, depth
+	return !d.terminated
+}
+
+// check retourne l'arrêt à faire avant l'instruction, ou nil
+func (d *Debugger) check(loc Location, depth int) *Stop {
+	// les valeurs surveillées sont mises à jour même sans arrêt
+	var watch *Stop
+	for _, name := range sortedKeys(d.watches) {
+		v := d.value(name)
+		if old := d.watches[name]; v != old {
+			d.watches[name] = v
+			if watch == nil {
+				watch = &Stop{Reason: StopWatch, Watch: name, Old: old, New: v}
+			}
+		}
+	}
+
+	switch {
+	case d.detached:
+		return nil
+	case !d.started && d.StopOnEntry:
+		return &Stop{Reason: StopEntry}
+	case d.pausing:
+		d.pausing = false
+		return &Stop{Reason: StopPause}
+	case d.started && d.stepping(depth):
+		return &Stop{Reason: StopStep}
+	case watch != nil:
+		return watch
+	case d.hit(loc):
+		return &Stop{Reason: StopBreakpoint}
+	}
+	return nil
+}
+
+// stepping indique si le pas demandé au dernier arrêt est terminé
+func (d *Debugger) stepping(depth int) bool {
+	switch d.step {
+	case StepIn:
+		return true
+	case StepOver:
+		return depth <= d.depth
+	case StepOut:
+		return depth < d.depth
+	}
+	return false
+}
+
+// hit indique si un point d'arrêt (et sa condition) arrête l'instruction
+func (d *Debugger) hit(loc Location) bool {
+	bp, ok := d.breakpoints[loc]
+	if !ok {
+		// point d'arrêt de ligne : à l'entrée dans la ligne seulement
+		bp, ok = d.breakpoints[Location{loc.Line, 0}]
+		ok = ok && (loc.Stmt == 1 || d.prev.Line != loc.Line || !d.started)
+	}
+	if !ok {
+		return false
+	}
+	if bp.cond == nil {
+		return true
+	}
+
+	// une condition en erreur arrête le programme, pour être corrigée
+	val, err := EvalExpr(bp.cond, d.interp.rt)
+	return err != nil || truthy(val)
+}
+
+// exit signale la fin du programme
+func (d *Debugger) exit() {
+	close(d.stops)
+}
+
+func sortedKeys(m map[string]runtime.Value) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,196 @@
+package interpreter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"basics/internal/errors"
+	"basics/internal/runtime"
+	"basics/internal/unparse"
+)
+
+const consoleHelp = `break LINE[:STMT] [IF COND]  set a breakpoint (b)
+delete LINE[:STMT]           remove breakpoints (d)
+breaks                       list breakpoints and watches
+watch VAR / unwatch VAR      stop when a variable changes (w)
+continue (c)  step (s)  next (n)  out (o)
+print EXPR (p)  vars  stack (bt)  loops
+quit (q)`
+
+// Console est l'interface en ligne de commande du débogueur : elle affiche
+// chaque arrêt et lit les commandes jusqu'à la reprise du programme. La
+// fin de in laisse le programme se terminer sans arrêt.
+func (d *Debugger) Console(in io.Reader, out io.Writer) {
+	r := bufio.NewReader(in)
+
+	for stop := range d.Stops() {
+		fmt.Fprintln(out, formatStop(stop))
+		for !d.command(r, out) {
+		}
+	}
+}
+
+// command lit et exécute une commande ; retourne true si le programme
+// reprend
+func (d *Debugger) command(r *bufio.Reader, out io.Writer) bool {
+	fmt.Fprint(out, "(basics) ")
+	line, err := r.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(out)
+		d.Detach()
+		return true
+	}
+
+	cmd, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+	arg = strings.TrimSpace(arg)
+
+	switch strings.ToLower(cmd) {
+	case "":
+		return false
+
+	case "c", "continue":
+		d.Resume(Continue)
+		return true
+	case "s", "step":
+		d.Resume(StepIn)
+		return true
+	case "n", "next":
+		d.Resume(StepOver)
+		return true
+	case "o", "out":
+		d.Resume(StepOut)
+		return true
+	case "q", "quit":
+		d.Terminate()
+		return true
+
+	case "b", "break":
+		bp, err := parseBreakpoint(arg)
+		if err == nil {
+			err = d.SetBreakpoint(bp)
+		}
+		if err != nil {
+			fmt.Fprintln(out, err)
+		}
+
+	case "d", "delete":
+		line, stmt, err := parseLocation(arg)
+		if err != nil {
+			fmt.Fprintln(out, err)
+			break
+		}
+		d.ClearBreakpoint(line, stmt)
+
+	case "breaks":
+		for _, bp := range d.Breakpoints() {
+			fmt.Fprintf(out, "break %s\n", formatBreakpoint(bp))
+		}
+		for _, name := range d.Watches() {
+			fmt.Fprintf(out, "watch %s\n", name)
+		}
+
+	case "w", "watch", "unwatch":
+		switch {
+		case arg == "":
+			fmt.Fprintln(out, errors.NewSemantic(0, "MISSING VARIABLE NAME"))
+		case strings.ToLower(cmd) == "unwatch":
+			d.Unwatch(arg)
+		default:
+			d.Watch(arg)
+		}
+
+	case "p", "print":
+		val, err := d.Evaluate(arg)
+		if err != nil {
+			fmt.Fprintln(out, err)
+			break
+		}
+		fmt.Fprintln(out, FormatValue(val))
+
+	case "vars":
+		for _, v := range d.Variables() {
+			if v.Set {
+				fmt.Fprintf(out, "%s = %s\n", v.Symbol.Name, FormatValue(v.Value))
+			}
+		}
+
+	case "bt", "stack":
+		for k, f := range d.Frames() {
+			fmt.Fprintf(out, "#%d %s %s\n", k, f.Location, unparse.Statement(f.Stmt))
+		}
+
+	case "loops":
+		for _, l := range d.Loops() {
+			fmt.Fprintf(out, "FOR %s TO %s STEP %s (%s)\n",
+				l.Var, formatNumber(l.End), formatNumber(l.Step), l.Location)
+		}
+
+	case "h", "help":
+		fmt.Fprintln(out, consoleHelp)
+
+	default:
+		fmt.Fprintf(out, "⚠️ UNKNOWN COMMAND %s (help)\n", cmd)
+	}
+	return false
+}
+
+// parseBreakpoint lit "LINE[:STMT] [IF COND]"
+func parseBreakpoint(arg string) (Breakpoint, error) {
+	loc, cond, _ := strings.Cut(arg, " ")
+	cond = strings.TrimSpace(cond)
+	if len(cond) >= 2 && strings.EqualFold(cond[:2], "IF") {
+		cond = strings.TrimSpace(cond[2:])
+	}
+
+	line, stmt, err := parseLocation(loc)
+	return Breakpoint{Line: line, Stmt: stmt, Cond: cond}, err
+}
+
+// parseLocation lit "LINE" ou "LINE:STMT"
+func parseLocation(arg string) (int, int, error) {
+	l, s, hasStmt := strings.Cut(arg, ":")
+
+	line, err := strconv.Atoi(l)
+	if err != nil {
+		return 0, 0, errors.NewSemantic(0, "INVALID LINE NUMBER "+arg)
+	}
+	stmt := 0
+	if hasStmt {
+		if stmt, err = strconv.Atoi(s); err != nil || stmt < 1 {
+			return 0, 0, errors.NewSemantic(0, "INVALID STATEMENT NUMBER "+arg)
+		}
+	}
+	return line, stmt, nil
+}
+
+func formatBreakpoint(bp Breakpoint) string {
+	s := strconv.Itoa(bp.Line)
+	if bp.Stmt > 0 {
+		s = Location{bp.Line, bp.Stmt}.String()
+	}
+	if bp.Cond != "" {
+		s += " IF " + bp.Cond
+	}
+	return s
+}
+
+// formatStop décrit un arrêt : position, instruction et cause
+func formatStop(stop Stop) string {
+	s := fmt.Sprintf("⏸ %s %s", stop.Location, unparse.Statement(stop.Stmt))
+	if stop.Reason == StopWatch {
+		s += fmt.Sprintf(" [%s: %s → %s]", stop.Watch, FormatValue(stop.Old), FormatValue(stop.New))
+	}
+	return s + " (" + string(stop.Reason) + ")"
+}
+
+// FormatValue affiche une valeur pour le débogueur, les chaînes entre
+// guillemets
+func FormatValue(val runtime.Value) string {
+	if val.Type == runtime.STRING {
+		return strconv.Quote(val.Str)
+	}
+	return printString(val)
+}
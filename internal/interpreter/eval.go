@@ -5,7 +5,43 @@ import (
 	"basics/internal/parser"
 	"basics/internal/runtime"
 	"math"
-	"strconv"
+)
+
+// operator est un opérateur résolu une seule fois (compilation ou
+// évaluation) au lieu de comparer des chaînes à chaque calcul
+type operator uint8
+
+const (
+	unknownOp operator = iota
+	addOp
+	subOp
+	mulOp
+	divOp
+	powOp
+	eqOp
+	neOp
+	ltOp
+	gtOp
+	leOp
+	geOp
+)
+
+var operators = map[string]operator{
+	"+": addOp, "-": subOp, "*": mulOp, "/": divOp, "^": powOp,
+	"=": eqOp, "<>": neOp, "<": ltOp, ">": gtOp, "<=": leOp, ">=": geOp,
+}
+
+func operatorOf(op string) operator {
+	return operators[op]
+}
+
+// Messages des erreurs d'évaluation : la position est ajoutée par
+// l'appelant (nœud de l'AST ou instruction du bytecode)
+const (
+	errTypeMismatch   = "TYPE MISMATCH"
+	errDivisionByZero = "DIVISION BY ZERO"
+	errUnknownPrefix  = "UNKNOWN PREFIX OPERATOR"
+	errUnknownInfix   = "UNKNOWN INFIX OPERATOR"
 )
 
 func EvalExpr(expr parser.Expression, rt *runtime.Runtime) (runtime.Value, *errors.Error) {
@@ -16,6 +52,14 @@ func EvalExpr(expr parser.Expression, rt *runtime.Runtime) (runtime.Value, *erro
 	}
 	line, col, tok := node.Pos()
 
+	// fail place l'erreur sur le nœud (ou sur l'opérateur inconnu)
+	fail := func(msg, op string) (runtime.Value, *errors.Error) {
+		if msg == errUnknownPrefix || msg == errUnknownInfix {
+			tok = op
+		}
+		return runtime.Value{}, errors.NewSyntax(line, col, tok, msg)
+	}
+
 	switch e := expr.(type) {
 
 	case *parser.NumberLiteral:
@@ -27,23 +71,15 @@ func EvalExpr(expr parser.Expression, rt *runtime.Runtime) (runtime.Value, *erro
 		return runtime.Value{Type: runtime.STRING, Str: e.Value}, nil
 
 	case *parser.Identifier:
+		// la valeur a le type de la variable (suffixe)
 		val, ok := rt.Env.Get(e.Name)
 		if !ok {
 			return runtime.Value{}, errors.NewSemantic(
 				line,
 				"UNDEFINED VARIABLE "+e.Name,
 			)
-		} else {
-			// valeur par défaut Applesoft
-			switch VarType(e.Name) {
-			case "string":
-				return runtime.Value{Type: runtime.STRING, Str: val.Str}, nil
-			case "int":
-				return runtime.Value{Type: runtime.INTEGER, Int: val.Int}, nil
-			case "float":
-				return runtime.Value{Type: runtime.NUMBER, Num: val.Num}, nil
-			}
 		}
+		return val, nil
 
 	case *parser.PrefixExpr:
 		right, err := EvalExpr(e.Right, rt)
@@ -51,52 +87,11 @@ func EvalExpr(expr parser.Expression, rt *runtime.Runtime) (runtime.Value, *erro
 			return runtime.Value{}, err
 		}
 
-		switch right.Type {
-
-		case runtime.STRING:
-			return runtime.Value{}, errors.NewSyntax(
-				line, col, tok,
-				"TYPE MISMATCH",
-			)
-
-		case runtime.INTEGER:
-			switch e.Op {
-			case "+":
-				return right, nil
-			case "-":
-				return runtime.Value{
-					Type: runtime.INTEGER,
-					Int:  -right.Int,
-				}, nil
-			default:
-				return runtime.Value{}, errors.NewSyntax(
-					line, col, e.Op,
-					"UNKNOWN PREFIX OPERATOR",
-				)
-			}
-
-		case runtime.NUMBER:
-			switch e.Op {
-			case "+":
-				return right, nil
-			case "-":
-				return runtime.Value{
-					Type: runtime.NUMBER,
-					Num:  -right.Num,
-				}, nil
-			default:
-				return runtime.Value{}, errors.NewSyntax(
-					line, col, e.Op,
-					"UNKNOWN PREFIX OPERATOR",
-				)
-			}
+		val, msg := prefix(operatorOf(e.Op), right)
+		if msg != "" {
+			return fail(msg, e.Op)
 		}
-
-		// Sécurité (ne devrait jamais arriver)
-		return runtime.Value{}, errors.NewSyntax(
-			line, col, tok,
-			"INVALID PREFIX EXPRESSION",
-		)
+		return val, nil
 
 	case *parser.InfixExpr:
 		left, err := EvalExpr(e.Left, rt)
@@ -109,197 +104,11 @@ func EvalExpr(expr parser.Expression, rt *runtime.Runtime) (runtime.Value, *erro
 			return runtime.Value{}, err
 		}
 
-		op := e.Op
-
-		// =========================
-		// STRING operations
-		// =========================
-		if left.Type == runtime.STRING || right.Type == runtime.STRING {
-
-			// Applesoft : seul "+" est autorisé pour les chaînes
-			if op != "+" {
-				err = errors.NewSyntax(
-					line,
-					col,
-					tok,
-					"TYPE MISMATCH",
-				)
-				return runtime.Value{}, err
-			}
-
-			// conversion implicite nombre → string
-			ls := ""
-			rs := ""
-
-			switch left.Type {
-			case runtime.STRING:
-				ls = left.Str
-			case runtime.INTEGER:
-				ls = strconv.Itoa(left.Int)
-			default:
-				ls = formatNumber(left.Num)
-			}
-
-			switch right.Type {
-			case runtime.STRING:
-				rs = right.Str
-			case runtime.INTEGER:
-				rs = strconv.Itoa(right.Int)
-			default:
-				rs = formatNumber(right.Num)
-			}
-
-			return runtime.Value{
-				Type: runtime.STRING,
-				Str:  ls + rs,
-			}, nil
-		}
-
-		// =========================
-		// INTEGER operations
-		// =========================
-		if left.Type == runtime.INTEGER && right.Type == runtime.INTEGER {
-
-			switch op {
-
-			case "+":
-				return runtime.Value{Type: runtime.INTEGER, Int: left.Int + right.Int}, nil
-			case "-":
-				return runtime.Value{Type: runtime.INTEGER, Int: left.Int - right.Int}, nil
-			case "*":
-				return runtime.Value{Type: runtime.INTEGER, Int: left.Int * right.Int}, nil
-			case "^":
-				return runtime.Value{Type: runtime.INTEGER, Int: int(math.Pow(float64(left.Int), float64(right.Int)))}, nil
-			case "/":
-				// Applesoft : division entière → float
-				if right.Int == 0 {
-					err = errors.NewSyntax(
-						line,
-						col,
-						tok,
-						"DIVISION BY ZERO",
-					)
-					return runtime.Value{}, err
-				}
-				return runtime.Value{
-					Type: runtime.NUMBER,
-					Num:  float64(left.Int) / float64(right.Int),
-				}, nil
-
-			case "<":
-				if left.Int < right.Int {
-					return runtime.Value{Type: runtime.NUMBER, Num: 1}, nil
-				}
-				return runtime.Value{Type: runtime.NUMBER, Num: 0}, nil
-			case ">":
-				if left.Int > right.Int {
-					return runtime.Value{Type: runtime.NUMBER, Num: 1}, nil
-				}
-				return runtime.Value{Type: runtime.NUMBER, Num: 0}, nil
-			case "<=":
-				if left.Int <= right.Int {
-					return runtime.Value{Type: runtime.NUMBER, Num: 1}, nil
-				}
-				return runtime.Value{Type: runtime.NUMBER, Num: 0}, nil
-			case ">=":
-				if left.Int >= right.Int {
-					return runtime.Value{Type: runtime.NUMBER, Num: 1}, nil
-				}
-				return runtime.Value{Type: runtime.NUMBER, Num: 0}, nil
-			case "=":
-				if left.Int == right.Int {
-					return runtime.Value{Type: runtime.NUMBER, Num: 1}, nil
-				}
-				return runtime.Value{Type: runtime.NUMBER, Num: 0}, nil
-			case "<>":
-				if left.Int != right.Int {
-					return runtime.Value{Type: runtime.NUMBER, Num: 1}, nil
-				}
-				return runtime.Value{Type: runtime.NUMBER, Num: 0}, nil
-			}
-
-			err = errors.NewSyntax(
-				line,
-				col,
-				tok,
-				"SYNTAX ERROR",
-			)
-			return runtime.Value{}, err
-		}
-
-		// =========================
-		// MIXED or FLOAT operations
-		// =========================
-
-		// conversion implicite int → float
-		lf := left.Num
-		rf := right.Num
-
-		if left.Type == runtime.INTEGER {
-			lf = float64(left.Int)
-		}
-		if right.Type == runtime.INTEGER {
-			rf = float64(right.Int)
-		}
-
-		switch op {
-
-		case "+":
-			return runtime.Value{Type: runtime.NUMBER, Num: lf + rf}, nil
-		case "-":
-			return runtime.Value{Type: runtime.NUMBER, Num: lf - rf}, nil
-		case "*":
-			return runtime.Value{Type: runtime.NUMBER, Num: lf * rf}, nil
-		case "^":
-			return runtime.Value{Type: runtime.NUMBER, Num: math.Pow(lf, rf)}, nil
-		case "/":
-			if rf == 0 {
-				err = errors.NewSyntax(
-					line,
-					col,
-					tok,
-					"DIVISION BY ZERO",
-				)
-				return runtime.Value{}, err
-			}
-			return runtime.Value{Type: runtime.NUMBER, Num: lf / rf}, nil
-
-		case "<":
-			if lf < rf {
-				return runtime.Value{Type: runtime.NUMBER, Num: 1}, nil
-			}
-			return runtime.Value{Type: runtime.NUMBER, Num: 0}, nil
-		case ">":
-			if lf > rf {
-				return runtime.Value{Type: runtime.NUMBER, Num: 1}, nil
-			}
-			return runtime.Value{Type: runtime.NUMBER, Num: 0}, nil
-		case "<=":
-			if lf <= rf {
-				return runtime.Value{Type: runtime.NUMBER, Num: 1}, nil
-			}
-			return runtime.Value{Type: runtime.NUMBER, Num: 0}, nil
-		case ">=":
-			if lf >= rf {
-				return runtime.Value{Type: runtime.NUMBER, Num: 1}, nil
-			}
-			return runtime.Value{Type: runtime.NUMBER, Num: 0}, nil
-		case "<>":
-			if lf != rf {
-				return runtime.Value{Type: runtime.NUMBER, Num: 1}, nil
-			}
-			return runtime.Value{Type: runtime.NUMBER, Num: 0}, nil
-		case "=":
-			if lf == rf {
-				return runtime.Value{Type: runtime.NUMBER, Num: 1}, nil
-			}
-			return runtime.Value{Type: runtime.NUMBER, Num: 0}, nil
-		default:
-			return runtime.Value{}, errors.NewSyntax(
-				line, col, e.Op,
-				"UNKNOWN INFIX OPERATOR",
-			)
+		val, msg := infix(operatorOf(e.Op), left, right)
+		if msg != "" {
+			return fail(msg, e.Op)
 		}
+		return val, nil
 
 	case *parser.IntExpr:
 		val, err := EvalExpr(e.Expr, rt)
@@ -307,40 +116,11 @@ func EvalExpr(expr parser.Expression, rt *runtime.Runtime) (runtime.Value, *erro
 			return runtime.Value{}, err
 		}
 
-		switch val.Type {
-
-		case runtime.STRING:
-			return runtime.Value{}, errors.NewSyntax(
-				line, col, tok,
-				"TYPE MISMATCH",
-			)
-
-		case runtime.INTEGER:
-			// INT(entier) → entier inchangé
-			return val, nil
-
-		case runtime.NUMBER:
-			if val.Num >= 0 {
-				// Si positif alors partie entière
-				// INT (1,75) -> 1
-				return runtime.Value{
-					Type: runtime.INTEGER,
-					Int:  int(val.Num), // cast Go = floor pour positifs
-				}, nil
-			} else {
-				// Si négatif alors partie entière - 1
-				// INT (-1,75) -> -2
-				return runtime.Value{
-					Type: runtime.INTEGER,
-					Int:  int(val.Num) - 1,
-				}, nil
-			}
+		val, msg := intFn(val)
+		if msg != "" {
+			return fail(msg, "")
 		}
-
-		return runtime.Value{}, errors.NewSyntax(
-			line, col, tok,
-			"INVALID INT OPERAND",
-		)
+		return val, nil
 
 	case *parser.AbsExpr:
 		val, err := EvalExpr(e.Expr, rt)
@@ -348,37 +128,11 @@ func EvalExpr(expr parser.Expression, rt *runtime.Runtime) (runtime.Value, *erro
 			return runtime.Value{}, err
 		}
 
-		switch val.Type {
-
-		case runtime.STRING:
-			return runtime.Value{}, errors.NewSyntax(
-				line, col, tok,
-				"TYPE MISMATCH",
-			)
-
-		case runtime.INTEGER:
-			if val.Int < 0 {
-				return runtime.Value{
-					Type: runtime.INTEGER,
-					Int:  -val.Int,
-				}, nil
-			}
-			return val, nil
-
-		case runtime.NUMBER:
-			if val.Num < 0 {
-				return runtime.Value{
-					Type: runtime.NUMBER,
-					Num:  -val.Num,
-				}, nil
-			}
-			return val, nil
+		val, msg := absFn(val)
+		if msg != "" {
+			return fail(msg, "")
 		}
-
-		return runtime.Value{}, errors.NewSyntax(
-			line, col, tok,
-			"INVALID ABS OPERAND",
-		)
+		return val, nil
 
 	case *parser.SgnExpr:
 		val, err := EvalExpr(e.Expr, rt)
@@ -386,48 +140,11 @@ func EvalExpr(expr parser.Expression, rt *runtime.Runtime) (runtime.Value, *erro
 			return runtime.Value{}, err
 		}
 
-		var retVal float64
-		switch val.Type {
-
-		case runtime.STRING:
-			return runtime.Value{}, errors.NewSyntax(
-				line, col, tok,
-				"TYPE MISMATCH",
-			)
-
-		case runtime.INTEGER:
-			if val.Int < 0 {
-				retVal = -1
-			} else if val.Int > 0 {
-				retVal = 1
-			} else {
-				retVal = 0
-			}
-
-			return runtime.Value{
-				Type: runtime.INTEGER,
-				Int:  int(retVal),
-			}, nil
-
-		case runtime.NUMBER:
-			if val.Num < 0 {
-				retVal = -1
-			} else if val.Num > 0 {
-				retVal = 1
-			} else {
-				retVal = 0
-			}
-
-			return runtime.Value{
-				Type: runtime.INTEGER,
-				Int:  int(retVal),
-			}, nil
+		val, msg := sgnFn(val)
+		if msg != "" {
+			return fail(msg, "")
 		}
-
-		return runtime.Value{}, errors.NewSyntax(
-			line, col, tok,
-			"INVALID ABS OPERAND",
-		)
+		return val, nil
 
 	}
 
@@ -442,3 +159,161 @@ func EvalExpr(expr parser.Expression, rt *runtime.Runtime) (runtime.Value, *erro
 	)
 
 }
+
+// =========================
+// Opérations sur les valeurs
+// =========================
+//
+// Partagées par l'évaluateur d'AST, le VM et le repli des constantes.
+// Elles retournent un message d'erreur vide en cas de succès.
+//
+// Règles Applesoft : les calculs se font en réels (un entier est converti
+// par ToReal), les fonctions retournent un réel, et une chaîne ne se
+// mélange jamais à un nombre.
+
+// prefix applique + ou - unaire
+func prefix(op operator, right runtime.Value) (runtime.Value, string) {
+	f, err := right.ToReal()
+	if err != nil {
+		return runtime.Value{}, errTypeMismatch
+	}
+
+	switch op {
+	case addOp:
+		return runtime.NewReal(f), ""
+	case subOp:
+		return runtime.NewReal(-f), ""
+	}
+	return runtime.Value{}, errUnknownPrefix
+}
+
+// boolean convertit le résultat d'une comparaison (1 ou 0)
+func boolean(b bool) runtime.Value {
+	if b {
+		return runtime.NewReal(1)
+	}
+	return runtime.NewReal(0)
+}
+
+// infix applique un opérateur binaire
+func infix(op operator, left, right runtime.Value) (runtime.Value, string) {
+
+	// =========================
+	// STRING operations
+	// =========================
+	if left.Type == runtime.STRING || right.Type == runtime.STRING {
+		if left.Type != right.Type {
+			return runtime.Value{}, errTypeMismatch
+		}
+		return stringInfix(op, left.Str, right.Str)
+	}
+
+	// =========================
+	// Opérations numériques (réels)
+	// =========================
+	lf, _ := left.ToReal()
+	rf, _ := right.ToReal()
+
+	switch op {
+	case addOp:
+		return runtime.NewReal(lf + rf), ""
+	case subOp:
+		return runtime.NewReal(lf - rf), ""
+	case mulOp:
+		return runtime.NewReal(lf * rf), ""
+	case powOp:
+		return runtime.NewReal(math.Pow(lf, rf)), ""
+	case divOp:
+		if rf == 0 {
+			return runtime.Value{}, errDivisionByZero
+		}
+		return runtime.NewReal(lf / rf), ""
+	}
+
+	if cmp, ok := compare(op, lf, rf); ok {
+		return boolean(cmp), ""
+	}
+	return runtime.Value{}, errUnknownInfix
+}
+
+// stringInfix concatène (+) ou compare deux chaînes
+func stringInfix(op operator, l, r string) (runtime.Value, string) {
+	if op == addOp {
+		return runtime.NewString(l + r), ""
+	}
+
+	if cmp, ok := compare(op, l, r); ok {
+		return boolean(cmp), ""
+	}
+	return runtime.Value{}, errTypeMismatch
+}
+
+// compare applique un opérateur de comparaison
+func compare[T float64 | string](op operator, l, r T) (bool, bool) {
+	switch op {
+	case eqOp:
+		return l == r, true
+	case neOp:
+		return l != r, true
+	case ltOp:
+		return l < r, true
+	case gtOp:
+		return l > r, true
+	case leOp:
+		return l <= r, true
+	case geOp:
+		return l >= r, true
+	}
+	return false, false
+}
+
+// intFn calcule INT() : le plus grand entier inférieur ou égal
+// INT(1.75) → 1, INT(-1.75) → -2
+func intFn(val runtime.Value) (runtime.Value, string) {
+	f, err := val.ToReal()
+	if err != nil {
+		return runtime.Value{}, errTypeMismatch
+	}
+	return runtime.NewReal(math.Floor(f)), ""
+}
+
+// absFn calcule ABS()
+func absFn(val runtime.Value) (runtime.Value, string) {
+	f, err := val.ToReal()
+	if err != nil {
+		return runtime.Value{}, errTypeMismatch
+	}
+	return runtime.NewReal(math.Abs(f)), ""
+}
+
+// sgnFn calcule SGN() : -1, 0 ou 1
+func sgnFn(val runtime.Value) (runtime.Value, string) {
+	f, err := val.ToReal()
+	if err != nil {
+		return runtime.Value{}, errTypeMismatch
+	}
+
+	switch {
+	case f < 0:
+		return runtime.NewReal(-1), ""
+	case f > 0:
+		return runtime.NewReal(1), ""
+	}
+	return runtime.NewReal(0), ""
+}
+
+// assignError est le message d'une affectation refusée à une variable du
+// type kind
+func assignError(kind runtime.Kind, err error) string {
+	if err == runtime.ErrIllegalQuantity {
+		return "ILLEGAL QUANTITY"
+	}
+
+	switch kind {
+	case runtime.IntegerKind:
+		return "TYPE MISMATCH: INTEGER EXPECTED"
+	case runtime.StringKind:
+		return "TYPE MISMATCH: STRING EXPECTED"
+	}
+	return "TYPE MISMATCH: FLOAT EXPECTED"
+}
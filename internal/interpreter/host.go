@@ -0,0 +1,60 @@
+package interpreter
+
+import "basics/internal/runtime"
+
+// HostStatement est une instruction ajoutée par le programme hôte
+// (parser.Extend) ; elle reçoit ses arguments évalués
+type HostStatement func(args []runtime.Value) error
+
+// HostFunction est une fonction ajoutée par le programme hôte
+type HostFunction func(args []runtime.Value) (runtime.Value, error)
+
+// DefineStatement fournit l'implémentation d'une instruction ajoutée
+func (i *Interpreter) DefineStatement(name string, fn HostStatement) {
+	if i.statements == nil {
+		i.statements = map[string]HostStatement{}
+	}
+	i.statements[name] = fn
+}
+
+// DefineFunction fournit l'implémentation d'une fonction ajoutée
+func (i *Interpreter) DefineFunction(name string, fn HostFunction) {
+	if i.functions == nil {
+		i.functions = map[string]HostFunction{}
+	}
+	i.functions[name] = fn
+}
+
+// callStatement exécute une instruction ajoutée ; il retourne le message
+// de l'erreur à afficher
+func (i *Interpreter) callStatement(name string, args []runtime.Value) string {
+	fn, ok := i.statements[name]
+	if !ok {
+		return "UNDEFINED STATEMENT " + name
+	}
+	if err := fn(args); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// callFunction évalue une fonction ajoutée
+func (i *Interpreter) callFunction(name string, args []runtime.Value) (runtime.Value, string) {
+	fn, ok := i.functions[name]
+	if !ok {
+		return runtime.Value{}, "UNDEFINED FUNCTION " + name
+	}
+	val, err := fn(args)
+	if err != nil {
+		return runtime.Value{}, err.Error()
+	}
+	return val, ""
+}
+
+// popArgs dépile les n arguments d'un appel, dans l'ordre
+func popArgs(stack *[]runtime.Value, n int) []runtime.Value {
+	s := *stack
+	args := append([]runtime.Value(nil), s[len(s)-n:]...)
+	*stack = s[:len(s)-n]
+	return args
+}
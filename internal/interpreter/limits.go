@@ -0,0 +1,73 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"basics/internal/input"
+)
+
+// Causes d'arrêt retournées par RunContext
+var (
+	ErrBreak     = input.ErrBreak // Ctrl-C ou contexte annulé
+	ErrTimeLimit = errors.New("TIME LIMIT EXCEEDED")
+	ErrStepLimit = errors.New("STEP LIMIT EXCEEDED")
+
+	// INPUT ou GET sans plus rien à lire (fin de l'entrée standard)
+	ErrEndOfInput = errors.New("END OF INPUT")
+)
+
+// SetMaxSteps limite le nombre d'instructions BASIC exécutées par
+// RunContext ; 0 supprime la limite
+func (i *Interpreter) SetMaxSteps(n int) {
+	i.maxSteps = n
+}
+
+// Cause traduit l'annulation de ctx en cause d'arrêt : ErrTimeLimit si
+// son délai a expiré, ErrBreak sinon
+func Cause(ctx context.Context) error {
+	if errors.Is(context.Cause(ctx), context.DeadlineExceeded) {
+		return ErrTimeLimit
+	}
+	return ErrBreak
+}
+
+// limited indique si les instructions doivent être comptées (SetMaxSteps)
+func (i *Interpreter) limited() bool {
+	return i.maxSteps > 0
+}
+
+// step compte une instruction ; il retourne ErrStepLimit quand le nombre
+// fixé par SetMaxSteps est dépassé
+func (i *Interpreter) step() error {
+	i.steps++
+	if i.steps > i.maxSteps {
+		return ErrStepLimit
+	}
+	return nil
+}
+
+// watch lève i.cancelled quand ctx est annulé. Le VM ne teste ce drapeau
+// qu'aux sauts (boucles, GOTO, GOSUB, RETURN) : un programme sans limite ne
+// paie ni opStmt ni select par instruction. La fonction retournée arrête
+// la surveillance.
+func (i *Interpreter) watch(ctx context.Context) func() bool {
+	i.cancelled.Store(ctx.Err() != nil)
+	return context.AfterFunc(ctx, func() { i.cancelled.Store(true) })
+}
+
+// inputBreak retourne la cause d'arrêt d'une saisie : Ctrl-C sur le
+// device, fin de l'entrée ou contexte annulé pendant l'attente
+func (i *Interpreter) inputBreak(err error) error {
+	if errors.Is(err, input.ErrBreak) {
+		return ErrBreak
+	}
+	if errors.Is(err, io.EOF) {
+		return ErrEndOfInput
+	}
+	if i.ctx.Err() != nil {
+		return Cause(i.ctx)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package interpreter
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// WritePprof exporte le rapport au format pprof (profile.proto compressé
+// par gzip) : une fonction par ligne BASIC et un échantillon par
+// instruction, avec son nombre d'exécutions et sa durée cumulée. file est
+// le nom du programme affiché par pprof.
+func (prof *Profile) WritePprof(w io.Writer, file string) error {
+	p := &protoBuf{}
+	strs := map[string]int{}
+	var table []string
+	str := func(s string) int {
+		k, ok := strs[s]
+		if !ok {
+			k = len(table)
+			strs[s] = k
+			table = append(table, s)
+		}
+		return k
+	}
+	str("") // string_table[0] est toujours vide
+
+	valueType := func(typ, unit string) []byte {
+		v := &protoBuf{}
+		v.int(1, str(typ))
+		v.int(2, str(unit))
+		return v.b
+	}
+	p.bytes(1, valueType("statements", "count"))
+	p.bytes(1, valueType("time", "nanoseconds"))
+
+	// fonctions : une par ligne BASIC
+	functions := map[int]int{}
+	for _, l := range prof.Lines {
+		id := len(functions) + 1
+		functions[l.Line] = id
+
+		f := &protoBuf{}
+		f.int(1, id)
+		f.int(2, str(fmt.Sprintf("LINE %d", l.Line)))
+		f.int(3, str(fmt.Sprintf("LINE %d", l.Line)))
+		f.int(4, str(file))
+		f.int(5, l.Line)
+		p.bytes(5, f.b)
+	}
+
+	// un emplacement et un échantillon par instruction
+	for k, s := range prof.Statements {
+		id := k + 1
+
+		line := &protoBuf{}
+		line.int(1, functions[s.Line])
+		line.int(2, s.Line)
+
+		loc := &protoBuf{}
+		loc.int(1, id)
+		loc.bytes(4, line.b)
+		p.bytes(4, loc.b)
+
+		sample := &protoBuf{}
+		sample.packed(1, uint64(id))
+		sample.packed(2, uint64(s.Count), uint64(s.TimeNs))
+		sample.bytes(3, label(str("statement"), str(s.Statement)))
+		p.bytes(2, sample.b)
+	}
+
+	p.int(10, int(prof.TimeNs))
+	p.bytes(11, valueType("time", "nanoseconds"))
+	p.int(14, str("time"))
+
+	// la table des chaînes est complète : elle est écrite en dernier
+	for _, s := range table {
+		p.bytes(6, []byte(s))
+	}
+
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(p.b); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// label encode une étiquette texte d'échantillon
+func label(key, val int) []byte {
+	l := &protoBuf{}
+	l.int(1, key)
+	l.int(2, val)
+	return l.b
+}
+
+// protoBuf encode les quelques types protobuf utilisés par profile.proto
+type protoBuf struct {
+	b []byte
+}
+
+func (p *protoBuf) varint(x uint64) {
+	for x >= 0x80 {
+		p.b = append(p.b, byte(x)|0x80)
+		x >>= 7
+	}
+	p.b = append(p.b, byte(x))
+}
+
+// int écrit un champ entier ; les valeurs nulles sont omises
+func (p *protoBuf) int(field int, x int) {
+	if x == 0 {
+		return
+	}
+	p.varint(uint64(field) << 3)
+	p.varint(uint64(x))
+}
+
+// bytes écrit un champ de longueur variable (chaîne, message)
+func (p *protoBuf) bytes(field int, b []byte) {
+	p.varint(uint64(field)<<3 | 2)
+	p.varint(uint64(len(b)))
+	p.b = append(p.b, b...)
+}
+
+// packed écrit un champ répété d'entiers
+func (p *protoBuf) packed(field int, xs ...uint64) {
+	v := &protoBuf{}
+	for _, x := range xs {
+		v.varint(x)
+	}
+	p.bytes(field, v.b)
+}
@@ -0,0 +1,244 @@
+package interpreter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"basics/internal/parser"
+	"basics/internal/unparse"
+)
+
+//
+// =======================
+// Profileur
+// =======================
+//
+
+// Profiler compte les exécutions de chaque instruction BASIC et mesure leur
+// durée cumulée (temps réel, entrées / sorties comprises), ainsi que les
+// appels GOSUB et les tours des boucles FOR
+type Profiler struct {
+	code   *Bytecode
+	counts []int           // exécutions, par indice de Bytecode.stmts
+	times  []time.Duration // durée cumulée, par indice de Bytecode.stmts
+	lines  map[int]int     // ligne → exécutions depuis son début
+	gosubs map[int]int     // ligne appelée → appels
+	loops  map[int]int     // indice du FOR → tours
+
+	current int // instruction en cours, -1 avant la première
+	since   time.Time
+	start   time.Time
+	total   time.Duration
+	done    chan struct{}
+}
+
+// NewProfiler crée un profileur vide
+func NewProfiler() *Profiler {
+	return &Profiler{done: make(chan struct{})}
+}
+
+// SetProfiler attache un profileur à l'interpréteur, avant Run
+func (i *Interpreter) SetProfiler(p *Profiler) {
+	i.profiler = p
+}
+
+// begin prépare les compteurs du bytecode exécuté
+func (p *Profiler) begin(code *Bytecode) {
+	p.code = code
+	p.counts = make([]int, len(code.stmts))
+	p.times = make([]time.Duration, len(code.stmts))
+	p.lines = make(map[int]int)
+	p.gosubs = make(map[int]int)
+	p.loops = make(map[int]int)
+	p.current = -1
+	p.start = time.Now()
+	p.since = p.start
+}
+
+// statement attribue le temps écoulé à l'instruction précédente et compte
+// l'instruction k
+func (p *Profiler) statement(k int) {
+	now := time.Now()
+	if p.current >= 0 {
+		p.times[p.current] += now.Sub(p.since)
+	}
+	p.current, p.since = k, now
+
+	p.counts[k]++
+	if t := p.code.stmts[k]; t.index == 1 {
+		p.lines[t.line]++
+	}
+}
+
+// gosub compte un appel de la ligne
+func (p *Profiler) gosub(line int) {
+	p.gosubs[line]++
+}
+
+// iteration compte un tour de la boucle dont le FOR est au pc
+func (p *Profiler) iteration(pc int) {
+	if k := p.code.statementIndex(pc); k >= 0 {
+		p.loops[k]++
+	}
+}
+
+// end termine la mesure, en fin d'exécution
+func (p *Profiler) end() {
+	now := time.Now()
+	if p.current >= 0 {
+		p.times[p.current] += now.Sub(p.since)
+	}
+	p.total = now.Sub(p.start)
+	close(p.done)
+}
+
+//
+// =======================
+// Rapport
+// =======================
+//
+
+// Profile est le rapport d'un profileur. Les durées sont en nanosecondes.
+type Profile struct {
+	TimeNs     int64              `json:"time_ns"`
+	Lines      []LineProfile      `json:"lines"`
+	Statements []StatementProfile `json:"statements"`
+	Gosubs     []GosubProfile     `json:"gosubs"`
+	Loops      []LoopProfile      `json:"loops"`
+}
+
+type LineProfile struct {
+	Line   int   `json:"line"`
+	Count  int   `json:"count"` // exécutions depuis le début de la ligne
+	TimeNs int64 `json:"time_ns"`
+}
+
+type StatementProfile struct {
+	Line      int    `json:"line"`
+	Stmt      int    `json:"stmt"`
+	Statement string `json:"statement"`
+	Count     int    `json:"count"`
+	TimeNs    int64  `json:"time_ns"`
+}
+
+type GosubProfile struct {
+	Line  int `json:"line"`
+	Calls int `json:"calls"`
+}
+
+type LoopProfile struct {
+	Line       int    `json:"line"`
+	Stmt       int    `json:"stmt"`
+	Var        string `json:"var"`
+	Iterations int    `json:"iterations"`
+}
+
+// Profile retourne le rapport, dans l'ordre du programme ; nil tant que le
+// programme s'exécute
+func (p *Profiler) Profile() *Profile {
+	select {
+	case <-p.done:
+	default:
+		return nil
+	}
+
+	prof := &Profile{
+		TimeNs:     int64(p.total),
+		Lines:      []LineProfile{},
+		Statements: []StatementProfile{},
+		Gosubs:     []GosubProfile{},
+		Loops:      []LoopProfile{},
+	}
+
+	for k, t := range p.code.stmts {
+		if p.counts[k] == 0 {
+			continue
+		}
+		prof.Statements = append(prof.Statements, StatementProfile{
+			Line: t.line, Stmt: t.index, Statement: unparse.Statement(t.stmt),
+			Count: p.counts[k], TimeNs: int64(p.times[k]),
+		})
+
+		n := len(prof.Lines)
+		if n == 0 || prof.Lines[n-1].Line != t.line {
+			prof.Lines = append(prof.Lines, LineProfile{Line: t.line, Count: p.lines[t.line]})
+			n++
+		}
+		prof.Lines[n-1].TimeNs += int64(p.times[k])
+
+		if iter, ok := p.loops[k]; ok {
+			loop := LoopProfile{Line: t.line, Stmt: t.index, Iterations: iter}
+			if f, ok := t.stmt.(*parser.ForStmt); ok {
+				loop.Var = f.Var
+			}
+			prof.Loops = append(prof.Loops, loop)
+		}
+	}
+
+	for line, calls := range p.gosubs {
+		prof.Gosubs = append(prof.Gosubs, GosubProfile{Line: line, Calls: calls})
+	}
+	sort.Slice(prof.Gosubs, func(a, b int) bool { return prof.Gosubs[a].Line < prof.Gosubs[b].Line })
+
+	return prof
+}
+
+// WriteTable affiche le rapport en tableaux, les lignes et les instructions
+// les plus coûteuses en premier
+func (prof *Profile) WriteTable(w io.Writer) error {
+	var tw *tabwriter.Writer
+	section := func(header string) {
+		if tw != nil {
+			_ = tw.Flush()
+			fmt.Fprintln(w)
+		}
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(tw, header)
+	}
+	percent := func(ns int64) string {
+		if prof.TimeNs == 0 {
+			return "0.0%"
+		}
+		return fmt.Sprintf("%.1f%%", 100*float64(ns)/float64(prof.TimeNs))
+	}
+
+	lines := append([]LineProfile(nil), prof.Lines...)
+	sort.SliceStable(lines, func(a, b int) bool { return lines[a].TimeNs > lines[b].TimeNs })
+
+	section("LINE\tCOUNT\tTIME\t%TIME\t")
+	for _, l := range lines {
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t\n", l.Line, l.Count, time.Duration(l.TimeNs), percent(l.TimeNs))
+	}
+
+	stmts := append([]StatementProfile(nil), prof.Statements...)
+	sort.SliceStable(stmts, func(a, b int) bool { return stmts[a].TimeNs > stmts[b].TimeNs })
+
+	section("STATEMENT\tCOUNT\tTIME\t%TIME\t")
+	for _, s := range stmts {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t  %s\n", Location{s.Line, s.Stmt}, s.Count,
+			time.Duration(s.TimeNs), percent(s.TimeNs), s.Statement)
+	}
+
+	if len(prof.Gosubs) > 0 {
+		section("GOSUB\tCALLS\t")
+		for _, g := range prof.Gosubs {
+			fmt.Fprintf(tw, "%d\t%d\t\n", g.Line, g.Calls)
+		}
+	}
+
+	if len(prof.Loops) > 0 {
+		section("FOR\tITERATIONS\t")
+		for _, l := range prof.Loops {
+			fmt.Fprintf(tw, "%s\t%d\t  %s\n", Location{l.Line, l.Stmt}, l.Iterations, l.Var)
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\nTOTAL %s\n", time.Duration(prof.TimeNs))
+	return err
+}
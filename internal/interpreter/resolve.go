@@ -0,0 +1,41 @@
+package interpreter
+
+import (
+	"basics/internal/parser"
+	"basics/internal/runtime"
+)
+
+// Resolve déclare chaque variable du programme dans env, dans l'ordre
+// d'apparition, et retourne ses symboles. Tous les slots sont ainsi alloués
+// avant l'exécution.
+func Resolve(prog *parser.Program, env *runtime.Environment) []runtime.Symbol {
+	var symbols []runtime.Symbol
+	seen := make(map[runtime.Symbol]bool)
+
+	declare := func(name string) {
+		if name == "" {
+			return
+		}
+		sym := env.Declare(name)
+		if !seen[sym] {
+			seen[sym] = true
+			symbols = append(symbols, sym)
+		}
+	}
+
+	parser.Inspect(prog, func(node any) bool {
+		switch n := node.(type) {
+		case *parser.LetStmt:
+			declare(n.Name)
+		case *parser.ForStmt:
+			declare(n.Var)
+		case *parser.NextStmt:
+			declare(n.Var)
+		case *parser.Identifier:
+			declare(n.Name)
+		}
+		return true
+	})
+
+	return symbols
+}
@@ -0,0 +1,140 @@
+package interpreter
+
+import (
+	"math"
+
+	"basics/internal/errors"
+	"basics/internal/parser"
+	"basics/internal/runtime"
+)
+
+// screenOp identifie une instruction écran / graphique
+type screenOp uint8
+
+const (
+	screenCls screenOp = iota
+	screenMode
+	screenLocate
+	screenInk
+	screenPen
+	screenPaper
+	screenBorder
+	screenPlot
+	screenDraw
+)
+
+// screenArgs retourne l'opération d'une instruction écran et ses arguments
+// dans l'ordre d'évaluation (couleurs optionnelles complétées)
+func screenArgs(stmt parser.Statement) (screenOp, []parser.Expression, bool) {
+	switch s := stmt.(type) {
+
+	case *parser.ClsStmt:
+		return screenCls, nil, true
+
+	case *parser.ModeStmt:
+		return screenMode, []parser.Expression{s.Expr}, true
+
+	case *parser.LocateStmt:
+		return screenLocate, []parser.Expression{s.X, s.Y}, true
+
+	case *parser.InkStmt:
+		color2 := s.Color2
+		if color2 == nil {
+			color2 = s.Color1
+		}
+		return screenInk, []parser.Expression{s.Ink, s.Color1, color2}, true
+
+	case *parser.PenStmt:
+		return screenPen, []parser.Expression{s.Expr}, true
+
+	case *parser.PaperStmt:
+		return screenPaper, []parser.Expression{s.Expr}, true
+
+	case *parser.BorderStmt:
+		color2 := s.Color2
+		if color2 == nil {
+			color2 = s.Color1
+		}
+		return screenBorder, []parser.Expression{s.Color1, color2}, true
+
+	case *parser.PlotStmt:
+		return screenPlot, withInk([]parser.Expression{s.X, s.Y}, s.Ink), true
+
+	case *parser.DrawStmt:
+		return screenDraw, withInk([]parser.Expression{s.X, s.Y}, s.Ink), true
+	}
+
+	return 0, nil, false
+}
+
+// withInk ajoute l'encre optionnelle de PLOT / DRAW
+func withInk(exprs []parser.Expression, ink parser.Expression) []parser.Expression {
+	if ink == nil {
+		return exprs
+	}
+	return append(exprs, ink)
+}
+
+// screen applique une opération écran à ses arguments déjà évalués
+func (i *Interpreter) screen(line int, op screenOp, args []int) *errors.Error {
+	switch op {
+
+	case screenCls:
+		i.rt.ExecCls()
+
+	case screenMode:
+		return improper(line, i.rt.ExecMode(args[0]))
+
+	case screenLocate:
+		if args[0] < 1 || args[0] > 255 || args[1] < 1 || args[1] > 255 {
+			return errors.NewSemantic(line, "IMPROPER ARGUMENT")
+		}
+		i.rt.ExecLocate(args[0], args[1])
+
+	case screenInk:
+		return improper(line, i.rt.ExecInk(args[0], args[1], args[2]))
+
+	case screenPen:
+		return improper(line, i.rt.ExecPen(args[0]))
+
+	case screenPaper:
+		return improper(line, i.rt.ExecPaper(args[0]))
+
+	case screenBorder:
+		return improper(line, i.rt.ExecBorder(args[0], args[1]))
+
+	case screenPlot, screenDraw:
+		// encre optionnelle
+		if len(args) > 2 {
+			if err := improper(line, i.rt.ExecGraphicsPen(args[2])); err != nil {
+				return err
+			}
+		}
+		if op == screenPlot {
+			i.rt.ExecPlot(args[0], args[1])
+		} else {
+			i.rt.ExecDraw(args[0], args[1])
+		}
+	}
+
+	return nil
+}
+
+// intArg arrondit un argument numérique
+func intArg(val runtime.Value) (int, bool) {
+	switch val.Type {
+	case runtime.INTEGER:
+		return val.Int, true
+	case runtime.NUMBER:
+		return int(math.Round(val.Num)), true
+	}
+	return 0, false
+}
+
+// improper convertit une erreur de device en erreur BASIC
+func improper(line int, err error) *errors.Error {
+	if err == nil {
+		return nil
+	}
+	return errors.NewSemantic(line, "IMPROPER ARGUMENT")
+}
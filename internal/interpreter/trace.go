@@ -0,0 +1,130 @@
+package interpreter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"basics/internal/parser"
+	"basics/internal/runtime"
+	"basics/internal/unparse"
+)
+
+//
+// =======================
+// TRACE (Applesoft)
+// =======================
+//
+
+// traceLine affiche "#ligne" quand TRACE est actif et qu'une ligne commence :
+// en séquence ou par un saut, mais pas au retour d'un NEXT ou d'un RETURN
+// au milieu d'une ligne
+func (i *Interpreter) traceLine(t traced) {
+	if i.tracing && t.index == 1 {
+		i.rt.ExecPrint("#" + strconv.Itoa(t.line) + " ")
+	}
+}
+
+// usesTrace indique si le programme contient une instruction TRACE
+func usesTrace(prog *parser.Program) bool {
+	found := false
+	parser.Inspect(prog, func(node any) bool {
+		if _, ok := node.(*parser.TraceStmt); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+//
+// =======================
+// Trace JSON (--trace)
+// =======================
+//
+
+// TraceEvent est l'exécution d'une instruction BASIC, écrite sur une ligne
+// JSON par le Tracer
+type TraceEvent struct {
+	PC        int          `json:"pc"`
+	Line      int          `json:"line"`
+	Stmt      int          `json:"stmt"` // rang dans la ligne (Location.Stmt)
+	Statement string       `json:"statement"`
+	Values    []TraceValue `json:"values,omitempty"`
+}
+
+// TraceValue est une valeur évaluée par l'instruction. Name est la
+// variable affectée ou le rôle de la valeur (IF, TO, STEP, GOTO...), vide
+// pour une expression de PRINT.
+type TraceValue struct {
+	Name  string `json:"name,omitempty"`
+	Value any    `json:"value"`
+}
+
+// Tracer écrit la trace d'exécution d'un programme, un événement JSON par
+// instruction exécutée
+type Tracer struct {
+	w     *bufio.Writer
+	enc   *json.Encoder
+	event *TraceEvent // instruction en cours, écrite à la suivante
+	err   error
+}
+
+// NewTracer crée une trace écrite dans w
+func NewTracer(w io.Writer) *Tracer {
+	bw := bufio.NewWriter(w)
+	return &Tracer{w: bw, enc: json.NewEncoder(bw)}
+}
+
+// SetTracer attache une trace JSON à l'interpréteur, avant Run
+func (i *Interpreter) SetTracer(t *Tracer) {
+	i.tracer = t
+}
+
+// Err retourne la première erreur d'écriture
+func (t *Tracer) Err() error {
+	return t.err
+}
+
+// statement commence l'événement d'une instruction
+func (t *Tracer) statement(tr traced) {
+	t.write()
+	t.event = &TraceEvent{PC: tr.pc, Line: tr.line, Stmt: tr.index, Statement: unparse.Statement(tr.stmt)}
+}
+
+// value ajoute une valeur à l'instruction en cours
+func (t *Tracer) value(name string, v runtime.Value) {
+	if t.event == nil {
+		return
+	}
+
+	var val any
+	switch v.Type {
+	case runtime.INTEGER:
+		val = v.Int
+	case runtime.STRING:
+		val = v.Str
+	default:
+		val = v.Num
+	}
+	t.event.Values = append(t.event.Values, TraceValue{Name: name, Value: val})
+}
+
+// write écrit l'événement en cours
+func (t *Tracer) write() {
+	if t.event == nil || t.err != nil {
+		t.event = nil
+		return
+	}
+	t.err = t.enc.Encode(t.event)
+	t.event = nil
+}
+
+// flush écrit la dernière instruction, en fin d'exécution
+func (t *Tracer) flush() {
+	t.write()
+	if err := t.w.Flush(); t.err == nil {
+		t.err = err
+	}
+}
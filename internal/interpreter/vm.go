@@ -0,0 +1,558 @@
+package interpreter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"basics/internal/errors"
+	"basics/internal/logger"
+	"basics/internal/parser"
+	"basics/internal/runtime"
+)
+
+//
+// =======================
+// Machine virtuelle
+// =======================
+//
+
+// Run compile le programme en bytecode puis l'exécute
+func (i *Interpreter) Run(prog *parser.Program) {
+	_ = i.RunContext(context.Background(), prog)
+}
+
+// RunContext exécute le programme comme Run. Il s'arrête sur BREAK quand
+// ctx est annulé, sur TIME LIMIT EXCEEDED quand son délai expire et sur
+// STEP LIMIT EXCEEDED après le nombre d'instructions fixé par SetMaxSteps.
+// L'erreur retournée est la cause de l'arrêt (ErrBreak, ErrTimeLimit,
+// ErrStepLimit) ; elle est nil quand le programme se termine, même sur une
+// erreur BASIC.
+func (i *Interpreter) RunContext(ctx context.Context, prog *parser.Program) error {
+	i.ctx, i.steps, i.stopped = ctx, 0, nil
+	defer i.watch(ctx)()
+
+	code := i.bytecode(prog)
+
+	logger.Debug("Program execution trace")
+	logger.Debug("Program compiled", "lines", len(prog.Lines), "instructions", code.Len())
+
+	if i.debug != nil {
+		i.debug.code = code
+		defer i.debug.exit()
+	}
+	if i.tracer != nil {
+		defer i.tracer.flush()
+	}
+	if i.profiler != nil {
+		i.profiler.begin(code)
+		defer i.profiler.end()
+	}
+	if i.coverage != nil {
+		i.coverage.begin(code)
+		defer i.coverage.end()
+	}
+	i.exec(code)
+	return i.stopped
+}
+
+// bytecode compile le programme ; les instructions ne sont marquées (opStmt)
+// que si une option les observe ou les compte
+func (i *Interpreter) bytecode(prog *parser.Program) *Bytecode {
+	trace := logger.Enabled(logger.LevelDebug)
+	return compile(prog, i.rt.Env, trace || i.debug != nil || i.tracer != nil || i.profiler != nil || i.coverage != nil || i.limited() || usesTrace(prog))
+}
+
+// exec est la boucle du VM. Les erreurs arrêtent le programme comme dans
+// l'évaluateur d'AST, avec les mêmes messages.
+func (i *Interpreter) exec(b *Bytecode) {
+	env := i.rt.Env
+	trace := logger.Enabled(logger.LevelDebug)
+	tracer := i.tracer
+	profiler := i.profiler
+	limited := i.limited()
+	cancelled := &i.cancelled
+	code := b.code
+
+	stack := make([]runtime.Value, 0, 16)
+	cursor := 0 // colonne dans le PRINT en cours (tabulation ',')
+
+	// syntax et semantic placent une erreur sur l'instruction courante
+	syntax := func(in instr, msg string) {
+		p := b.pos[in.pos]
+		i.rt.ExecError(errors.NewSyntax(p.line, p.col, p.tok, msg))
+	}
+	semantic := func(in instr, msg string) {
+		i.rt.ExecError(errors.NewSemantic(b.pos[in.pos].line, msg))
+	}
+	undefined := func(in instr) {
+		semantic(in, "UNDEFINED VARIABLE "+b.pos[in.pos].tok)
+	}
+	// interrupt arrête le programme sur une cause de RunContext
+	interrupt := func(line int, err error) {
+		i.stopped = err
+		i.rt.ExecError(errors.NewSemantic(line, err.Error()))
+	}
+	// stop arrête le programme quand ctx est annulé. Il n'est testé qu'aux
+	// sauts en arrière : une boucle sans fin en contient forcément.
+	stop := func(in instr) {
+		interrupt(b.pos[in.pos].line, Cause(i.ctx))
+	}
+
+	pc := 0
+	for pc < len(code) {
+		in := code[pc]
+		pc++
+
+		switch in.op {
+
+		// -----------------------
+		// Expressions
+		// -----------------------
+		case opConst:
+			stack = append(stack, b.consts[in.a])
+
+		case opLoadReal:
+			v, ok := env.Real(int(in.a))
+			if !ok {
+				undefined(in)
+				return
+			}
+			stack = append(stack, runtime.Value{Type: runtime.NUMBER, Num: v})
+
+		case opLoadInt:
+			v, ok := env.Int(int(in.a))
+			if !ok {
+				undefined(in)
+				return
+			}
+			stack = append(stack, runtime.Value{Type: runtime.INTEGER, Int: v})
+
+		case opLoadStr:
+			v, ok := env.Str(int(in.a))
+			if !ok {
+				undefined(in)
+				return
+			}
+			stack = append(stack, runtime.Value{Type: runtime.STRING, Str: v})
+
+		case opPrefix:
+			top := len(stack) - 1
+			val, msg := prefix(operator(in.a), stack[top])
+			if msg != "" {
+				syntax(in, msg)
+				return
+			}
+			stack[top] = val
+
+		case opInfix:
+			top := len(stack) - 1
+			val, msg := infix(operator(in.a), stack[top-1], stack[top])
+			if msg != "" {
+				syntax(in, msg)
+				return
+			}
+			stack[top-1] = val
+			stack = stack[:top]
+
+		case opInt, opAbs, opSgn:
+			top := len(stack) - 1
+			var val runtime.Value
+			var msg string
+			switch in.op {
+			case opInt:
+				val, msg = intFn(stack[top])
+			case opAbs:
+				val, msg = absFn(stack[top])
+			default:
+				val, msg = sgnFn(stack[top])
+			}
+			if msg != "" {
+				syntax(in, msg)
+				return
+			}
+			stack[top] = val
+
+		// -----------------------
+		// LET
+		// -----------------------
+		case opStoreReal:
+			f, err := pop(&stack).ToReal()
+			if err != nil {
+				semantic(in, assignError(runtime.RealKind, err))
+				return
+			}
+			env.SetReal(int(in.a), f)
+			if tracer != nil {
+				tracer.value(b.names[runtime.RealKind][in.a], runtime.NewReal(f))
+			}
+
+		case opStoreInt:
+			// Applesoft : troncature, sur 16 bits
+			n, err := pop(&stack).ToInt16()
+			if err != nil {
+				semantic(in, assignError(runtime.IntegerKind, err))
+				return
+			}
+			env.SetInt(int(in.a), n)
+			if tracer != nil {
+				tracer.value(b.names[runtime.IntegerKind][in.a], runtime.Value{Type: runtime.INTEGER, Int: n})
+			}
+
+		case opStoreStr:
+			str, err := pop(&stack).ToString()
+			if err != nil {
+				semantic(in, assignError(runtime.StringKind, err))
+				return
+			}
+			env.SetStr(int(in.a), str)
+			if tracer != nil {
+				tracer.value(b.names[runtime.StringKind][in.a], runtime.Value{Type: runtime.STRING, Str: str})
+			}
+
+		// -----------------------
+		// PRINT
+		// -----------------------
+		case opPrint:
+			val := pop(&stack)
+			if tracer != nil {
+				tracer.value("", val)
+			}
+			str := printString(val)
+
+			switch rune(in.a) {
+			case 0:
+				cursor = 0
+			case ',':
+				str = strings.Repeat(" ", 14-(cursor%14)) + str
+			}
+
+			i.rt.ExecPrint(str)
+			cursor += len(str)
+
+		case opNewline:
+			i.rt.ExecPrint("\n")
+
+		// -----------------------
+		// Écran
+		// -----------------------
+		case opHome:
+			i.rt.ExecHome()
+
+		case opTrace:
+			i.tracing = true
+
+		case opNoTrace:
+			i.tracing = false
+
+		case opHTab, opVTab:
+			f, err := pop(&stack).ToReal()
+			if err != nil {
+				semantic(in, "TYPE MISMATCH")
+				return
+			}
+			if tracer != nil {
+				tracer.value(in.op.String(), runtime.NewReal(f))
+			}
+			if in.op == opHTab {
+				i.rt.ExecHTab(int(f))
+			} else {
+				i.rt.ExecVTab(int(f))
+			}
+
+		case opArg:
+			top := len(stack) - 1
+			n, ok := intArg(stack[top])
+			if !ok {
+				semantic(in, "TYPE MISMATCH")
+				return
+			}
+			stack[top] = runtime.Value{Type: runtime.INTEGER, Int: n}
+
+		case opScreen:
+			var args [3]int
+			n := int(in.b)
+			for k := 0; k < n; k++ {
+				args[k] = stack[len(stack)-n+k].Int
+			}
+			stack = stack[:len(stack)-n]
+
+			if err := i.screen(b.pos[in.pos].line, screenOp(in.a), args[:n]); err != nil {
+				i.rt.ExecError(err)
+				return
+			}
+
+		// -----------------------
+		// INPUT / GET
+		// -----------------------
+		case opInput:
+			if err := i.execInput(b.inputs[in.a]); err != nil {
+				interrupt(b.pos[in.pos].line, err)
+				return
+			}
+			if tracer != nil {
+				for _, v := range b.inputs[in.a].Vars {
+					if val, ok := env.Get(v.Name); ok {
+						tracer.value(v.Name, val)
+					}
+				}
+			}
+
+		case opGet:
+			if err := i.execGet(b.gets[in.a]); err != nil {
+				interrupt(b.pos[in.pos].line, err)
+				return
+			}
+			if tracer != nil {
+				name := b.gets[in.a].Var.Name
+				if val, ok := env.Get(name); ok {
+					tracer.value(name, val)
+				}
+			}
+
+		// -----------------------
+		// FOR / NEXT (Applesoft semantics)
+		// -----------------------
+		case opFor:
+			top := len(stack)
+			start, errStart := stack[top-3].ToReal()
+			end, errEnd := stack[top-2].ToReal()
+			step, errStep := stack[top-1].ToReal()
+			stack = stack[:top-3]
+
+			if errStart != nil || errEnd != nil || errStep != nil {
+				semantic(in, "TYPE MISMATCH")
+				return
+			}
+			if step == 0 {
+				semantic(in, "STEP CANNOT BE ZERO")
+				return
+			}
+
+			sym := runtime.Symbol{Kind: runtime.Kind(in.b), Slot: int(in.a)}
+			sym.Name = b.names[sym.Kind][sym.Slot]
+			if tracer != nil {
+				tracer.value(sym.Name, runtime.NewReal(start))
+				tracer.value("TO", runtime.NewReal(end))
+				tracer.value("STEP", runtime.NewReal(step))
+			}
+
+			// 🔹 Initialisation TOUJOURS faite
+			if err := env.Store(sym, runtime.NewReal(start)); err != nil {
+				semantic(in, assignError(sym.Kind, err))
+				return
+			}
+
+			if profiler != nil {
+				profiler.iteration(pc - 1)
+			}
+
+			// 🔹 Empiler SANS TEST (un FOR relancé remplace sa boucle)
+			i.forStack.Unwind(sym)
+			i.forStack.Push(ForFrame{
+				Var:     sym.Name,
+				Symbol:  sym,
+				End:     float64(int(end + 0.5)),
+				Step:    step,
+				PCStart: pc - 1,
+			})
+
+		case opNext:
+			frame := i.forStack.Top()
+			if frame == nil {
+				fmt.Println("?NEXT WITHOUT FOR")
+				return
+			}
+
+			var v float64
+			if frame.Symbol.Kind == runtime.RealKind {
+				// cas courant : sans passer par une Value
+				v, _ = env.Real(frame.Symbol.Slot)
+			} else {
+				val, _ := env.Load(frame.Symbol)
+				v, _ = val.ToReal()
+			}
+			v += frame.Step
+
+			done := (frame.Step > 0 && v > frame.End) ||
+				(frame.Step < 0 && v < frame.End)
+
+			if done {
+				i.forStack.Pop()
+				break
+			}
+
+			if err := env.Store(frame.Symbol, runtime.NewReal(v)); err != nil {
+				semantic(in, assignError(frame.Symbol.Kind, err))
+				return
+			}
+			if profiler != nil {
+				profiler.iteration(frame.PCStart)
+			}
+			if cancelled.Load() {
+				stop(in)
+				return
+			}
+			pc = frame.PCStart + 1
+
+		// -----------------------
+		// Sauts
+		// -----------------------
+		case opJump:
+			if int(in.a) < pc && cancelled.Load() {
+				stop(in)
+				return
+			}
+			pc = int(in.a)
+
+		case opJumpFalse:
+			cond := pop(&stack)
+			if tracer != nil {
+				tracer.value("IF", cond)
+			}
+			if !truthy(cond) {
+				pc = int(in.a)
+			}
+
+		case opGoto, opGosubLine:
+			f, err := pop(&stack).ToReal()
+			if err != nil {
+				if in.op == opGoto {
+					fmt.Println("?GOTO TYPE MISMATCH")
+				} else {
+					fmt.Println("?GOSUB TYPE MISMATCH")
+				}
+				return
+			}
+
+			line := int(f)
+			if tracer != nil {
+				name := "GOTO"
+				if in.op == opGosubLine {
+					name = "GOSUB"
+				}
+				tracer.value(name, runtime.NewReal(float64(line)))
+			}
+			target, ok := b.lines[line]
+			if !ok {
+				fmt.Printf("?UNDEFINED LINE %d\n", line)
+				return
+			}
+
+			if in.op == opGosubLine {
+				// ⚠️ empiler l'instruction SUIVANTE
+				i.gosubStack.Push(pc)
+				if profiler != nil {
+					profiler.gosub(line)
+				}
+			}
+			if target < pc && cancelled.Load() {
+				stop(in)
+				return
+			}
+			pc = target
+
+		case opGosub:
+			i.gosubStack.Push(pc)
+			if profiler != nil {
+				profiler.gosub(int(in.b))
+			}
+			if int(in.a) < pc && cancelled.Load() {
+				stop(in)
+				return
+			}
+			pc = int(in.a)
+
+		case opReturn:
+			retPC, ok := i.gosubStack.Pop()
+			if !ok {
+				fmt.Println("?RETURN WITHOUT GOSUB")
+				return
+			}
+			if retPC < pc && cancelled.Load() {
+				stop(in)
+				return
+			}
+			pc = retPC
+
+		case opEnd:
+			i.rt.Halt()
+			return
+
+		case opFail:
+			i.rt.ExecError(b.faults[in.a])
+			return
+
+		// -----------------------
+		// Instructions et fonctions ajoutées
+		// -----------------------
+		case opCall:
+			if msg := i.callStatement(b.calls[in.a], popArgs(&stack, int(in.b))); msg != "" {
+				semantic(in, msg)
+				return
+			}
+
+		case opCallFn:
+			val, msg := i.callFunction(b.calls[in.a], popArgs(&stack, int(in.b)))
+			if msg != "" {
+				syntax(in, msg)
+				return
+			}
+			stack = append(stack, val)
+
+		case opStmt:
+			t := b.stmts[in.a]
+			if limited {
+				if err := i.step(); err != nil {
+					interrupt(t.line, err)
+					return
+				}
+			}
+			if trace {
+				logger.Debug("Executing statement",
+					"line", t.line, "pc", pc-1,
+					"stmt", parser.StmtName(t.stmt), "args", strings.TrimSpace(parser.StmtArgs(t.stmt)))
+			}
+			if tracer != nil {
+				tracer.statement(t)
+			}
+			if profiler != nil {
+				profiler.statement(int(in.a))
+			}
+			if i.coverage != nil {
+				i.coverage.counts[in.a]++
+			}
+			i.traceLine(t)
+			if i.debug != nil && !i.debug.statement(t) {
+				return
+			}
+		}
+	}
+}
+
+// pop dépile une valeur
+func pop(stack *[]runtime.Value) runtime.Value {
+	s := *stack
+	v := s[len(s)-1]
+	*stack = s[:len(s)-1]
+	return v
+}
+
+// truthy évalue la condition d'un IF
+func truthy(cond runtime.Value) bool {
+	f, err := cond.ToReal()
+	return err == nil && f != 0
+}
+
+// printString formate une valeur affichée par PRINT
+func printString(val runtime.Value) string {
+	switch val.Type {
+	case runtime.INTEGER:
+		return formatNumber(float64(val.Int))
+	case runtime.NUMBER:
+		return formatNumber(val.Num)
+	case runtime.STRING:
+		return val.Str
+	}
+	return ""
+}
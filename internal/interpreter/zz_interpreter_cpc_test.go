@@ -0,0 +1,133 @@
+package interpreter
+
+import (
+	"basics/internal/constants"
+	"basics/internal/dialect"
+	"basics/internal/lexer"
+	"basics/internal/machines"
+	"basics/internal/machines/cpc"
+	"basics/internal/parser"
+	"basics/testutils"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// runLocomotive exécute un programme Locomotive BASIC sur un runtime CPC
+func runLocomotive(t *testing.T, program string) *cpc.Screen {
+	t.Helper()
+
+	rt, err := machines.NewRuntime(constants.BASIC_AMS)
+	testutils.True(t, "NewRuntime AMS", err == nil)
+
+	tokens := lexer.LexDialect(program, dialect.Locomotive)
+	p := parser.NewWithDialect(tokens, dialect.Locomotive)
+	prog, errs := p.ParseProgram()
+	testutils.Equal(t, "no parser errors", len(errs), 0)
+
+	New(rt).Run(prog)
+
+	screen, ok := rt.Video.(*cpc.Screen)
+	testutils.True(t, "video is cpc.Screen", ok)
+	return screen
+}
+
+func TestCPC_ModeInkBorder(t *testing.T) {
+	s := runLocomotive(t, `
+10 MODE 0
+20 INK 1,6,26
+30 BORDER 3
+40 PAPER 2
+50 PEN 5
+`)
+
+	testutils.Equal(t, "mode", s.CurrentMode(), 0)
+
+	c1, c2 := s.Ink(1)
+	testutils.Equal(t, "ink 1 colour 1", c1, 6)
+	testutils.Equal(t, "ink 1 colour 2", c2, 26)
+
+	b1, b2 := s.Border()
+	testutils.Equal(t, "border colour 1", b1, 3)
+	testutils.Equal(t, "border colour 2", b2, 3)
+
+	testutils.Equal(t, "paper", s.Text.BG, 2)
+	testutils.Equal(t, "pen", s.Text.FG, 5)
+}
+
+func TestCPC_LocateIsOneBased(t *testing.T) {
+	s := runLocomotive(t, `
+10 CLS
+20 LOCATE 10,5
+`)
+
+	testutils.Equal(t, "cursor x", s.Text.CursorX(), 9)
+	testutils.Equal(t, "cursor y", s.Text.CursorY(), 4)
+}
+
+func TestCPC_PlotDraw(t *testing.T) {
+	s := runLocomotive(t, `
+10 MODE 1
+20 PLOT 0,0,2
+30 DRAW 100,0
+40 DRAW 100,100,3
+`)
+
+	testutils.Equal(t, "plot origin", s.PixelAt(0, 0), 2)
+	testutils.Equal(t, "horizontal line", s.PixelAt(50, 0), 2)
+	testutils.Equal(t, "vertical line uses new ink", s.PixelAt(100, 50), 3)
+
+	x, y := s.GraphicsCursor()
+	testutils.Equal(t, "graphics cursor x", x, 100)
+	testutils.Equal(t, "graphics cursor y", y, 100)
+}
+
+func TestCPC_ImproperArgument(t *testing.T) {
+	tests := []struct {
+		name    string
+		program string
+	}{
+		{"MODE out of range", "10 MODE 3\n"},
+		{"INK out of range", "10 INK 16,1\n"},
+		{"colour out of range", "10 BORDER 27\n"},
+		{"LOCATE zero", "10 LOCATE 0,1\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := runLocomotive(t, tc.program)
+
+			got := screenLine(s, 0)
+			testutils.True(t, "IMPROPER ARGUMENT reported: "+got,
+				strings.Contains(got, "IMPROPER ARGUMENT"))
+		})
+	}
+}
+
+// screenLine retourne le texte d'une ligne de l'écran CPC
+func screenLine(s *cpc.Screen, y int) string {
+	var sb strings.Builder
+	for x := 0; x < s.Text.Buffer.Cols; x++ {
+		sb.WriteRune(s.Text.Buffer.CellAt(x, y).Glyph)
+	}
+	return sb.String()
+}
+
+func TestCPC_TTYIgnoresScreenStatements(t *testing.T) {
+	rt, _ := machines.NewRuntime(constants.BASIC_TTY)
+	out := &bytes.Buffer{}
+	rt.SetOutput(out)
+
+	program := `
+10 MODE 2
+20 INK 0,1
+30 PLOT 10,10
+40 PRINT "OK"
+`
+	prog, errs := parser.NewWithDialect(lexer.LexDialect(program, dialect.Locomotive), dialect.Locomotive).ParseProgram()
+	testutils.Equal(t, "no parser errors", len(errs), 0)
+
+	New(rt).Run(prog)
+
+	testutils.Equal(t, "output", out.String(), "OK\n")
+}
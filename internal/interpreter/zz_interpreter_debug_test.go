@@ -0,0 +1,177 @@
+package interpreter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"basics/internal/dialect"
+	"basics/internal/runtime"
+	"basics/testutils"
+)
+
+const debugSource = `10 A = 1: GOSUB 100
+15 B = 0
+20 FOR I = 1 TO 3: B = B + I: NEXT I
+30 PRINT B
+40 END
+100 A = A + 1
+110 RETURN
+`
+
+// startDebug exécute debugSource sous un débogueur ; done est fermé à la
+// fin du programme
+func startDebug(t *testing.T, setup func(d *Debugger)) (*Debugger, *bytes.Buffer, chan struct{}) {
+	t.Helper()
+
+	var out bytes.Buffer
+	interp := New(newTTY(t, &out))
+	d := NewDebugger(dialect.Applesoft)
+	setup(d)
+	interp.SetDebugger(d)
+
+	done := make(chan struct{})
+	prog := parseSource(t, debugSource)
+	go func() {
+		interp.Run(prog)
+		close(done)
+	}()
+	return d, &out, done
+}
+
+// nextStop attend l'arrêt suivant ; ok est faux à la fin du programme
+func nextStop(t *testing.T, d *Debugger) (Stop, bool) {
+	t.Helper()
+	select {
+	case stop, ok := <-d.Stops():
+		return stop, ok
+	case <-time.After(5 * time.Second):
+		t.Fatal("debugger did not stop")
+	}
+	return Stop{}, false
+}
+
+func TestDebugger_Stepping(t *testing.T) {
+	d, out, done := startDebug(t, func(d *Debugger) { d.StopOnEntry = true })
+
+	stop, _ := nextStop(t, d)
+	testutils.Equal(t, "entry", stop.Location, Location{10, 1})
+	testutils.Equal(t, "entry reason", stop.Reason, StopEntry)
+
+	d.Resume(StepIn)
+	stop, _ = nextStop(t, d)
+	testutils.Equal(t, "step to GOSUB", stop.Location, Location{10, 2})
+
+	d.Resume(StepIn)
+	stop, _ = nextStop(t, d)
+	testutils.Equal(t, "step into GOSUB", stop.Location, Location{100, 1})
+
+	frames := d.Frames()
+	testutils.Equal(t, "frames", len(frames), 2)
+	testutils.Equal(t, "caller", frames[1].Location, Location{10, 2})
+
+	d.Resume(StepOut)
+	stop, _ = nextStop(t, d)
+	testutils.Equal(t, "step out", stop.Location, Location{15, 1})
+	val, err := d.Evaluate("A * 10")
+	testutils.True(t, "evaluate", err == nil)
+	testutils.Equal(t, "A after GOSUB", val.Num, 20.0)
+
+	d.Resume(StepOver)
+	stop, _ = nextStop(t, d)
+	testutils.Equal(t, "step over", stop.Location, Location{20, 1})
+
+	d.Resume(Continue)
+	_, ok := nextStop(t, d)
+	testutils.False(t, "program ended", ok)
+	<-done
+	testutils.True(t, "output", strings.Contains(out.String(), "6"))
+}
+
+func TestDebugger_StepOverGosub(t *testing.T) {
+	d, _, done := startDebug(t, func(d *Debugger) {
+		_ = d.SetBreakpoint(Breakpoint{Line: 10, Stmt: 2})
+	})
+
+	stop, _ := nextStop(t, d)
+	testutils.Equal(t, "breakpoint", stop.Location, Location{10, 2})
+	testutils.Equal(t, "breakpoint reason", stop.Reason, StopBreakpoint)
+
+	d.Resume(StepOver)
+	stop, _ = nextStop(t, d)
+	testutils.Equal(t, "GOSUB stepped over", stop.Location, Location{15, 1})
+
+	d.Terminate()
+	_, ok := nextStop(t, d)
+	testutils.False(t, "terminated", ok)
+	<-done
+}
+
+func TestDebugger_Breakpoints(t *testing.T) {
+	d, _, done := startDebug(t, func(d *Debugger) {
+		_ = d.SetBreakpoint(Breakpoint{Line: 20})
+		_ = d.SetBreakpoint(Breakpoint{Line: 20, Stmt: 3, Cond: "I = 2"})
+	})
+
+	// un point d'arrêt de ligne ne s'arrête pas à chaque tour de la boucle
+	var stops []Location
+	for {
+		stop, ok := nextStop(t, d)
+		if !ok {
+			break
+		}
+		stops = append(stops, stop.Location)
+
+		if stop.Location == (Location{20, 3}) {
+			loops := d.Loops()
+			testutils.Equal(t, "loops", len(loops), 1)
+			testutils.Equal(t, "loop variable", loops[0].Var, "I")
+			testutils.Equal(t, "loop location", loops[0].Location, Location{20, 1})
+		}
+		d.Resume(Continue)
+	}
+	<-done
+	testutils.Equal(t, "stops", len(stops), 2)
+	testutils.Equal(t, "line breakpoint", stops[0], Location{20, 1})
+	testutils.Equal(t, "conditional breakpoint", stops[1], Location{20, 3})
+
+	err := NewDebugger(dialect.Applesoft).SetBreakpoint(Breakpoint{Line: 10, Cond: "A ="})
+	testutils.True(t, "invalid condition", err != nil)
+}
+
+func TestDebugger_Watch(t *testing.T) {
+	d, _, done := startDebug(t, func(d *Debugger) { d.Watch("B") })
+
+	stop, _ := nextStop(t, d)
+	testutils.Equal(t, "watch reason", stop.Reason, StopWatch)
+	testutils.Equal(t, "watch location", stop.Location, Location{20, 3})
+	testutils.Equal(t, "old value", stop.Old, runtime.NewReal(0))
+	testutils.Equal(t, "new value", stop.New, runtime.NewReal(1))
+
+	d.Unwatch("B")
+	d.Resume(Continue)
+	_, ok := nextStop(t, d)
+	testutils.False(t, "no more stops", ok)
+	<-done
+}
+
+func TestDebugger_Console(t *testing.T) {
+	d, _, done := startDebug(t, func(d *Debugger) { d.StopOnEntry = true })
+
+	var out bytes.Buffer
+	d.Console(strings.NewReader("break 100\nc\nbt\nprint A + 1\nbreaks\ndelete 100\nbreak 30\nc\nvars\n"), &out)
+	<-done
+
+	for _, want := range []string{
+		"⏸ 10:1 A = 1 (entry)",
+		"⏸ 100:1 A = A+1 (breakpoint)",
+		"#1 10:2 GOSUB 100",
+		"(basics) 2\n",
+		"break 100\n",
+		"⏸ 30:1 PRINT B (breakpoint)",
+		"B = 6\n",
+	} {
+		testutils.True(t, "console output has "+want, strings.Contains(out.String(), want))
+	}
+}
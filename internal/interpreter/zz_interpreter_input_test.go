@@ -1,6 +1,7 @@
 package interpreter
 
 import (
+	"basics/internal/common"
 	"basics/internal/constants"
 	"basics/internal/input"
 	"basics/internal/lexer"
@@ -9,6 +10,7 @@ import (
 	"basics/testutils"
 	"bytes"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -83,6 +85,15 @@ func TestINPUT_TableDriven(t *testing.T) {
 			input: "5\n",
 			want:  "? ",
 		},
+		{
+			name: "INPUT integer is truncated",
+			program: `
+10 INPUT A%
+20 PRINT A%
+`,
+			input: "2.7\n",
+			want:  "? 2\n",
+		},
 		/* {
 			name: "INPUT REENTER on invalid numeric",
 			program: `
@@ -124,3 +135,53 @@ func TestINPUT_TableDriven(t *testing.T) {
 		})
 	}
 }
+
+// scriptInput fournit des saisies successives ; fin de l'entrée ensuite
+type scriptInput struct {
+	r *strings.Reader
+}
+
+func (s *scriptInput) ReadLine() (string, error) {
+	var sb strings.Builder
+	for {
+		ch, _, err := s.r.ReadRune()
+		if err != nil && sb.Len() == 0 {
+			return "", err
+		}
+		if err != nil || ch == '\n' {
+			return sb.String(), nil
+		}
+		sb.WriteRune(ch)
+	}
+}
+
+func (s *scriptInput) GetChar() (rune, error) {
+	ch, _, err := s.r.ReadRune()
+	return ch, err
+}
+
+// Les nombres saisis par INPUT et GET suivent la syntaxe Applesoft ; une
+// saisie refusée, ou hors limites pour une variable entière, est redemandée
+func TestINPUT_NumberSyntax(t *testing.T) {
+	tests := []inputTestCase{
+		{"signs and exponent", "10 INPUT A,B,C\n20 PRINT A;\" \";B;\" \";C\n", "- 1 . 5 E 2, .25,+E-\n", "? -150 0.25 0\n"},
+		{"NaN refused", "10 INPUT A\n20 PRINT A\n", "NaN\n7\n", "? \n?TYPE MISMATCH, REENTER\n? 7\n"},
+		{"Inf refused", "10 INPUT A\n20 PRINT A\n", "Inf\n7\n", "? \n?TYPE MISMATCH, REENTER\n? 7\n"},
+		{"hexadecimal refused", "10 INPUT A\n20 PRINT A\n", "0x1p3\n7\n", "? \n?TYPE MISMATCH, REENTER\n? 7\n"},
+		{"underscore refused", "10 INPUT A\n20 PRINT A\n", "1_000\n7\n", "? \n?TYPE MISMATCH, REENTER\n? 7\n"},
+		{"two signs refused", "10 INPUT A\n20 PRINT A\n", "+-\n7\n", "? \n?TYPE MISMATCH, REENTER\n? 7\n"},
+		{"integer out of range", "10 INPUT A%\n20 PRINT A%\n", "40000\n-32767\n", "? \n?ILLEGAL QUANTITY, REENTER\n? -32767\n"},
+		{"GET digit", "10 GET A\n20 PRINT A\n", "X5", "\n?TYPE MISMATCH, REENTER\n5\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			rt := newTTY(t, &out)
+			rt.Input = &scriptInput{strings.NewReader(tc.input)}
+
+			New(rt).Run(parseSource(t, tc.program))
+			testutils.Equal(t, tc.name, common.StripANSI(out.String()), tc.want)
+		})
+	}
+}
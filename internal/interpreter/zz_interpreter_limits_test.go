@@ -0,0 +1,98 @@
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"basics/internal/input"
+	"basics/testutils"
+)
+
+// breakInput simule Ctrl-C pendant une saisie
+type breakInput struct{}
+
+func (breakInput) ReadLine() (string, error) { return "", input.ErrBreak }
+func (breakInput) GetChar() (rune, error)    { return 0, input.ErrBreak }
+
+func TestRunContext_StepLimit(t *testing.T) {
+	var out bytes.Buffer
+	interp := New(newTTY(t, &out))
+	interp.SetMaxSteps(5)
+
+	err := interp.RunContext(context.Background(), parseSource(t, "10 PRINT \"A\"\n20 GOTO 10\n"))
+	testutils.Equal(t, "error", err, ErrStepLimit)
+	testutils.Equal(t, "prints", strings.Count(out.String(), "A\n"), 3)
+	testutils.True(t, "message", strings.Contains(out.String(), "STEP LIMIT EXCEEDED IN 20"))
+}
+
+func TestRunContext_Timeout(t *testing.T) {
+	var out bytes.Buffer
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := New(newTTY(t, &out)).RunContext(ctx, parseSource(t, "10 X = 0\n20 X = X + 1\n30 GOTO 20\n"))
+	testutils.Equal(t, "error", err, ErrTimeLimit)
+	testutils.True(t, "message", strings.Contains(out.String(), "TIME LIMIT EXCEEDED"))
+}
+
+func TestRunContext_Cancel(t *testing.T) {
+	var out bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := New(newTTY(t, &out)).RunContext(ctx, parseSource(t, "10 PRINT \"A\"\n20 GOTO 10\n"))
+	testutils.Equal(t, "error", err, ErrBreak)
+	testutils.Equal(t, "output", out.String(), "A\n⚠️ BREAK IN 20 ()\n")
+}
+
+func TestRunContext_CancelLoops(t *testing.T) {
+	for _, src := range []string{
+		"10 FOR I = 1 TO 2\n20 I = 1\n30 NEXT I\n",
+		"10 GOSUB 10\n",
+		"10 L = 10\n20 GOTO L\n",
+		"10 GOSUB 30\n20 END\n30 GOTO 10\n",
+	} {
+		var out bytes.Buffer
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+
+		err := New(newTTY(t, &out)).RunContext(ctx, parseSource(t, src))
+		cancel()
+		testutils.Equal(t, "error", err, ErrTimeLimit)
+	}
+}
+
+// un contexte annulable sans budget d'instructions ne marque pas les
+// instructions : l'annulation n'est testée qu'aux sauts
+func TestRunContext_NoStmtWithoutBudget(t *testing.T) {
+	prog := parseSource(t, "10 PRINT 1\n20 GOTO 10\n")
+	interp := New(newTTY(t, &bytes.Buffer{}))
+	testutils.False(t, "without budget", strings.Contains(interp.bytecode(prog).Disassemble(), "STMT"))
+
+	interp.SetMaxSteps(10)
+	testutils.True(t, "with budget", strings.Contains(interp.bytecode(prog).Disassemble(), "STMT"))
+}
+
+func TestRunContext_BreakDuringInput(t *testing.T) {
+	for _, src := range []string{"10 INPUT A\n20 PRINT A\n", "10 GET A$\n20 PRINT A$\n"} {
+		var out bytes.Buffer
+		rt := newTTY(t, &out)
+		rt.Input = breakInput{}
+
+		err := New(rt).RunContext(context.Background(), parseSource(t, src))
+		testutils.Equal(t, "error", err, ErrBreak)
+		testutils.True(t, "message", strings.HasSuffix(out.String(), "BREAK IN 10 ()\n"))
+	}
+}
+
+func TestRunContext_WithinLimit(t *testing.T) {
+	var out bytes.Buffer
+	interp := New(newTTY(t, &out))
+	interp.SetMaxSteps(3)
+
+	err := interp.RunContext(context.Background(), parseSource(t, "10 PRINT 1\n20 PRINT 2\n30 END\n"))
+	testutils.Equal(t, "error", err, nil)
+	testutils.Equal(t, "output", out.String(), "1\n2\n")
+}
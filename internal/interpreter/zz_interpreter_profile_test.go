@@ -0,0 +1,54 @@
+package interpreter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"basics/testutils"
+)
+
+func TestProfiler_Counts(t *testing.T) {
+	var out bytes.Buffer
+	interp := New(newTTY(t, &out))
+	profiler := NewProfiler()
+	interp.SetProfiler(profiler)
+	testutils.True(t, "no profile before Run", profiler.Profile() == nil)
+
+	interp.Run(parseSource(t, `10 FOR I = 1 TO 3 : FOR J = 1 TO 2
+20 GOSUB 100 : NEXT J : NEXT I
+30 L = 100 : GOSUB L
+40 END
+100 N = I : RETURN
+`))
+	prof := profiler.Profile()
+
+	testutils.Equal(t, "lines", len(prof.Lines), 5)
+	testutils.Equal(t, "line 10 started once", prof.Lines[0].Count, 1)
+	testutils.Equal(t, "line 20 started after FOR J and by NEXT J", prof.Lines[1].Count, 6)
+	testutils.Equal(t, "line 100", prof.Lines[4], LineProfile{Line: 100, Count: 7, TimeNs: prof.Lines[4].TimeNs})
+
+	testutils.Equal(t, "statements", len(prof.Statements), 10)
+	testutils.Equal(t, "NEXT J", prof.Statements[3].Count, 6)
+	testutils.Equal(t, "NEXT J text", prof.Statements[3].Statement, "NEXT J")
+
+	testutils.Equal(t, "GOSUB lines", len(prof.Gosubs), 1)
+	testutils.Equal(t, "GOSUB calls (constant and computed)", prof.Gosubs[0], GosubProfile{Line: 100, Calls: 7})
+	testutils.Equal(t, "loops", len(prof.Loops), 2)
+	testutils.Equal(t, "FOR I", prof.Loops[0], LoopProfile{Line: 10, Stmt: 1, Var: "I", Iterations: 3})
+	testutils.Equal(t, "FOR J", prof.Loops[1], LoopProfile{Line: 10, Stmt: 2, Var: "J", Iterations: 6})
+
+	var table bytes.Buffer
+	testutils.True(t, "table", prof.WriteTable(&table) == nil)
+	testutils.True(t, "table sections", strings.Contains(table.String(), "GOSUB  CALLS"))
+
+	var pprof bytes.Buffer
+	testutils.True(t, "pprof", prof.WritePprof(&pprof, "test.bas") == nil)
+	zr, err := gzip.NewReader(&pprof)
+	testutils.True(t, "gzip", err == nil)
+	data, err := io.ReadAll(zr)
+	testutils.True(t, "gzip data", err == nil)
+	testutils.True(t, "string table", bytes.Contains(data, []byte("LINE 100")))
+}
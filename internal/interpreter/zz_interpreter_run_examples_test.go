@@ -323,6 +323,16 @@ World
 			expected: `Hello
 World
 !!!
+`,
+		},
+		{
+			name:   "Trace-01",
+			file:   "flow_control/trace-01-example.bas",
+			errors: 0,
+			expected: `#30 1
+2
+#40 #100 SOUS-PROGRAMME
+#50 FIN
 `,
 		},
 		{
@@ -345,7 +355,7 @@ Last line
 		{
 			name:     "HelloWorld-02",
 			file:     "others/hello-world-02-example.bas",
-			errors:   2,
+			errors:   1,
 			expected: ``,
 		},
 		{
@@ -687,14 +697,14 @@ All done!
 		{
 			name:   "Print-06",
 			file:   "display/print-06-example.bas",
-			errors: 2,
+			errors: 1,
 			expected: `⚠️ UNDEFINED VARIABLE A IN 3 ()
 `,
 		},
 		{
 			name:   "Print-07",
 			file:   "display/print-07-example.bas",
-			errors: 2,
+			errors: 1,
 			expected: `⚠️ UNDEFINED VARIABLE A IN 3 ()
 `,
 		},
@@ -0,0 +1,61 @@
+package interpreter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"basics/testutils"
+)
+
+// TRACE affiche chaque ligne qui commence, y compris par GOTO, mais pas au
+// retour d'un NEXT au milieu d'une ligne
+func TestTrace_LineNumbers(t *testing.T) {
+	src := `10 TRACE : N = 0
+20 FOR I = 1 TO 2 : N = N + I : NEXT I
+30 IF N < 6 THEN GOTO 20
+40 NOTRACE : PRINT N
+50 PRINT "END"
+`
+	tree, vm := runBoth(t, src)
+	testutils.Equal(t, "bytecode", vm, "#20 #30 #20 #30 #40 6\nEND\n")
+	testutils.Equal(t, "same as tree", tree, vm)
+}
+
+func TestTracer_JSONLines(t *testing.T) {
+	var out, trace bytes.Buffer
+	interp := New(newTTY(t, &out))
+	tracer := NewTracer(&trace)
+	interp.SetTracer(tracer)
+	interp.Run(parseSource(t, `10 A$ = "X" : B% = 2.5
+20 FOR I = 1 TO 1 : PRINT I * 2 : NEXT I
+30 IF B% = 2 THEN GOSUB 40 + 0 * B%
+40 END
+`))
+	testutils.True(t, "no write error", tracer.Err() == nil)
+
+	var events []TraceEvent
+	sc := bufio.NewScanner(&trace)
+	for sc.Scan() {
+		var ev TraceEvent
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			t.Fatalf("line %q: %v", sc.Text(), err)
+		}
+		events = append(events, ev)
+	}
+
+	testutils.Equal(t, "events", len(events), 8)
+	testutils.Equal(t, "first", events[0].Statement, `A$ = "X"`)
+	testutils.Equal(t, "string value", events[0].Values[0], TraceValue{Name: "A$", Value: "X"})
+	testutils.Equal(t, "integer value", events[1].Values[0], TraceValue{Name: "B%", Value: float64(2)})
+	testutils.Equal(t, "statement index", events[1].Stmt, 2)
+
+	testutils.Equal(t, "FOR values", len(events[2].Values), 3)
+	testutils.Equal(t, "TO", events[2].Values[1], TraceValue{Name: "TO", Value: float64(1)})
+	testutils.Equal(t, "PRINT value", events[3].Values[0], TraceValue{Value: float64(2)})
+
+	testutils.Equal(t, "IF condition", events[5].Values[0], TraceValue{Name: "IF", Value: float64(1)})
+	testutils.Equal(t, "computed GOSUB", events[6].Values[0], TraceValue{Name: "GOSUB", Value: float64(40)})
+	testutils.Equal(t, "last", events[7].Line, 40)
+}
@@ -0,0 +1,636 @@
+package interpreter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"basics/internal/errors"
+	"basics/internal/logger"
+	"basics/internal/parser"
+	"basics/internal/runtime"
+)
+
+// tree est l'évaluateur d'AST d'origine, remplacé par le bytecode : il sert
+// de référence aux tests et aux benchmarks de la VM
+type tree struct {
+	*Interpreter
+	insts     []Instruction
+	lineIndex map[int]int // line number → PC
+}
+
+func newTree(rt *runtime.Runtime) *tree {
+	return &tree{Interpreter: New(rt)}
+}
+
+// Instruction représente un statement exécutable
+type Instruction struct {
+	LineNum int
+	Stmt    parser.Statement
+}
+
+//
+// =======================
+// Programme → instructions
+// =======================
+//
+
+func (i *tree) buildInstructions(prog *parser.Program) {
+	i.insts = nil
+	i.lineIndex = make(map[int]int)
+
+	for _, line := range prog.Lines {
+
+		// index de la première instruction de la ligne
+		if _, exists := i.lineIndex[line.Number]; !exists {
+			i.lineIndex[line.Number] = len(i.insts)
+		}
+
+		for _, stmt := range line.Stmts {
+
+			switch s := stmt.(type) {
+
+			// =====================================================
+			// IF : aplatissement en flot linéaire (style Applesoft)
+			// =====================================================
+			case *parser.IfStmt:
+
+				// 1️⃣ réserver une instruction IF (patchée ensuite)
+				ifPC := len(i.insts)
+
+				i.insts = append(i.insts, Instruction{
+					LineNum: line.Number,
+					Stmt:    nil, // sera remplacé
+				})
+
+				// 2️⃣ THEN block (instructions normales)
+				for _, thenStmt := range s.Then {
+					i.insts = append(i.insts, Instruction{
+						LineNum: line.Number,
+						Stmt:    thenStmt,
+					})
+				}
+
+				// 3️⃣ ELSE block (optionnel)
+				var elseTarget int
+				if len(s.Else) > 0 {
+
+					// saut après THEN
+					gotoAfterThenPC := len(i.insts)
+
+					i.insts = append(i.insts, Instruction{
+						LineNum: line.Number,
+						Stmt: &parser.GotoStmt{
+							Expr: &parser.NumberLiteral{
+								Value: float64(-1), // patch plus tard
+							},
+						},
+					})
+
+					elseTarget = len(i.insts)
+
+					for _, elseStmt := range s.Else {
+						i.insts = append(i.insts, Instruction{
+							LineNum: line.Number,
+							Stmt:    elseStmt,
+						})
+					}
+
+					// patch du GOTO de fin de THEN
+					i.insts[gotoAfterThenPC].Stmt.(*parser.GotoStmt).Expr =
+						&parser.NumberLiteral{Value: float64(len(i.insts))}
+
+				} else {
+					elseTarget = len(i.insts)
+				}
+
+				// 4️⃣ patch de l’instruction IF
+				i.insts[ifPC].Stmt = &parser.IfJumpStmt{
+					Cond:   s.Cond,
+					Target: elseTarget,
+				}
+
+			// ==========================
+			// Autres instructions
+			// ==========================
+			default:
+				i.insts = append(i.insts, Instruction{
+					LineNum: line.Number,
+					Stmt:    stmt,
+				})
+			}
+		}
+	}
+}
+
+//
+// =======================
+// Boucle d'exécution
+// =======================
+//
+
+// run exécute le programme en parcourant l'AST instruction par instruction
+func (i *tree) run(prog *parser.Program) {
+	i.buildInstructions(prog)
+	logger.Debug("Program execution trace")
+	logger.Debug(fmt.Sprintf("Program contains %d lines and %d instructions", len(prog.Lines), len(i.insts)))
+
+	pc := 0
+	for pc < len(i.insts) {
+		inst := i.insts[pc]
+		nextPC := pc + 1
+		sExpr := ""
+
+		if i.tracing && pc == i.lineIndex[inst.LineNum] {
+			i.rt.ExecPrint("#" + strconv.Itoa(inst.LineNum) + " ")
+		}
+
+		switch s := inst.Stmt.(type) {
+
+		// -----------------------
+		// HOME
+		// -----------------------
+		case *parser.HomeStmt:
+			i.rt.ExecHome()
+
+		// -----------------------
+		// TRACE / NOTRACE
+		// -----------------------
+		case *parser.TraceStmt:
+			i.tracing = true
+
+		case *parser.NoTraceStmt:
+			i.tracing = false
+
+		// -----------------------
+		// END
+		// -----------------------
+		case *parser.EndStmt:
+			logger.Debug(LogTrace(inst, pc, nextPC, sExpr))
+			i.rt.Halt()
+			return
+
+		// -----------------------
+		// LET
+		// -----------------------
+		case *parser.LetStmt:
+			val, err := EvalExpr(s.Value, i.rt)
+			if err != nil {
+				i.rt.ExecError(err)
+				return
+			}
+
+			// conversion Applesoft vers le type de la variable
+			if err := i.rt.Env.Set(s.Name, val); err != nil {
+				msg := assignError(runtime.KindOf(s.Name), err)
+				i.rt.ExecError(errors.NewSemantic(inst.LineNum, msg))
+				return
+			}
+			stored, _ := i.rt.Env.Get(s.Name)
+			sExpr = printString(stored)
+
+		// -----------------------
+		// INPUT
+		// -----------------------
+		case *parser.InputStmt:
+			if i.execInput(s) != nil {
+				return
+			}
+
+		// -----------------------
+		// GET
+		// -----------------------
+		case *parser.GetStmt:
+			if i.execGet(s) != nil {
+				return
+			}
+			val, _ := i.rt.Env.Get(s.Var.Name)
+			sExpr = val.Str
+
+		// -----------------------
+		// PRINT
+		// -----------------------
+		case *parser.PrintStmt:
+			// PRINT sans arguments
+			if len(s.Exprs) == 0 {
+				i.rt.ExecPrint("\n")
+				break
+			}
+
+			cursor := 0
+
+			for iExpr, expr := range s.Exprs {
+				val, err := EvalExpr(expr, i.rt)
+				if err != nil {
+					i.rt.ExecError(err)
+					return
+				}
+
+				str := ""
+				switch val.Type {
+				case runtime.INTEGER:
+					str = formatNumber(float64(val.Int))
+				case runtime.NUMBER:
+					str = formatNumber(val.Num)
+				case runtime.STRING:
+					str = val.Str
+				}
+
+				if iExpr > 0 {
+					sep := s.Separators[iExpr-1]
+					if sep == ',' {
+						spaces := 14 - (cursor % 14)
+						str = strings.Repeat(" ", spaces) + str
+					}
+				}
+
+				sExpr += str
+
+				i.rt.ExecPrint(str)
+				cursor += len(str)
+			}
+
+			if len(s.Separators) < len(s.Exprs) {
+				i.rt.ExecPrint("\n")
+			}
+
+		// -----------------------
+		// HTAB / VTAB
+		// -----------------------
+		case *parser.HTabStmt:
+			val, err := EvalExpr(s.Expr, i.rt)
+			if err != nil {
+				i.rt.ExecError(err)
+				return
+			}
+
+			f, _ := val.ToReal()
+			sExpr = fmt.Sprintf("%d", int(f))
+			i.rt.ExecHTab(int(f))
+
+		case *parser.VTabStmt:
+			val, err := EvalExpr(s.Expr, i.rt)
+			if err != nil {
+				i.rt.ExecError(err)
+				return
+			}
+
+			f, _ := val.ToReal()
+			sExpr = fmt.Sprintf("%d", int(f))
+			i.rt.ExecVTab(int(f))
+
+		// -----------------------
+		// Écran & graphique (CPC)
+		// -----------------------
+		case *parser.ModeStmt, *parser.ClsStmt, *parser.LocateStmt,
+			*parser.InkStmt, *parser.PenStmt, *parser.PaperStmt,
+			*parser.BorderStmt, *parser.PlotStmt, *parser.DrawStmt:
+			if err := i.execScreen(inst.LineNum, s); err != nil {
+				i.rt.ExecError(err)
+				return
+			}
+
+		// -----------------------
+		// FOR (Applesoft semantics)
+		// -----------------------
+		case *parser.ForStmt:
+			startVal, err := EvalExpr(s.Start, i.rt)
+			if err != nil {
+				i.rt.ExecError(err)
+				return
+			}
+
+			endVal, err := EvalExpr(s.End, i.rt)
+			if err != nil {
+				i.rt.ExecError(err)
+				return
+			}
+
+			step := 1.0
+			if s.Step != nil {
+				stepVal, err := EvalExpr(s.Step, i.rt)
+				if err != nil {
+					fmt.Println(err)
+					return
+				}
+				step, _ = stepVal.ToReal()
+				if step == 0 {
+					err = errors.NewSemantic(
+						inst.LineNum,
+						"STEP CANNOT BE ZERO",
+					)
+					i.rt.ExecError(err)
+					return
+				}
+			}
+
+			start, _ := startVal.ToReal()
+			endNum, _ := endVal.ToReal()
+			end := float64(int(endNum + 0.5))
+
+			// 🔹 Initialisation TOUJOURS faite
+			_ = i.rt.Env.Set(s.Var, runtime.NewReal(start))
+
+			// 🔹 Empiler SANS TEST
+			i.forStack.Push(ForFrame{
+				Var:     s.Var,
+				End:     end,
+				Step:    step,
+				PCStart: pc,
+			})
+
+			sExpr = fmt.Sprintf("-> %g TO %g STEP %g", start, endNum, step)
+
+		// -----------------------
+		// NEXT
+		// -----------------------
+		case *parser.NextStmt:
+			frame := i.forStack.Top()
+			if frame == nil {
+				fmt.Println("?NEXT WITHOUT FOR")
+				return
+			}
+
+			val, _ := i.rt.Env.Get(frame.Var)
+			v, _ := val.ToReal()
+			v += frame.Step
+
+			done := (frame.Step > 0 && v > frame.End) ||
+				(frame.Step < 0 && v < frame.End)
+
+			if !done {
+				_ = i.rt.Env.Set(frame.Var, runtime.NewReal(v))
+				nextPC = frame.PCStart + 1
+				sExpr = fmt.Sprintf("-> %g", v)
+			} else {
+				i.forStack.Pop()
+			}
+
+		// -----------------------
+		// GOTO
+		// -----------------------
+		case *parser.GotoStmt:
+			val, err := EvalExpr(s.Expr, i.rt)
+			if err != nil {
+				i.rt.ExecError(err)
+				return
+			}
+
+			f, err2 := val.ToReal()
+			if err2 != nil {
+				fmt.Println("?GOTO TYPE MISMATCH")
+				return
+			}
+
+			line := int(f)
+			sExpr = fmt.Sprintf("%d", line)
+			targetPC, ok := i.lineIndex[line]
+			if !ok {
+				fmt.Printf("?UNDEFINED LINE %d\n", line)
+				return
+			}
+
+			nextPC = targetPC
+
+		// -----------------------
+		// GOSUB
+		// -----------------------
+		case *parser.GosubStmt:
+			val, err := EvalExpr(s.Expr, i.rt)
+			if err != nil {
+				i.rt.ExecError(err)
+				return
+			}
+
+			f, err2 := val.ToReal()
+			if err2 != nil {
+				fmt.Println("?GOSUB TYPE MISMATCH")
+				return
+			}
+
+			line := int(f)
+			sExpr = fmt.Sprintf("%d", line)
+			targetPC, ok := i.lineIndex[line]
+			if !ok {
+				fmt.Printf("?UNDEFINED LINE %d\n", line)
+				return
+			}
+
+			// ⚠️ empiler l’instruction SUIVANTE
+			i.gosubStack.Push(pc + 1)
+
+			nextPC = targetPC
+
+		// -----------------------
+		// RETURN
+		// -----------------------
+		case *parser.ReturnStmt:
+			retPC, ok := i.gosubStack.Pop()
+			if !ok {
+				fmt.Println("?RETURN WITHOUT GOSUB")
+				return
+			}
+			nextPC = retPC
+
+		// -----------------------
+		// IF
+		// -----------------------
+		case *parser.IfStmt:
+			cond, err := EvalExpr(s.Cond, i.rt)
+			if err != nil {
+				i.rt.ExecError(err)
+				return
+			}
+
+			if truthy(cond) {
+				// exécution inline TERMINALE
+				pc2 := pc + 1 // PC logique après le IF
+
+				for _, stmt := range s.Then {
+					pc2 = i.execInline(inst.LineNum, stmt, pc2-1)
+				}
+
+				nextPC = pc2
+				sExpr = "THEN"
+			} else if s.Else != nil {
+				pc2 := pc + 1
+				for _, stmt := range s.Else {
+					pc2 = i.execInline(inst.LineNum, stmt, pc2-1)
+				}
+				nextPC = pc2
+				sExpr = "ELSE"
+			} else {
+				// condition fausse → instruction suivante
+				nextPC = pc + 1
+				sExpr = "ELSE"
+			}
+
+		// -----------------------
+		// IF (compiled jump)
+		// -----------------------
+		case *parser.IfJumpStmt:
+			cond, err := EvalExpr(s.Cond, i.rt)
+			if err != nil {
+				i.rt.ExecError(err)
+				return
+			}
+
+			sExpr = "THEN"
+			if !truthy(cond) {
+				nextPC = s.Target
+				sExpr = "ELSE"
+			}
+
+		}
+
+		logger.Debug(LogTrace(inst, pc, nextPC, sExpr))
+		pc = nextPC
+	}
+}
+
+// =======================
+// Inline execution helper
+// =======================
+
+func (i *tree) execInline(line int, stmt parser.Statement, pc int) int {
+
+	switch s := stmt.(type) {
+
+	case *parser.HomeStmt:
+		i.rt.ExecHome()
+		return pc + 1
+
+	case *parser.GotoStmt:
+		val, err := EvalExpr(s.Expr, i.rt)
+		if err != nil {
+			i.rt.ExecError(err)
+			return pc + 1
+		}
+		f, _ := val.ToReal()
+		target, ok := i.lineIndex[int(f)]
+		if !ok {
+			fmt.Printf("?UNDEFINED LINE %d\n", int(f))
+			return pc + 1
+		}
+		return target
+
+	case *parser.GosubStmt:
+		val, err := EvalExpr(s.Expr, i.rt)
+		if err != nil {
+			i.rt.ExecError(err)
+			return pc + 1
+		}
+
+		f, _ := val.ToReal()
+		line := int(f)
+		targetPC, ok := i.lineIndex[line]
+		if !ok {
+			fmt.Printf("?UNDEFINED LINE %d\n", line)
+			return pc + 1
+		}
+
+		i.gosubStack.Push(pc + 1)
+		return targetPC
+
+	case *parser.ReturnStmt:
+		retPC, ok := i.gosubStack.Pop()
+		if !ok {
+			fmt.Println("?RETURN WITHOUT GOSUB")
+			return pc + 1
+		}
+		return retPC
+
+	case *parser.LetStmt:
+		val, err := EvalExpr(s.Value, i.rt)
+		if err != nil {
+			i.rt.ExecError(err)
+			return pc + 1
+		}
+		if err := i.rt.Env.Set(s.Name, val); err != nil {
+			msg := assignError(runtime.KindOf(s.Name), err)
+			i.rt.ExecError(errors.NewSemantic(line, msg))
+		}
+		return pc + 1
+
+	case *parser.GetStmt:
+		i.execGet(s)
+		return pc + 1
+
+	case *parser.PrintStmt:
+		//i.rt.ExecPrint(s.Exprs[0].(*parser.StringLiteral).Value)
+		//i.rt.ExecPrint("\n")
+		for iExpr, expr := range s.Exprs {
+			val, err := EvalExpr(expr, i.rt)
+			if err != nil {
+				i.rt.ExecError(err)
+				return pc
+			}
+
+			out := printString(val)
+
+			// séparateurs ; et ,
+			if iExpr > 0 {
+				sep := s.Separators[iExpr-1]
+				switch sep {
+				case ',':
+					out = " " + out
+				case ';':
+					// rien
+				}
+			}
+
+			i.rt.ExecPrint(out)
+		}
+		i.rt.ExecPrint("\n")
+
+	}
+
+	return pc + 1
+}
+
+func LogTrace(inst Instruction, pc int, nextPC int, sExpr string) string {
+	return fmt.Sprintf(
+		"Executing line: %d, pc: %d, nextPC: %d - [%s]%s %s",
+		inst.LineNum,
+		pc,
+		nextPC,
+		parser.StmtName(inst.Stmt),
+		parser.StmtArgs(inst.Stmt),
+		sExpr,
+	)
+}
+
+// execScreen exécute les instructions écran / graphique (MODE, INK, PLOT...)
+func (i *tree) execScreen(line int, stmt parser.Statement) *errors.Error {
+	op, exprs, ok := screenArgs(stmt)
+	if !ok {
+		return errors.NewSemantic(line, "UNKNOWN STATEMENT")
+	}
+
+	args, err := i.evalIntArgs(line, exprs...)
+	if err != nil {
+		return err
+	}
+	return i.screen(line, op, args)
+}
+
+// evalIntArgs évalue des arguments numériques arrondis à l'entier le plus
+// proche (comportement Locomotive BASIC)
+func (i *tree) evalIntArgs(line int, exprs ...parser.Expression) ([]int, *errors.Error) {
+	args := make([]int, 0, len(exprs))
+
+	for _, expr := range exprs {
+		val, err := EvalExpr(expr, i.rt)
+		if err != nil {
+			return nil, err
+		}
+
+		n, ok := intArg(val)
+		if !ok {
+			return nil, errors.NewSemantic(line, "TYPE MISMATCH")
+		}
+		args = append(args, n)
+	}
+
+	return args, nil
+}
@@ -0,0 +1,32 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+
+	"basics/testutils"
+)
+
+// sémantique des types Applesoft, identique avec les deux moteurs
+func TestValues_ApplesoftSemantics(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"integer truncation", "10 A% = 1.9 : B% = -1.9 : PRINT A%; \" \"; B%\n", "1 -1\n"},
+		{"integer bounds", "10 A% = 32767 : B% = -32767 : PRINT A% + B%\n", "0\n"},
+		{"integer overflow", "10 A% = 40000\n", "⚠️ ILLEGAL QUANTITY IN 10 ()\n"},
+		{"integer arithmetic is real", "10 A% = 7 : PRINT A% / 2\n", "3.5\n"},
+		{"INT floors", "10 PRINT INT(-2.5); \" \"; INT(-2); \" \"; INT(2.5)\n", "-3 -2 2\n"},
+		{"string concatenation", "10 A$ = \"AB\" : PRINT A$ + \"C\"\n", "ABC\n"},
+		{"string comparison", "10 IF \"ABC\" < \"ABD\" THEN PRINT \"LT\"\n", "LT\n"},
+		{"string plus number", "10 PRINT \"A\" + 1\n", "TYPE MISMATCH"},
+		{"number to string", "10 A$ = 1\n", "TYPE MISMATCH"},
+		{"string to integer", "10 A% = \"1\"\n", "TYPE MISMATCH"},
+	}
+
+	for _, tt := range tests {
+		tree, vm := runBoth(t, tt.src)
+		testutils.Equal(t, tt.name+" (same output)", vm, tree)
+		testutils.True(t, tt.name+": "+vm, strings.Contains(vm, tt.want))
+	}
+}
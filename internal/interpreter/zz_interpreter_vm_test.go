@@ -0,0 +1,278 @@
+package interpreter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"basics/internal/common"
+	"basics/internal/constants"
+	"basics/internal/input"
+	"basics/internal/lexer"
+	"basics/internal/logger"
+	"basics/internal/machines"
+	"basics/internal/parser"
+	"basics/internal/runtime"
+	"basics/testutils"
+)
+
+func parseSource(t testing.TB, src string) *parser.Program {
+	t.Helper()
+
+	prog, errs := parser.New(lexer.Lex(src)).ParseProgram()
+	if len(errs) > 0 {
+		t.Fatalf("parse errors: %v", errs)
+	}
+	return prog
+}
+
+// newTTY retourne un runtime TTY dont la sortie est capturée
+func newTTY(t testing.TB, out io.Writer) *runtime.Runtime {
+	t.Helper()
+
+	rt, err := machines.NewRuntime(constants.BASIC_TTY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt.Input = input.NewTTYInput(strings.NewReader(""), out)
+	rt.Video.SetOutput(out)
+	return rt
+}
+
+// runBoth exécute un programme avec l'évaluateur d'AST puis le bytecode
+func runBoth(t *testing.T, src string) (string, string) {
+	t.Helper()
+
+	var tree, vm bytes.Buffer
+	newTree(newTTY(t, &tree)).run(parseSource(t, src))
+	New(newTTY(t, &vm)).Run(parseSource(t, src))
+	return common.StripANSI(tree.String()), common.StripANSI(vm.String())
+}
+
+func TestCompile_ConstantFolding(t *testing.T) {
+	prog := parseSource(t, "10 PRINT 2 * 3 + INT(1.5) - ABS(-4) : A = B + 1 * 2\n")
+	dis := Compile(prog, runtime.NewEnvironment()).Disassemble()
+
+	testutils.Equal(t, "bytecode", dis, `0000 CONST   3
+0001 PRINT  
+0002 NEWLINE
+0003 LOAD    B
+0004 CONST   2
+0005 INFIX   +
+0006 STORE   A
+`)
+}
+
+func TestCompile_ErrorsAreNotFolded(t *testing.T) {
+	prog := parseSource(t, "10 PRINT 1 / 0\n")
+	dis := Compile(prog, runtime.NewEnvironment()).Disassemble()
+
+	testutils.True(t, "division kept", strings.Contains(dis, "INFIX   /"))
+}
+
+func TestCompile_JumpsAndSlots(t *testing.T) {
+	prog := parseSource(t, `10 GOSUB 40
+20 GOTO 10 + 20
+30 GOTO 99
+40 COUNT = CO + 1 : RETURN
+`)
+	env := runtime.NewEnvironment()
+	env.SetNameSignificance(nil)
+	dis := Compile(prog, env).Disassemble()
+
+	testutils.True(t, "GOSUB resolved to pc", strings.HasPrefix(dis, "0000 GOSUB   0004\n"))
+	testutils.True(t, "folded target resolved", strings.Contains(dis, "0001 JUMP    0002\n"))
+	testutils.True(t, "undefined line kept dynamic", strings.Contains(dis, "0003 GOTO"))
+	count, _ := env.Lookup("COUNT")
+	co, _ := env.Lookup("CO")
+	testutils.True(t, "distinct slots", count != co)
+}
+
+func TestVM_SameOutputAsTree(t *testing.T) {
+	sources := []string{
+		// tabulations, chaînes, entiers
+		`10 A% = 3 : B$ = "X" : PRINT A%, B$; 1.5, "END"
+20 PRINT "A" + "B", 2 ^ 10
+`,
+		// boucles imbriquées et pas négatif
+		`10 FOR I = 1 TO 3 : FOR J = 3 TO 1 STEP -1
+20 PRINT I * J;
+30 NEXT J : PRINT : NEXT I
+`,
+		// IF, GOSUB calculé
+		`10 N = 0
+20 N = N + 1 : IF N < 4 THEN GOSUB 100 + 0 * N : GOTO 20
+30 END
+100 PRINT "N="; N : RETURN
+`,
+		// erreurs d'exécution
+		"10 PRINT 1 / 0\n",
+		"10 A$ = 1\n",
+		"10 PRINT -\"A\"\n",
+		"10 PRINT Z\n",
+		"10 FOR I = 1 TO 2 STEP 0 : NEXT I\n",
+	}
+
+	for _, src := range sources {
+		tree, vm := runBoth(t, src)
+		testutils.Equal(t, src, vm, tree)
+	}
+}
+
+func TestVM_IfElse(t *testing.T) {
+	var out bytes.Buffer
+	New(newTTY(t, &out)).Run(parseSource(t, `10 A = 1
+20 IF A = 2 THEN PRINT "TWO" ELSE PRINT "OTHER" : PRINT "!"
+30 IF A = 1 THEN PRINT "ONE" ELSE PRINT "NOT ONE"
+`))
+
+	testutils.Equal(t, "output", common.StripANSI(out.String()), "OTHER\n!\nONE\n")
+}
+
+//
+// Benchmarks : évaluateur d'AST (tree) contre bytecode (Run)
+//
+
+const benchSource = `10 S = 0
+20 FOR I = 1 TO 2000
+30 A = I * 2 + 1 - 3 * 4
+40 IF A / 3 = INT(A / 3) THEN S = S + 1
+50 GOSUB 100
+60 NEXT I
+70 END
+100 S = S + ABS(-0.5) * (2 + 2) : RETURN
+`
+
+// quietLogs coupe la trace pendant un benchmark
+func quietLogs(b *testing.B) {
+	old := logger.Default()
+	logger.SetDefault(slog.New(logger.NewTextHandler(io.Discard, logger.LevelFatal, "bench")))
+	b.Cleanup(func() { logger.SetDefault(old) })
+}
+
+func BenchmarkRun_Tree(b *testing.B) {
+	quietLogs(b)
+	prog := parseSource(b, benchSource)
+
+	for n := 0; n < b.N; n++ {
+		newTree(newTTY(b, io.Discard)).run(prog)
+	}
+}
+
+func BenchmarkRun_Bytecode(b *testing.B) {
+	quietLogs(b)
+	prog := parseSource(b, benchSource)
+
+	for n := 0; n < b.N; n++ {
+		New(newTTY(b, io.Discard)).Run(prog)
+	}
+}
+
+// BenchmarkExec_Bytecode mesure le VM seul, programme déjà compilé
+func BenchmarkExec_Bytecode(b *testing.B) {
+	quietLogs(b)
+	prog := parseSource(b, benchSource)
+	rt := newTTY(b, io.Discard)
+	code := Compile(prog, rt.Env)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		New(rt).exec(code)
+	}
+}
+
+// les exemples du dépôt produisent la même sortie avec les deux moteurs
+func TestVM_Examples(t *testing.T) {
+	files, _ := filepath.Glob("../../examples/*/*.bas")
+	more, _ := filepath.Glob("../../examples/programs/*/*.bas")
+	files = append(files, more...)
+
+	checked := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		testutils.True(t, "read "+file, err == nil)
+
+		src := string(data)
+		if strings.Contains(src, "INPUT") || strings.Contains(src, "GET") {
+			continue // entrées clavier
+		}
+		if _, errs := parser.New(lexer.Lex(src)).ParseProgram(); len(errs) > 0 {
+			continue
+		}
+
+		tree, vm := runBoth(t, src)
+		testutils.Equal(t, file, vm, tree)
+		checked++
+	}
+
+	testutils.True(t, "examples checked", checked > 30)
+}
+
+func TestResolve(t *testing.T) {
+	prog := parseSource(t, `10 FOR I = 1 TO N% : A$ = B$ + "X" : NEXT I
+20 INPUT C
+`)
+	env := runtime.NewEnvironment()
+	symbols := Resolve(prog, env)
+
+	names := []string{}
+	for _, sym := range symbols {
+		names = append(names, sym.Name+":"+sym.Kind.String())
+	}
+	testutils.Equal(t, "symbols", strings.Join(names, " "), "I:real N%:integer A$:string B$:string C:real")
+	testutils.Equal(t, "typed slots", symbols[3].Slot, 1)
+}
+
+// loopAllocs compte les allocations d'une exécution de la boucle
+func loopAllocs(t *testing.T, count int) float64 {
+	src := fmt.Sprintf("10 A = 0 : FOR I = 1 TO %d : FOR J = 1 TO 3 : A = A + I * J : NEXT J : NEXT I\n", count)
+	prog := parseSource(t, src)
+	rt := newTTY(t, io.Discard)
+	code := Compile(prog, rt.Env)
+
+	return testing.AllocsPerRun(5, func() {
+		New(rt).exec(code)
+	})
+}
+
+func TestVM_ForLoopDoesNotAllocate(t *testing.T) {
+	quiet := logger.Default()
+	logger.SetDefault(slog.New(logger.NewTextHandler(io.Discard, logger.LevelFatal, "test")))
+	defer logger.SetDefault(quiet)
+
+	testutils.Equal(t, "allocations independent of iterations", loopAllocs(t, 10000), loopAllocs(t, 10))
+}
+
+func TestVM_ForReentryReplacesLoop(t *testing.T) {
+	prog := parseSource(t, `5 N = 0
+10 N = N + 1
+20 FOR I = 1 TO 10
+30 IF N < 50 THEN 10
+40 NEXT I
+`)
+	rt := newTTY(t, io.Discard)
+	interp := New(rt)
+	interp.Run(prog)
+
+	testutils.Equal(t, "one loop left", len(interp.forStack.stack), 0)
+	v, _ := rt.Env.Get("I")
+	testutils.Equal(t, "loop ended", v.Num, 10.0)
+}
+
+func BenchmarkForLoop_Bytecode(b *testing.B) {
+	quietLogs(b)
+	prog := parseSource(b, "10 A = 0 : FOR I = 1 TO 10000 : A = A + I : NEXT I\n")
+	rt := newTTY(b, io.Discard)
+	code := Compile(prog, rt.Env)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		New(rt).exec(code)
+	}
+}
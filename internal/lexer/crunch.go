@@ -0,0 +1,115 @@
+package lexer
+
+import (
+	"sort"
+	"unicode"
+
+	"basics/internal/dialect"
+)
+
+// crunchTable retourne les mots réservés du dialecte, du plus long au plus
+// court, afin que la recherche trouve toujours la correspondance la plus
+// longue (ATN avant AT, HGR2 avant HGR).
+func crunchTable(d *dialect.Dialect) []string {
+	table := make([]string, 0, len(d.Keywords)+len(d.Extensions))
+	for kw := range d.AllKeywords() {
+		table = append(table, kw)
+	}
+
+	sort.Slice(table, func(i, j int) bool {
+		if len(table[i]) != len(table[j]) {
+			return len(table[i]) > len(table[j])
+		}
+		return table[i] < table[j]
+	})
+	return table
+}
+
+// matchKeyword cherche un mot-clé commençant à la position pos et retourne
+// le mot-clé et la position qui le suit. Comme sur Apple II, les blancs à
+// l'intérieur d'un mot-clé sont ignorés (G O T O).
+func (l *Lexer) matchKeyword(pos int) (string, int) {
+	for _, kw := range l.crunch {
+		end, ok := l.matchAt(pos, kw)
+		if !ok {
+			continue
+		}
+
+		// AT N : ATN n'est reconnu que si N suit immédiatement AT,
+		// sinon c'est AT suivi de la variable N (HLIN 0,39 AT N)
+		if kw == "ATN" && l.fold(l.input[end-2]) != 'T' {
+			continue
+		}
+
+		// A TO : "AT" suivi de "O" n'est pas le mot-clé AT, mais une
+		// variable A suivie de TO (FORI=ATOB)
+		if kw == "AT" && end < len(l.input) && l.fold(l.input[end]) == 'O' {
+			return "", pos
+		}
+
+		return kw, end
+	}
+	return "", pos
+}
+
+// matchAt compare le mot-clé au source à partir de pos, blancs ignorés
+func (l *Lexer) matchAt(pos int, kw string) (int, bool) {
+	i := pos
+	for j, r := range kw {
+		if j > 0 {
+			for i < len(l.input) && isBlank(l.input[i]) {
+				i++
+			}
+		}
+		if i >= len(l.input) || l.fold(l.input[i]) != r {
+			return pos, false
+		}
+		i++
+	}
+	return i, true
+}
+
+// readCrunchedWord lit un mot-clé ou un identifiant en mode "crunché" :
+// un identifiant s'arrête dès qu'un mot-clé commence (FORI=1TO10 donne
+// FOR, I, =, 1, TO, 10). Retourne le littéral et true s'il s'agit d'un
+// mot-clé.
+func (l *Lexer) readCrunchedWord() (string, bool) {
+	if kw, end := l.matchKeyword(l.position); kw != "" {
+		for l.position < end {
+			l.readChar()
+		}
+		return kw, true
+	}
+
+	start := l.position
+	l.readChar()
+
+	for isLetter(l.ch) || isDigit(l.ch) {
+		if isLetter(l.ch) {
+			if kw, _ := l.matchKeyword(l.position); kw != "" {
+				break
+			}
+		}
+		l.readChar()
+	}
+
+	// suffixe de type éventuel ($, %, ! selon le dialecte)
+	if l.dialect.IsSuffix(l.ch) {
+		l.readChar()
+	}
+
+	return string(l.input[start:l.position]), false
+}
+
+// fold met un caractère en majuscule si le dialecte accepte les mots-clés
+// en minuscules
+func (l *Lexer) fold(ch rune) rune {
+	if l.dialect.Lowercase {
+		return unicode.ToUpper(ch)
+	}
+	return ch
+}
+
+func isBlank(ch rune) bool {
+	return ch == ' ' || ch == '\t'
+}
@@ -1,6 +1,7 @@
 package lexer
 
 import (
+	"basics/internal/dialect"
 	"basics/internal/logger"
 	"basics/internal/token"
 	"fmt"
@@ -9,7 +10,24 @@ import (
 
 // Lex tokenize entièrement la source BASIC et retourne tous les tokens (EOF inclus)
 func Lex(input string) []token.Token {
-	l := New(input)
+	return LexDialect(input, dialect.Default)
+}
+
+// LexDialect tokenize la source selon les règles du dialecte fourni.
+// Le programme s'arrête sur un token invalide.
+func LexDialect(input string, d *dialect.Dialect) []token.Token {
+	tokens, err := Scan(input, d)
+	if err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+		os.Exit(1)
+	}
+	return tokens
+}
+
+// Scan tokenize la source selon le dialecte fourni et retourne une erreur
+// sur le premier token invalide
+func Scan(input string, d *dialect.Dialect) ([]token.Token, error) {
+	l := NewWithDialect(input, d)
 	var tokens []token.Token
 
 	for {
@@ -22,17 +40,15 @@ func Lex(input string) []token.Token {
 			break
 		}
 
-		// We got an invalid token, stop lexing and exit now
+		// We got an invalid token, stop lexing now
 		if tok.Type == token.ILLEGAL {
-			fmt.Printf(
-				"⚠️ Invalid token found in %d (%s)\n",
+			return tokens, fmt.Errorf(
+				"Invalid token found in %d (%s)",
 				tok.Line,
 				tok.Literal,
 			)
-
-			os.Exit(1)
 		}
 	}
 
-	return tokens
+	return tokens, nil
 }
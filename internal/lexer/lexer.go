@@ -1,8 +1,10 @@
 package lexer
 
 import (
+	"strings"
 	"unicode"
 
+	"basics/internal/dialect"
 	"basics/internal/logger"
 	"basics/internal/token"
 )
@@ -17,14 +19,30 @@ type Lexer struct {
 	column int
 
 	expectLineNumber bool
+
+	// Dialecte du BASIC ciblé (mots-clés, suffixes)
+	dialect *dialect.Dialect
+
+	// Mots-clés triés pour le mode "crunché" (nil sinon)
+	crunch []string
 }
 
 func New(input string) *Lexer {
+	return NewWithDialect(input, dialect.Default)
+}
+
+// NewWithDialect crée un lexer pour un dialecte donné
+// (ex: dialect.Locomotive pour l'Amstrad CPC)
+func NewWithDialect(input string, d *dialect.Dialect) *Lexer {
 	logger.Info("Instanciate new lexer")
 	l := &Lexer{
 		input:            []rune(input),
 		line:             1,
 		expectLineNumber: true,
+		dialect:          d,
+	}
+	if d.Crunched {
+		l.crunch = crunchTable(d)
 	}
 	l.readChar()
 	return l
@@ -140,10 +158,23 @@ func (l *Lexer) NextToken() token.Token {
 			return tok
 		}
 		if isLetter(l.ch) {
-			lit := l.readIdentifier()
+			var lit string
+			var keyword bool
+
+			if l.dialect.Crunched {
+				lit, keyword = l.readCrunchedWord()
+			} else {
+				lit = l.readIdentifier()
+				keyword = l.dialect.IsKeyword(lit)
+
+				// mot-clé en minuscules (print), converti comme sur Apple IIe
+				if upper := strings.ToUpper(lit); !keyword && l.dialect.Lowercase && l.dialect.IsKeyword(upper) {
+					lit, keyword = upper, true
+				}
+			}
 			tok.Literal = lit
 
-			if Keywords[lit] {
+			if keyword {
 				tok.Type = token.KEYWORD
 
 				// ✅ REM : ignorer le reste de la ligne
@@ -180,14 +211,34 @@ func (l *Lexer) simpleToken(t token.TokenType, lit string) token.Token {
 }
 
 func (l *Lexer) skipWhitespace() {
-	for l.ch == ' ' || l.ch == '\t' || l.ch == '\r' {
+	for l.ch == ' ' || l.ch == '\t' || l.ch == '\r' || (l.ch == '\n' && l.continued()) {
 		l.readChar()
 	}
 }
 
+// continued indique si la ligne suivante est une ligne de continuation :
+// sans numéro, elle commence (après des blancs) par ':' et prolonge la
+// ligne courante
+func (l *Lexer) continued() bool {
+	for i := l.readPosition; i < len(l.input); i++ {
+		switch l.input[i] {
+		case ' ', '\t', '\r':
+			continue
+		case ':':
+			return true
+		}
+		return false
+	}
+	return false
+}
+
 func (l *Lexer) readIdentifier() string {
 	start := l.position
-	for isLetter(l.ch) || isDigit(l.ch) || l.ch == '$' || l.ch == '%' {
+	for isLetter(l.ch) || isDigit(l.ch) {
+		l.readChar()
+	}
+	// suffixe de type éventuel ($, %, ! selon le dialecte)
+	if l.dialect.IsSuffix(l.ch) {
 		l.readChar()
 	}
 	return string(l.input[start:l.position])
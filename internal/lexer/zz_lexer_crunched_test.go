@@ -0,0 +1,138 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+
+	"basics/internal/dialect"
+	"basics/internal/token"
+	"basics/testutils"
+)
+
+// describeTokens résume les tokens d'une ligne : K:mot-clé, I:identifiant,
+// N:nombre, S:chaîne, L:numéro de ligne, littéral pour les opérateurs
+func describeTokens(tokens []token.Token) string {
+	var parts []string
+
+	for _, tok := range tokens {
+		switch tok.Type {
+		case token.EOL, token.EOF:
+			continue
+		case token.KEYWORD:
+			parts = append(parts, "K:"+tok.Literal)
+		case token.IDENT:
+			parts = append(parts, "I:"+tok.Literal)
+		case token.NUMBER:
+			parts = append(parts, "N:"+tok.Literal)
+		case token.STRING:
+			parts = append(parts, "S:"+tok.Literal)
+		case token.LINENUM:
+			parts = append(parts, "L:"+tok.Literal)
+		default:
+			parts = append(parts, tok.Literal)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestLexDialect_Crunched(t *testing.T) {
+	applesoft := dialect.Applesoft.WithCrunched()
+
+	tests := []struct {
+		name     string
+		dialect  *dialect.Dialect
+		input    string
+		expected string
+	}{
+		{
+			"FOR loop",
+			applesoft,
+			"10FORI=1TO10:PRINTI:NEXT",
+			"L:10 K:FOR I:I = N:1 K:TO N:10 : K:PRINT I:I : K:NEXT",
+		},
+		{
+			"lo-res bars",
+			applesoft,
+			"10GR:FORI=0TO39:COLOR=I/2.5:VLIN0,39ATI:NEXT",
+			"L:10 K:GR : K:FOR I:I = N:0 K:TO N:39 : K:COLOR = I:I / N:2.5 : K:VLIN N:0 , N:39 K:AT I:I : K:NEXT",
+		},
+		{
+			"A TO B is not AT",
+			applesoft,
+			"20FORI=ATOB",
+			"L:20 K:FOR I:I = I:A K:TO I:B",
+		},
+		{
+			"A TO B with spaces",
+			applesoft,
+			"20 FOR I = A TO B",
+			"L:20 K:FOR I:I = I:A K:TO I:B",
+		},
+		{
+			"ATN function",
+			applesoft,
+			"30X=ATN(1)*4",
+			"L:30 I:X = K:ATN ( N:1 ) * N:4",
+		},
+		{
+			"AT N is not ATN",
+			applesoft,
+			"40HLIN0,39AT N",
+			"L:40 K:HLIN N:0 , N:39 K:AT I:N",
+		},
+		{
+			"keyword hidden in variable names",
+			applesoft,
+			`50IFSCORE>HIGHTHENPRINT"NEW RECORD"`,
+			"L:50 K:IF I:SC K:OR I:E > I:HIGH K:THEN K:PRINT S:NEW RECORD",
+		},
+		{
+			"TOTAL starts with TO",
+			applesoft,
+			"60TOTAL=1",
+			"L:60 K:TO I:TAL = N:1",
+		},
+		{
+			"blanks inside keywords",
+			applesoft,
+			"70 G O T O 100",
+			"L:70 K:GOTO N:100",
+		},
+		{
+			"longest keyword wins",
+			applesoft,
+			"80HGR2:HCOLOR=3:HPLOT0,0",
+			"L:80 K:HGR2 : K:HCOLOR = N:3 : K:HPLOT N:0 , N:0",
+		},
+		{
+			"string suffix",
+			applesoft,
+			`90A$=LEFT$(N$,3)`,
+			"L:90 I:A$ = K:LEFT$ ( I:N$ , N:3 )",
+		},
+		{
+			"REM keeps its text",
+			applesoft,
+			"100REMFORI=1TO10",
+			"L:100 K:REM",
+		},
+		{
+			"C64 10 PRINT",
+			dialect.CommodoreV2.WithCrunched(),
+			"10PRINTCHR$(205.5+RND(1));:GOTO10",
+			"L:10 K:PRINT K:CHR$ ( N:205.5 + K:RND ( N:1 ) ) ; : K:GOTO N:10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := describeTokens(LexDialect(tt.input, tt.dialect))
+			testutils.Equal(t, tt.input, got, tt.expected)
+		})
+	}
+}
+
+func TestLexDialect_NotCrunchedByDefault(t *testing.T) {
+	got := describeTokens(LexDialect("10 SCORE=1:FORI=1", dialect.Applesoft))
+	testutils.Equal(t, "words are not split", got, "L:10 I:SCORE = N:1 : I:FORI = N:1")
+}
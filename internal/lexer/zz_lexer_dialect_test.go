@@ -0,0 +1,137 @@
+package lexer
+
+import (
+	"fmt"
+	"testing"
+
+	"basics/internal/dialect"
+	"basics/internal/token"
+	"basics/testutils"
+)
+
+func TestLexDialect_Locomotive(t *testing.T) {
+	input := "10 MODE 1:INK 1,24:PEN 1\n20 LOCATE 5,10:PLOT 0,0:DRAW 639,399\n"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.LINENUM, "10"},
+		{token.KEYWORD, "MODE"},
+		{token.NUMBER, "1"},
+		{token.COLON, ":"},
+		{token.KEYWORD, "INK"},
+		{token.NUMBER, "1"},
+		{token.COMMA, ","},
+		{token.NUMBER, "24"},
+		{token.COLON, ":"},
+		{token.KEYWORD, "PEN"},
+		{token.NUMBER, "1"},
+		{token.EOL, "\n"},
+		{token.LINENUM, "20"},
+		{token.KEYWORD, "LOCATE"},
+		{token.NUMBER, "5"},
+		{token.COMMA, ","},
+		{token.NUMBER, "10"},
+		{token.COLON, ":"},
+		{token.KEYWORD, "PLOT"},
+		{token.NUMBER, "0"},
+		{token.COMMA, ","},
+		{token.NUMBER, "0"},
+		{token.COLON, ":"},
+		{token.KEYWORD, "DRAW"},
+		{token.NUMBER, "639"},
+		{token.COMMA, ","},
+		{token.NUMBER, "399"},
+		{token.EOL, "\n"},
+		{token.EOF, ""},
+	}
+
+	tokens := LexDialect(input, dialect.Locomotive)
+	testutils.Equal(t, "token count", len(tokens), len(tests))
+
+	for i, tt := range tests {
+		msg := fmt.Sprintf("tests[%d] - token type wrong. got=%q, want=%q", i, tokens[i].TypeName(), tt.expectedLiteral)
+		testutils.True(t, msg, tokens[i].Type == tt.expectedType)
+
+		msg = fmt.Sprintf("tests[%d] - literal wrong", i)
+		testutils.Equal(t, msg, tokens[i].Literal, tt.expectedLiteral)
+	}
+}
+
+func TestLexDialect_SameSourceDiffers(t *testing.T) {
+	// HOME, CLS et GET ne sont pas réservés dans tous les dialectes
+	input := "10 HOME:CLS:GET A$\n"
+
+	tests := []struct {
+		name     string
+		dialect  *dialect.Dialect
+		expected []token.TokenType
+	}{
+		{
+			"APPLE", dialect.Applesoft,
+			[]token.TokenType{token.KEYWORD, token.IDENT, token.KEYWORD},
+		},
+		{
+			"C64", dialect.CommodoreV2,
+			[]token.TokenType{token.IDENT, token.IDENT, token.KEYWORD},
+		},
+		{
+			"AMS", dialect.Locomotive,
+			[]token.TokenType{token.IDENT, token.KEYWORD, token.IDENT},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := LexDialect(input, tt.dialect)
+
+			// LINENUM HOME : CLS : GET A$ EOL EOF
+			words := []token.Token{tokens[1], tokens[3], tokens[5]}
+			for i, tok := range words {
+				msg := fmt.Sprintf("%s - %s type", tt.name, tok.Literal)
+				testutils.True(t, msg, tok.Type == tt.expected[i])
+			}
+		})
+	}
+}
+
+func TestLexDialect_Suffixes(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect *dialect.Dialect
+		input   string
+		ident   string
+		next    token.TokenType
+	}{
+		{"APPLE string", dialect.Applesoft, "10 A$=B$", "A$", token.EQUAL},
+		{"APPLE integer", dialect.Applesoft, "10 I%=1", "I%", token.EQUAL},
+		{"APPLE has no ! suffix", dialect.Applesoft, "10 R!=1", "R", token.ILLEGAL},
+		{"AMS real", dialect.Locomotive, "10 R!=1", "R!", token.EQUAL},
+		{"suffix ends identifier", dialect.Locomotive, "10 A$B", "A$", token.IDENT},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewWithDialect(tt.input, tt.dialect)
+			l.NextToken() // LINENUM
+
+			tok := l.NextToken()
+			testutils.True(t, "identifier type", tok.Type == token.IDENT)
+			testutils.Equal(t, "identifier literal", tok.Literal, tt.ident)
+
+			tok = l.NextToken()
+			testutils.True(t, "next token type", tok.Type == tt.next)
+		})
+	}
+}
+
+func TestLexDialect_Extensions(t *testing.T) {
+	for _, d := range []*dialect.Dialect{dialect.Applesoft, dialect.CommodoreV2, dialect.Locomotive} {
+		l := NewWithDialect("10 SLEEP 1", d)
+		l.NextToken() // LINENUM
+		tok := l.NextToken()
+
+		testutils.True(t, d.Name+" - SLEEP is a keyword", tok.Type == token.KEYWORD)
+	}
+}
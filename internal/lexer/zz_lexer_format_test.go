@@ -0,0 +1,43 @@
+package lexer
+
+import (
+	"testing"
+
+	"basics/internal/dialect"
+	"basics/internal/token"
+	"basics/testutils"
+)
+
+func TestLexDialect_Lowercase(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  *dialect.Dialect
+		input    string
+		expected string
+	}{
+		{"case sensitive by default", dialect.Applesoft, "10 print next", "L:10 I:print I:next"},
+		{"lowercase keywords", dialect.Applesoft.WithLowercase(), "10 print X: Next X", "L:10 K:PRINT I:X : K:NEXT I:X"},
+		{"lowercase crunched", dialect.Applesoft.WithCrunched().WithLowercase(), "10fori=1to2", "L:10 K:FOR I:i = N:1 K:TO N:2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutils.Equal(t, tt.input, describeTokens(LexDialect(tt.input, tt.dialect)), tt.expected)
+		})
+	}
+}
+
+func TestLex_ContinuationLine(t *testing.T) {
+	tokens := Lex("10 PRINT 1\n   : PRINT 2\n20 END\n")
+
+	testutils.Equal(t, "one BASIC line", describeTokens(tokens), "L:10 K:PRINT N:1 : K:PRINT N:2 L:20 K:END")
+
+	eols := 0
+	for _, tok := range tokens {
+		if tok.Type == token.EOL {
+			eols++
+		}
+	}
+	testutils.Equal(t, "newline before ':' skipped", eols, 2)
+	testutils.Equal(t, "physical line kept", tokens[3].Line, 2)
+}
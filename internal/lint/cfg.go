@@ -0,0 +1,206 @@
+package lint
+
+import (
+	"basics/internal/parser"
+)
+
+// edgeKind est la nature d'un arc du graphe de flot
+type edgeKind int
+
+const (
+	nextEdge  edgeKind = iota // instruction suivante
+	jumpEdge                  // GOTO, THEN n
+	callEdge                  // GOSUB vers le sous-programme
+	afterCall                 // GOSUB vers l'instruction de retour
+	loopEdge                  // NEXT vers le corps de sa boucle
+)
+
+type edge struct {
+	to   int
+	kind edgeKind
+}
+
+// node est une instruction du programme. Un IF est le nœud de sa
+// condition, ses blocs THEN et ELSE étant des nœuds distincts.
+type node struct {
+	line int
+	stmt parser.Statement // nil : REM ou ligne vide
+	succ []edge
+}
+
+// graph est le graphe de flot de contrôle du programme, au niveau des
+// instructions. Les cibles calculées (GOTO A*10) mènent à toutes les
+// lignes.
+type graph struct {
+	nodes []node
+	entry map[int]int // numéro de ligne → premier nœud
+
+	returnSites []int         // nœuds où reprend l'exécution après un GOSUB
+	undefined   []*jumpTarget // GOTO / GOSUB vers une ligne inexistante
+}
+
+// jumpTarget est un saut vers une ligne constante
+type jumpTarget struct {
+	from   int
+	line   int // numéro de ligne du saut
+	target int // numéro de ligne visé
+	kind   edgeKind
+}
+
+// pending est une sortie de bloc qui rejoint l'instruction suivante
+type pending struct {
+	from int
+	kind edgeKind
+}
+
+func buildGraph(prog *parser.Program) *graph {
+	g := &graph{entry: map[int]int{}}
+
+	var jumps []*jumpTarget
+	var computed []pending
+	fors := map[*parser.ForStmt]int{}
+	var nexts []int
+
+	// ajoute les nœuds d'une liste d'instructions et retourne son premier
+	// nœud (-1 si vide) et les sorties vers l'instruction suivante
+	var block func(line int, stmts []parser.Statement, open []pending) (int, []pending)
+
+	link := func(open []pending, to int) {
+		for _, p := range open {
+			g.nodes[p.from].succ = append(g.nodes[p.from].succ, edge{to, p.kind})
+			if p.kind == afterCall {
+				g.returnSites = append(g.returnSites, to)
+			}
+		}
+	}
+
+	add := func(line int, stmt parser.Statement, open []pending) int {
+		g.nodes = append(g.nodes, node{line: line, stmt: stmt})
+		n := len(g.nodes) - 1
+		link(open, n)
+		return n
+	}
+
+	jump := func(from, line int, expr parser.Expression, kind edgeKind) {
+		if lit, ok := expr.(*parser.NumberLiteral); ok {
+			jumps = append(jumps, &jumpTarget{from, line, int(lit.Value), kind})
+		} else {
+			computed = append(computed, pending{from, kind})
+		}
+	}
+
+	block = func(line int, stmts []parser.Statement, open []pending) (int, []pending) {
+		first := -1
+		for _, stmt := range stmts {
+			n := add(line, stmt, open)
+			if first < 0 {
+				first = n
+			}
+
+			switch s := stmt.(type) {
+			case *parser.GotoStmt:
+				jump(n, line, s.Expr, jumpEdge)
+				open = nil
+
+			case *parser.GosubStmt:
+				jump(n, line, s.Expr, callEdge)
+				open = []pending{{n, afterCall}}
+
+			case *parser.ReturnStmt, *parser.EndStmt:
+				open = nil
+
+			case *parser.ForStmt:
+				fors[s] = n
+				open = []pending{{n, nextEdge}}
+
+			case *parser.NextStmt:
+				nexts = append(nexts, n)
+				open = []pending{{n, nextEdge}}
+
+			case *parser.IfStmt:
+				_, thenOpen := block(line, s.Then, []pending{{n, nextEdge}})
+				elseOpen := []pending{{n, nextEdge}}
+				if s.Else != nil {
+					_, elseOpen = block(line, s.Else, elseOpen)
+				}
+				open = append(thenOpen, elseOpen...)
+
+			default:
+				open = []pending{{n, nextEdge}}
+			}
+		}
+		return first, open
+	}
+
+	var open []pending
+	for _, line := range prog.Lines {
+		stmts := line.Stmts
+		if len(stmts) == 0 {
+			stmts = []parser.Statement{nil}
+		}
+		first, out := block(line.Number, stmts, open)
+		g.entry[line.Number] = first
+		open = out
+	}
+
+	// sauts vers des lignes constantes
+	for _, j := range jumps {
+		to, ok := g.entry[j.target]
+		if !ok {
+			g.undefined = append(g.undefined, j)
+			continue
+		}
+		g.nodes[j.from].succ = append(g.nodes[j.from].succ, edge{to, j.kind})
+	}
+
+	// sauts calculés : toutes les lignes
+	for _, c := range computed {
+		for _, to := range g.entry {
+			g.nodes[c.from].succ = append(g.nodes[c.from].succ, edge{to, c.kind})
+		}
+	}
+
+	// NEXT : retour au corps des boucles FOR correspondantes (instruction
+	// qui suit le FOR)
+	for _, n := range nexts {
+		next := g.nodes[n].stmt.(*parser.NextStmt)
+		for f, from := range fors {
+			if f.Var != next.Var || f.LineNum != next.ForLineNum {
+				continue
+			}
+			for _, e := range g.nodes[from].succ {
+				if e.kind == nextEdge {
+					g.nodes[n].succ = append(g.nodes[n].succ, edge{e.to, loopEdge})
+				}
+			}
+		}
+	}
+
+	return g
+}
+
+// reach retourne les nœuds atteignables depuis le début du programme.
+// Sans calls, un GOSUB ne mène qu'à son instruction de retour :
+// seul le programme principal est parcouru.
+func (g *graph) reach(calls bool) []bool {
+	seen := make([]bool, len(g.nodes))
+	if len(g.nodes) == 0 {
+		return seen
+	}
+
+	stack := []int{0}
+	seen[0] = true
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, e := range g.nodes[n].succ {
+			if (e.kind == callEdge && !calls) || seen[e.to] {
+				continue
+			}
+			seen[e.to] = true
+			stack = append(stack, e.to)
+		}
+	}
+	return seen
+}
@@ -0,0 +1,24 @@
+package lint
+
+import "fmt"
+
+// Severity est la gravité d'un diagnostic
+type Severity string
+
+const (
+	Error   Severity = "error"   // échec certain à l'exécution
+	Warning Severity = "warning" // erreur probable ou code suspect
+)
+
+// Diagnostic est un avertissement produit par l'analyse d'un programme
+type Diagnostic struct {
+	Line     int      `json:"line"`
+	Column   int      `json:"column,omitempty"`
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("⚠️ %s IN %d", d.Message, d.Line)
+}
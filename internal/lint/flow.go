@@ -0,0 +1,230 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"basics/internal/parser"
+)
+
+// Codes des diagnostics de l'analyse de flot
+const (
+	CodeUndefinedLine      = "undefined-line"
+	CodeUnreachable        = "unreachable"
+	CodeReturnWithoutGosub = "return-without-gosub"
+	CodeNextWithoutFor     = "next-without-for"
+	CodeUninitialized      = "uninitialized"
+)
+
+// Flow analyse le graphe de flot du programme : sauts vers des lignes
+// inexistantes, lignes inaccessibles, RETURN atteint sans GOSUB, NEXT
+// atteint hors de sa boucle et variables lues avant d'être affectées.
+func Flow(prog *parser.Program) []Diagnostic {
+	g := buildGraph(prog)
+	var diags []Diagnostic
+
+	for _, j := range g.undefined {
+		kw := "GOTO"
+		if j.kind == callEdge {
+			kw = "GOSUB"
+		}
+		diags = append(diags, Diagnostic{
+			Line: j.line, Severity: Error, Code: CodeUndefinedLine,
+			Message: fmt.Sprintf("%s TO UNDEFINED LINE %d", kw, j.target),
+		})
+	}
+
+	// lignes inaccessibles (hors lignes ne contenant qu'un REM)
+	all := g.reach(true)
+	for _, line := range prog.Lines {
+		if !all[g.entry[line.Number]] && !remOnly(line) {
+			diags = append(diags, Diagnostic{
+				Line: line.Number, Severity: Warning, Code: CodeUnreachable,
+				Message: "UNREACHABLE LINE",
+			})
+		}
+	}
+
+	// RETURN atteint par le programme principal
+	main := g.reach(false)
+	for n, nd := range g.nodes {
+		if _, ok := nd.stmt.(*parser.ReturnStmt); ok && main[n] {
+			diags = append(diags, Diagnostic{
+				Line: nd.line, Severity: Warning, Code: CodeReturnWithoutGosub,
+				Message: "RETURN CAN BE REACHED WITHOUT GOSUB",
+			})
+		}
+	}
+
+	diags = append(diags, g.dataflow()...)
+	return diags
+}
+
+// remOnly indique si la ligne ne contient qu'un commentaire
+func remOnly(line *parser.Line) bool {
+	for _, stmt := range line.Stmts {
+		if stmt != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// state est l'état certain avant une instruction, sur tous les chemins :
+// variables affectées et boucles FOR ouvertes
+type state struct {
+	assigned map[string]bool
+	loops    map[string]bool
+}
+
+func (s *state) clone() *state {
+	c := &state{assigned: make(map[string]bool, len(s.assigned)), loops: make(map[string]bool, len(s.loops))}
+	for k := range s.assigned {
+		c.assigned[k] = true
+	}
+	for k := range s.loops {
+		c.loops[k] = true
+	}
+	return c
+}
+
+// meet garde ce qui est vrai sur les deux chemins ; retourne true si
+// l'état a changé
+func (s *state) meet(o *state) bool {
+	changed := false
+	for k := range s.assigned {
+		if !o.assigned[k] {
+			delete(s.assigned, k)
+			changed = true
+		}
+	}
+	for k := range s.loops {
+		if !o.loops[k] {
+			delete(s.loops, k)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// transfer retourne l'état après l'instruction n, le long d'un arc
+func (g *graph) transfer(n int, in *state, kind edgeKind) *state {
+	out := in.clone()
+
+	switch s := g.nodes[n].stmt.(type) {
+	case *parser.LetStmt:
+		out.assigned[s.Name] = true
+	case *parser.ForStmt:
+		out.assigned[s.Var] = true
+		out.loops[s.Var] = true
+	case *parser.InputStmt:
+		for _, v := range s.Vars {
+			out.assigned[v.Name] = true
+		}
+	case *parser.GetStmt:
+		out.assigned[s.Var.Name] = true
+	case *parser.NextStmt:
+		if kind != loopEdge {
+			delete(out.loops, s.Var) // boucle terminée
+		}
+	}
+	return out
+}
+
+// dataflow propage l'état certain dans le graphe. Un RETURN rejoint toutes
+// les instructions de retour des GOSUB ; l'arc direct d'un GOSUB vers son
+// retour est ignoré, l'état passant par le sous-programme (sauf GOSUB vers
+// une ligne inexistante).
+func (g *graph) dataflow() []Diagnostic {
+	if len(g.nodes) == 0 {
+		return nil
+	}
+
+	in := make([]*state, len(g.nodes))
+	in[0] = &state{assigned: map[string]bool{}, loops: map[string]bool{}}
+	work := []int{0}
+
+	propagate := func(to int, out *state) {
+		if in[to] == nil {
+			in[to] = out
+			work = append(work, to)
+		} else if in[to].meet(out) {
+			work = append(work, to)
+		}
+	}
+
+	for len(work) > 0 {
+		n := work[len(work)-1]
+		work = work[:len(work)-1]
+
+		if _, ok := g.nodes[n].stmt.(*parser.ReturnStmt); ok {
+			for _, to := range g.returnSites {
+				propagate(to, in[n].clone())
+			}
+			continue
+		}
+		calls := false
+		for _, e := range g.nodes[n].succ {
+			calls = calls || e.kind == callEdge
+		}
+		for _, e := range g.nodes[n].succ {
+			if e.kind != afterCall || !calls {
+				propagate(e.to, g.transfer(n, in[n], e.kind))
+			}
+		}
+	}
+
+	var diags []Diagnostic
+	reported := map[string]bool{}
+
+	for n, nd := range g.nodes {
+		if in[n] == nil {
+			continue // jamais atteint
+		}
+
+		for _, name := range reads(nd.stmt) {
+			key := fmt.Sprintf("%d:%s", nd.line, name)
+			if in[n].assigned[name] || reported[key] {
+				continue
+			}
+			reported[key] = true
+			diags = append(diags, Diagnostic{
+				Line: nd.line, Severity: Warning, Code: CodeUninitialized,
+				Message: fmt.Sprintf("VARIABLE %s MAY BE USED BEFORE ASSIGNMENT", name),
+			})
+		}
+
+		if next, ok := nd.stmt.(*parser.NextStmt); ok && !in[n].loops[next.Var] {
+			diags = append(diags, Diagnostic{
+				Line: nd.line, Severity: Warning, Code: CodeNextWithoutFor,
+				Message: fmt.Sprintf("NEXT %s CAN BE REACHED WITHOUT FOR", next.Var),
+			})
+		}
+	}
+	return diags
+}
+
+// reads retourne les variables lues par une instruction (condition
+// seulement pour un IF), triées
+func reads(stmt parser.Statement) []string {
+	var root any = stmt
+
+	switch s := stmt.(type) {
+	case *parser.IfStmt:
+		root = s.Cond
+	case *parser.NextStmt, *parser.InputStmt, *parser.GetStmt, nil:
+		return nil
+	}
+
+	seen := map[string]bool{}
+	var names []string
+	parser.Inspect(root, func(node any) bool {
+		if id, ok := node.(*parser.Identifier); ok && !seen[id.Name] {
+			seen[id.Name] = true
+			names = append(names, id.Name)
+		}
+		return true
+	})
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,22 @@
+package lint
+
+import (
+	"sort"
+
+	"basics/internal/dialect"
+	"basics/internal/parser"
+)
+
+// Check lance toutes les analyses et retourne les diagnostics triés par
+// ligne
+func Check(prog *parser.Program, d *dialect.Dialect) []Diagnostic {
+	var diags []Diagnostic
+	diags = append(diags, NameCollisions(prog, d)...)
+	diags = append(diags, Flow(prog)...)
+	diags = append(diags, Types(prog)...)
+
+	sort.SliceStable(diags, func(i, j int) bool {
+		return diags[i].Line < diags[j].Line
+	})
+	return diags
+}
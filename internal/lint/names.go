@@ -0,0 +1,67 @@
+package lint
+
+import (
+	"fmt"
+
+	"basics/internal/dialect"
+	"basics/internal/parser"
+)
+
+// CodeNameCollision signale deux orthographes d'une même variable
+const CodeNameCollision = "name-collision"
+
+// NameCollisions signale les noms de variables distincts dans le source
+// mais identiques pour le dialecte (Applesoft : COUNT et CO). Un seul
+// avertissement est produit par orthographe, à sa première apparition.
+func NameCollisions(prog *parser.Program, d *dialect.Dialect) []Diagnostic {
+	var diags []Diagnostic
+
+	if d.NameSignificance <= 0 {
+		return diags
+	}
+
+	// première orthographe rencontrée pour chaque nom significatif
+	first := make(map[string]string)
+	reported := make(map[string]bool)
+
+	check := func(line int, name string) {
+		key := d.SignificantName(name)
+
+		prev, ok := first[key]
+		if !ok {
+			first[key] = name
+			return
+		}
+		if prev == name || reported[name] {
+			return
+		}
+
+		reported[name] = true
+		diags = append(diags, Diagnostic{
+			Line:     line,
+			Severity: Warning,
+			Code:     CodeNameCollision,
+			Message: fmt.Sprintf(
+				"VARIABLE %s IS THE SAME AS %s (%s)", name, prev, key,
+			),
+		})
+	}
+
+	for _, line := range prog.Lines {
+		parser.Inspect(line, func(node any) bool {
+			switch n := node.(type) {
+			case *parser.LetStmt:
+				check(line.Number, n.Name)
+			case *parser.ForStmt:
+				check(line.Number, n.Var)
+			case *parser.NextStmt:
+				check(line.Number, n.Var)
+			case *parser.Identifier:
+				check(line.Number, n.Name)
+			}
+			return true
+		})
+	}
+
+	return diags
+}
@@ -0,0 +1,154 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"basics/internal/parser"
+	"basics/internal/unparse"
+)
+
+// CodeTypeMismatch signale un mélange de chaînes et de nombres
+const CodeTypeMismatch = "type-mismatch"
+
+// valueType est le type statique d'une expression
+type valueType int
+
+const (
+	unknownType valueType = iota
+	numberType
+	stringType
+)
+
+func (t valueType) String() string {
+	if t == stringType {
+		return "STRING"
+	}
+	return "NUMBER"
+}
+
+// nameType retourne le type d'une variable d'après son suffixe
+func nameType(name string) valueType {
+	if strings.HasSuffix(name, "$") {
+		return stringType
+	}
+	return numberType
+}
+
+// Types signale les TYPE MISMATCH visibles sans exécuter le programme :
+// A% = "X", "A" + 1, FOR I = "A" TO 10, ABS("X")
+func Types(prog *parser.Program) []Diagnostic {
+	var diags []Diagnostic
+
+	for _, line := range prog.Lines {
+		report := func(msg string) {
+			diags = append(diags, Diagnostic{
+				Line: line.Number, Severity: Error, Code: CodeTypeMismatch,
+				Message: "TYPE MISMATCH: " + msg,
+			})
+		}
+
+		// type d'une expression ; les erreurs internes sont signalées
+		var typeOf func(expr parser.Expression) valueType
+
+		// fonction numérique INT, ABS, SGN
+		function := func(call, arg parser.Expression) valueType {
+			if typeOf(arg) == stringType {
+				report(unparse.Expression(call))
+				return unknownType
+			}
+			return numberType
+		}
+		typeOf = func(expr parser.Expression) valueType {
+			switch e := expr.(type) {
+			case *parser.NumberLiteral:
+				return numberType
+			case *parser.StringLiteral:
+				return stringType
+			case *parser.Identifier:
+				return nameType(e.Name)
+
+			case *parser.PrefixExpr:
+				if typeOf(e.Right) == stringType {
+					report(fmt.Sprintf("%s APPLIED TO A STRING", e.Op))
+					return unknownType
+				}
+				return numberType
+
+			case *parser.InfixExpr:
+				l, r := typeOf(e.Left), typeOf(e.Right)
+				if l == unknownType || r == unknownType {
+					return unknownType
+				}
+				if l != r {
+					report(unparse.Expression(e))
+					return unknownType
+				}
+				if prec := parser.Precedence(e.Op); prec == parser.EQUALS || prec == parser.LESSGREATER {
+					return numberType // comparaison
+				}
+				if l == stringType && e.Op != "+" {
+					report(unparse.Expression(e))
+					return unknownType
+				}
+				return l
+
+			case *parser.IntExpr:
+				return function(e, e.Expr)
+			case *parser.AbsExpr:
+				return function(e, e.Expr)
+			case *parser.SgnExpr:
+				return function(e, e.Expr)
+			}
+			return unknownType
+		}
+
+		numeric := func(what string, exprs ...parser.Expression) {
+			for _, expr := range exprs {
+				if expr != nil && typeOf(expr) == stringType {
+					report(fmt.Sprintf("%s EXPECTS A NUMBER", what))
+				}
+			}
+		}
+
+		parser.Inspect(line, func(node any) bool {
+			switch s := node.(type) {
+			case *parser.LetStmt:
+				if t := typeOf(s.Value); t != unknownType && t != nameType(s.Name) {
+					report(fmt.Sprintf("%s ASSIGNED TO %s", t, s.Name))
+				}
+			case *parser.ForStmt:
+				if nameType(s.Var) == stringType {
+					report("FOR " + s.Var)
+				}
+				numeric("FOR", s.Start, s.End, s.Step)
+			case *parser.HTabStmt:
+				numeric("HTAB", s.Expr)
+			case *parser.VTabStmt:
+				numeric("VTAB", s.Expr)
+			case *parser.GotoStmt:
+				numeric("GOTO", s.Expr)
+			case *parser.GosubStmt:
+				numeric("GOSUB", s.Expr)
+			case *parser.PrintStmt:
+				for _, expr := range s.Exprs {
+					typeOf(expr)
+				}
+			case *parser.IfStmt:
+				typeOf(s.Cond)
+			default:
+				return true
+			}
+			return !isExprNode(node)
+		})
+	}
+
+	return diags
+}
+
+// isExprNode indique si le nœud est une expression (déjà vérifiée par
+// l'instruction qui la contient)
+func isExprNode(node any) bool {
+	_, ok := node.(parser.Expression)
+	return ok
+}
@@ -0,0 +1,114 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"basics/internal/dialect"
+	"basics/testutils"
+)
+
+// summary résume les diagnostics : "ligne code" séparés par des virgules
+func summary(diags []Diagnostic) string {
+	parts := make([]string, 0, len(diags))
+	for _, d := range diags {
+		parts = append(parts, fmt.Sprintf("%d %s", d.Line, d.Code))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func TestFlow(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			"clean program",
+			`10 REM SUM
+20 S = 0: FOR I = 1 TO 3: GOSUB 100: NEXT I
+30 IF S > 5 THEN PRINT S ELSE GOTO 50
+40 END
+50 PRINT "SMALL": END
+100 S = S + I: RETURN
+`,
+			"",
+		},
+		{
+			"undefined lines",
+			"10 GOSUB 99\n20 IF A = 1 THEN 98\n30 END\n",
+			"10 undefined-line, 20 undefined-line, 20 uninitialized",
+		},
+		{
+			"unreachable lines",
+			"10 GOTO 40\n20 PRINT \"NEVER\"\n30 REM ONLY A COMMENT\n40 END\n50 PRINT \"AFTER END\"\n",
+			"20 unreachable, 50 unreachable",
+		},
+		{
+			"missing END before subroutine",
+			"10 GOSUB 100\n100 PRINT \"SUB\": RETURN\n",
+			"100 return-without-gosub",
+		},
+		{
+			"NEXT reached outside its loop",
+			"10 FOR I = 1 TO 2\n20 NEXT I\n30 IF I < 5 THEN 20\n",
+			"20 next-without-for",
+		},
+		{
+			"assigned on one branch only",
+			"10 INPUT A\n20 IF A THEN B = 1\n30 PRINT A + B\n",
+			"30 uninitialized",
+		},
+		{
+			"assigned by every subroutine call",
+			"10 GOSUB 100: PRINT R\n20 END\n100 R = 1: RETURN\n",
+			"",
+		},
+		{
+			"computed GOTO reaches every line",
+			"10 A = 30: GOTO A\n20 PRINT \"TWENTY\"\n30 END\n",
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := Check(parse(t, tt.src, dialect.Applesoft), dialect.Applesoft)
+			testutils.Equal(t, tt.name, summary(diags), tt.expected)
+		})
+	}
+}
+
+func TestFlow_Messages(t *testing.T) {
+	diags := Flow(parse(t, "10 GOSUB 99\n20 PRINT X\n", dialect.Applesoft))
+
+	testutils.Equal(t, "count", len(diags), 2)
+	testutils.Equal(t, "undefined", diags[0].Message, "GOSUB TO UNDEFINED LINE 99")
+	testutils.Equal(t, "undefined severity", diags[0].Severity, Error)
+	testutils.Equal(t, "uninitialized", diags[1].Message, "VARIABLE X MAY BE USED BEFORE ASSIGNMENT")
+	testutils.Equal(t, "uninitialized severity", diags[1].Severity, Warning)
+}
+
+func TestTypes(t *testing.T) {
+	src := `10 A% = "X": B$ = 1 + 2
+20 PRINT "A" + 1; -"B"; ABS(C$)
+30 FOR I = 1 TO "10": NEXT I
+40 IF A$ < "B" THEN HTAB 1
+50 D$ = "A" + "B": E = "A" = "B"
+60 F$ = "A" - "B"
+`
+	diags := Types(parse(t, src, dialect.Applesoft))
+
+	var messages []string
+	for _, d := range diags {
+		messages = append(messages, fmt.Sprintf("%d %s", d.Line, d.Message))
+	}
+	testutils.Equal(t, "type mismatches", strings.Join(messages, "\n"), `10 TYPE MISMATCH: STRING ASSIGNED TO A%
+10 TYPE MISMATCH: NUMBER ASSIGNED TO B$
+20 TYPE MISMATCH: "A"+1
+20 TYPE MISMATCH: - APPLIED TO A STRING
+20 TYPE MISMATCH: ABS(C$)
+30 TYPE MISMATCH: FOR EXPECTS A NUMBER
+60 TYPE MISMATCH: "A"-"B"`)
+}
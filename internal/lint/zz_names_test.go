@@ -0,0 +1,46 @@
+package lint
+
+import (
+	"testing"
+
+	"basics/internal/dialect"
+	"basics/internal/lexer"
+	"basics/internal/parser"
+	"basics/testutils"
+)
+
+func parse(t *testing.T, src string, d *dialect.Dialect) *parser.Program {
+	t.Helper()
+
+	prog, errs := parser.NewWithDialect(lexer.LexDialect(src, d), d).ParseProgram()
+	testutils.Equal(t, "no parser errors", len(errs), 0)
+	return prog
+}
+
+func TestNameCollisions(t *testing.T) {
+	src := `
+10 COUNT = 1
+20 PRINT CO
+30 FOR COLUMN = 1 TO 3
+40 NEXT COLUMN
+50 SC$ = "A":SCORE = 10:PRINT SCORE
+`
+	diags := NameCollisions(parse(t, src, dialect.Applesoft), dialect.Applesoft)
+
+	testutils.Equal(t, "diagnostic count", len(diags), 2)
+
+	testutils.Equal(t, "first line", diags[0].Line, 20)
+	testutils.Equal(t, "first code", diags[0].Code, CodeNameCollision)
+	testutils.Equal(t, "first message", diags[0].Message, "VARIABLE CO IS THE SAME AS COUNT (CO)")
+
+	testutils.Equal(t, "second line", diags[1].Line, 30)
+	testutils.Equal(t, "second message", diags[1].Message, "VARIABLE COLUMN IS THE SAME AS COUNT (CO)")
+	testutils.Equal(t, "string", diags[1].String(), "⚠️ VARIABLE COLUMN IS THE SAME AS COUNT (CO) IN 30")
+}
+
+func TestNameCollisions_NoSignificance(t *testing.T) {
+	src := "10 COUNT = 1\n20 PRINT CO\n"
+	diags := NameCollisions(parse(t, src, dialect.Locomotive), dialect.Locomotive)
+
+	testutils.Equal(t, "no diagnostics for Locomotive BASIC", len(diags), 0)
+}
@@ -5,18 +5,23 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"strconv"
+	"strings"
 	"sync"
 )
 
 type TextHandler struct {
-	mu      sync.Mutex
+	mu      *sync.Mutex
 	w       io.Writer
 	level   slog.Level
 	appName string
+	attrs   string // attributs déjà formatés (WithAttrs)
+	group   string // préfixe des clés (WithGroup)
 }
 
 func NewTextHandler(w io.Writer, level slog.Level, appName string) *TextHandler {
 	return &TextHandler{
+		mu:      &sync.Mutex{},
 		w:       w,
 		level:   level,
 		appName: appName,
@@ -35,28 +40,87 @@ func (h *TextHandler) Enabled(_ context.Context, lvl slog.Level) bool {
 }
 
 func (h *TextHandler) Handle(_ context.Context, r slog.Record) error {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	timestamp := r.Time.Format("2006-01-02 15:04:05,000")
 	level := levelToString(r.Level)
 
-	line := fmt.Sprintf(
-		"%s [%s] %s: %s\n",
-		timestamp,
-		level,
-		h.appName,
-		r.Message,
-	)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s [%s] %s: %s", timestamp, level, h.appName, r.Message)
+	sb.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		appendAttr(&sb, h.group, a)
+		return true
+	})
+	sb.WriteByte('\n')
 
-	_, err := h.w.Write([]byte(line))
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, sb.String())
 	return err
 }
 
-func (h *TextHandler) WithAttrs(_ []slog.Attr) slog.Handler {
-	return h
+// WithAttrs retourne un handler qui ajoute les attributs à chaque ligne
+func (h *TextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	var sb strings.Builder
+	sb.WriteString(h.attrs)
+	for _, a := range attrs {
+		appendAttr(&sb, h.group, a)
+	}
+	h2 := *h
+	h2.attrs = sb.String()
+	return &h2
+}
+
+// WithGroup retourne un handler qui préfixe les clés suivantes par le nom
+// du groupe (groupe.clé=valeur)
+func (h *TextHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// appendAttr écrit un attribut sous la forme " clé=valeur" ; les valeurs
+// qui contiennent des espaces sont entre guillemets
+func appendAttr(sb *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, g := range a.Value.Group() {
+			appendAttr(sb, prefix, g)
+		}
+		return
+	}
+
+	value := a.Value.String()
+	if value == "" || strings.ContainsAny(value, " =\"\t\n") {
+		value = strconv.Quote(value)
+	}
+	sb.WriteString(" " + prefix + a.Key + "=" + value)
 }
 
-func (h *TextHandler) WithGroup(_ string) slog.Handler {
-	return h
+// NewJSONHandler crée un handler qui écrit un objet JSON par ligne, avec
+// les niveaux de l'application et son nom (app)
+func NewJSONHandler(w io.Writer, level slog.Level, appName string) slog.Handler {
+	h := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.LevelKey {
+				if lvl, ok := a.Value.Any().(slog.Level); ok {
+					a.Value = slog.StringValue(levelToString(lvl))
+				}
+			}
+			return a
+		},
+	})
+	return h.WithAttrs([]slog.Attr{slog.String("app", appName)})
 }
@@ -8,21 +8,27 @@ import (
 // Helpers pour tous les niveaux personnalisés
 
 func Info(msg string, args ...any) {
-	slog.Log(context.Background(), LevelInfo, msg, args...)
+	Default().Log(context.Background(), LevelInfo, msg, args...)
 }
 
 func Debug(msg string, args ...any) {
-	slog.Log(context.Background(), LevelDebug, msg, args...)
+	Default().Log(context.Background(), LevelDebug, msg, args...)
 }
 
 func Warning(msg string, args ...any) {
-	slog.Log(context.Background(), LevelWarning, msg, args...)
+	Default().Log(context.Background(), LevelWarning, msg, args...)
 }
 
 func Critical(msg string, args ...any) {
-	slog.Log(context.Background(), LevelCritical, msg, args...)
+	Default().Log(context.Background(), LevelCritical, msg, args...)
+}
+
+// Enabled indique si un niveau est journalisé, pour éviter de construire
+// des messages coûteux inutilement
+func Enabled(level slog.Level) bool {
+	return Default().Enabled(context.Background(), level)
 }
 
 func Fatal(msg string, args ...any) {
-	slog.Log(context.Background(), LevelFatal, msg, args...)
+	Default().Log(context.Background(), LevelFatal, msg, args...)
 }
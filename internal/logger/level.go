@@ -1,6 +1,10 @@
 package logger
 
-import "log/slog"
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
 
 // Niveaux personnalisés
 const (
@@ -27,3 +31,14 @@ func levelToString(lvl slog.Level) string {
 		return "UNKNOWN"
 	}
 }
+
+// ParseLevel retourne le niveau d'un nom (info, debug, warning, critical,
+// fatal), sans tenir compte de la casse
+func ParseLevel(name string) (slog.Level, error) {
+	for _, lvl := range []slog.Level{LevelInfo, LevelDebug, LevelWarning, LevelCritical, LevelFatal} {
+		if strings.EqualFold(name, levelToString(lvl)) {
+			return lvl, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q (info, debug, warning, critical, fatal)", name)
+}
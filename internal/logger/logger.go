@@ -1,10 +1,33 @@
 package logger
 
 import (
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"sync/atomic"
 )
 
+// std est le logger des helpers. Il ne journalise rien tant que
+// l'application ne l'a pas configuré (Setup, InitLogger, SetDefault) : les
+// paquets utilisés comme bibliothèque restent silencieux.
+var std atomic.Pointer[slog.Logger]
+
+func init() {
+	std.Store(slog.New(slog.DiscardHandler))
+}
+
+// Default retourne le logger des helpers
+func Default() *slog.Logger {
+	return std.Load()
+}
+
+// SetDefault remplace le logger des helpers et celui de slog
+func SetDefault(log *slog.Logger) {
+	std.Store(log)
+	slog.SetDefault(log)
+}
+
 func NewFileLogger(path, appName string, level slog.Level) (*slog.Logger, func() error, error) {
 	file, err := os.OpenFile(
 		path,
@@ -35,6 +58,38 @@ func InitLogger(path, appName string, level slog.Level) (func() error, error) {
 	}
 
 	// Définit ce logger comme logger par défaut
-	slog.SetDefault(log)
+	SetDefault(log)
+	return closeFn, nil
+}
+
+// Setup initialise le logger de l'application selon les options de la
+// ligne de commande.
+// path : fichier de log, "-" pour la sortie d'erreur, "" ou "none" pour
+// ne rien journaliser
+// format : "text" ou "json"
+func Setup(path, format, appName string, level slog.Level) (func() error, error) {
+	if format != "text" && format != "json" {
+		return nil, fmt.Errorf("unknown log format %q (text, json)", format)
+	}
+	if path == "" || path == "none" {
+		SetDefault(slog.New(slog.DiscardHandler))
+		return func() error { return nil }, nil
+	}
+
+	var w io.Writer = os.Stderr
+	closeFn := func() error { return nil }
+	if path != "-" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		w, closeFn = file, file.Close
+	}
+
+	var handler slog.Handler = NewTextHandler(w, level, appName)
+	if format == "json" {
+		handler = NewJSONHandler(w, level, appName)
+	}
+	SetDefault(slog.New(handler))
 	return closeFn, nil
 }
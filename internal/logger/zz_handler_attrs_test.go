@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"basics/testutils"
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestTextHandler_Attrs(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(NewTextHandler(&buf, LevelInfo, "basics"))
+
+	log.With("line", 20).WithGroup("vm").Log(context.Background(), LevelDebug, "Executing statement",
+		"pc", 3, "stmt", "PRINT", slog.Group("args", "value", "HELLO WORLD"))
+
+	got := strings.TrimSuffix(buf.String(), "\n")
+	want := `[DEBUG] basics: Executing statement line=20 vm.pc=3 vm.stmt=PRINT vm.args.value="HELLO WORLD"`
+	testutils.True(t, "attributes: "+got, strings.HasSuffix(got, want))
+
+	// le handler d'origine n'est pas modifié
+	buf.Reset()
+	log.Info("done")
+	testutils.True(t, "no attributes: "+buf.String(), strings.HasSuffix(buf.String(), "basics: done\n"))
+}
+
+func TestJSONHandler(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(NewJSONHandler(&buf, LevelInfo, "basics"))
+
+	log.Log(context.Background(), LevelWarning, "Loaded", "line", 10)
+
+	got := buf.String()
+	testutils.True(t, "level: "+got, strings.Contains(got, `"level":"WARNING","msg":"Loaded","app":"basics","line":10}`))
+}
@@ -62,7 +62,7 @@ func TestHelpers_LogOutput(t *testing.T) {
 			log := slog.New(handler)
 
 			// Remplacer le logger par défaut pour le test
-			slog.SetDefault(log)
+			SetDefault(log)
 
 			// Appel du helper
 			tt.logFunc(tt.message)
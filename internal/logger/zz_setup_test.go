@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"basics/testutils"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	lvl, err := ParseLevel("warning")
+	testutils.True(t, "warning", err == nil)
+	testutils.Equal(t, "warning", lvl, LevelWarning)
+
+	lvl, err = ParseLevel("DEBUG")
+	testutils.True(t, "DEBUG", err == nil)
+	testutils.Equal(t, "DEBUG", lvl, LevelDebug)
+
+	_, err = ParseLevel("verbose")
+	testutils.True(t, "unknown level", err != nil)
+}
+
+func TestSetup(t *testing.T) {
+	original := Default()
+	defer SetDefault(original)
+
+	closeFn, err := Setup("none", "text", "test_app", LevelInfo)
+	testutils.True(t, "none", err == nil && closeFn() == nil)
+	testutils.False(t, "none logs nothing", Default().Enabled(context.Background(), LevelFatal))
+
+	_, err = Setup("-", "xml", "test_app", LevelInfo)
+	testutils.True(t, "unknown format", err != nil)
+
+	path := filepath.Join(t.TempDir(), "test.log")
+	closeFn, err = Setup(path, "json", "test_app", LevelWarning)
+	testutils.True(t, "json file", err == nil)
+	Info("ignored")
+	Critical("written", "line", 10)
+	testutils.True(t, "close", closeFn() == nil)
+
+	data, err := os.ReadFile(path)
+	testutils.True(t, "read", err == nil)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	testutils.Equal(t, "lines", len(lines), 1)
+	testutils.True(t, "json line: "+lines[0], strings.HasSuffix(lines[0], `"level":"CRITICAL","msg":"written","app":"test_app","line":10}`))
+}
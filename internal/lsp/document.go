@@ -0,0 +1,323 @@
+package lsp
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"basics/internal/dialect"
+	"basics/internal/errors"
+	"basics/internal/lexer"
+	"basics/internal/lint"
+	"basics/internal/parser"
+	"basics/internal/token"
+	"basics/internal/unparse"
+)
+
+// document est un fichier ouvert dans l'éditeur, analysé à chaque
+// modification
+type document struct {
+	uri     string
+	lines   []string
+	dialect *dialect.Dialect
+
+	tokens  []token.Token
+	prog    *parser.Program
+	errs    []*errors.Error
+	illegal *token.Token // token invalide arrêtant le lexer
+
+	numbers map[int]int // numéro de ligne BASIC → indice du token LINENUM
+}
+
+func newDocument(uri, text string, d *dialect.Dialect) *document {
+	doc := &document{
+		uri:     uri,
+		lines:   strings.Split(text, "\n"),
+		dialect: d,
+		numbers: map[int]int{},
+	}
+
+	tokens, err := lexer.Scan(text, d)
+	if err != nil {
+		// le programme est analysé jusqu'au token invalide
+		bad := tokens[len(tokens)-1]
+		doc.illegal = &bad
+		tokens = append(tokens[:len(tokens)-1], token.Token{Type: token.EOF, Line: bad.Line, Column: bad.Column})
+	}
+	doc.tokens = tokens
+	doc.prog, doc.errs = parser.NewWithDialect(tokens, d).ParseProgram()
+
+	for i, tok := range tokens {
+		if tok.Type == token.LINENUM {
+			n, _ := strconv.Atoi(tok.Literal)
+			if _, seen := doc.numbers[n]; !seen {
+				doc.numbers[n] = i
+			}
+		}
+	}
+	return doc
+}
+
+// tokenRange retourne la plage d'un token
+func tokenRange(tok token.Token) Range {
+	width := len([]rune(tok.Literal))
+	if tok.Type == token.STRING {
+		width += 2 // guillemets
+	}
+	start := Position{Line: tok.Line - 1, Character: max(tok.Column-1, 0)}
+	return Range{Start: start, End: Position{Line: start.Line, Character: start.Character + width}}
+}
+
+// lineRange retourne la plage complète d'une ligne du fichier (0 = première)
+func (doc *document) lineRange(line int) Range {
+	width := 0
+	if line >= 0 && line < len(doc.lines) {
+		width = len([]rune(strings.TrimRight(doc.lines[line], "\r")))
+	}
+	return Range{Start: Position{Line: line}, End: Position{Line: line, Character: width}}
+}
+
+// basicLineRange retourne la plage de la ligne du fichier qui porte le
+// numéro BASIC n
+func (doc *document) basicLineRange(n int) Range {
+	if i, ok := doc.numbers[n]; ok {
+		return doc.lineRange(doc.tokens[i].Line - 1)
+	}
+	return doc.lineRange(0)
+}
+
+// diagnostics retourne les erreurs du lexer et du parser puis, si le
+// programme est correct, les diagnostics de l'analyse statique
+func (doc *document) diagnostics() []Diagnostic {
+	diags := []Diagnostic{}
+
+	if doc.illegal != nil {
+		diags = append(diags, Diagnostic{
+			Range: tokenRange(*doc.illegal), Severity: SeverityError, Source: "basics",
+			Message: fmt.Sprintf("INVALID TOKEN (%s)", doc.illegal.Literal),
+		})
+	}
+
+	for _, e := range doc.errs {
+		var r Range
+		if e.Kind == errors.Semantic || e.Msg == "MISSING NEXT" {
+			// ligne BASIC et non ligne du fichier
+			r = doc.basicLineRange(e.Line)
+		} else {
+			r = tokenRange(token.Token{Literal: e.Token, Line: e.Line, Column: e.Column})
+		}
+		msg := e.Msg
+		if e.Hint != "" {
+			msg += " (" + e.Hint + ")"
+		}
+		diags = append(diags, Diagnostic{Range: r, Severity: SeverityError, Source: "basics", Message: msg})
+	}
+
+	if len(diags) > 0 {
+		return diags
+	}
+
+	for _, d := range lint.Check(doc.prog, doc.dialect) {
+		severity := SeverityWarning
+		if d.Severity == lint.Error {
+			severity = SeverityError
+		}
+		diags = append(diags, Diagnostic{
+			Range: doc.basicLineRange(d.Line), Severity: severity, Code: d.Code,
+			Source: "basics lint", Message: d.Message,
+		})
+	}
+	return diags
+}
+
+// tokenAt retourne l'indice du token à la position donnée (-1 si aucun)
+func (doc *document) tokenAt(pos Position) int {
+	for i, tok := range doc.tokens {
+		if tok.Type == token.EOL || tok.Type == token.EOF {
+			continue
+		}
+		r := tokenRange(tok)
+		if r.Start.Line == pos.Line && pos.Character >= r.Start.Character && pos.Character < r.End.Character {
+			return i
+		}
+	}
+	return -1
+}
+
+// jumpTarget retourne le numéro de ligne visé si le token i est la cible
+// constante d'un GOTO, d'un GOSUB ou d'un THEN / ELSE implicite
+func (doc *document) jumpTarget(i int) (int, bool) {
+	if i <= 0 || doc.tokens[i].Type != token.NUMBER {
+		return 0, false
+	}
+
+	prev := doc.tokens[i-1]
+	if prev.Type != token.KEYWORD {
+		return 0, false
+	}
+	switch prev.Literal {
+	case "GOTO", "GOSUB", "THEN", "ELSE":
+	default:
+		return 0, false
+	}
+
+	// GOTO 10+20 : cible calculée
+	if i+1 < len(doc.tokens) {
+		switch next := doc.tokens[i+1]; next.Type {
+		case token.EOL, token.EOF, token.COLON:
+		default:
+			if next.Type != token.KEYWORD || next.Literal != "ELSE" {
+				return 0, false
+			}
+		}
+	}
+
+	n, err := strconv.Atoi(doc.tokens[i].Literal)
+	return n, err == nil
+}
+
+// lineAt retourne le numéro de ligne désigné à la position : numéro d'une
+// ligne ou cible d'un saut
+func (doc *document) lineAt(pos Position) (int, bool) {
+	i := doc.tokenAt(pos)
+	if i < 0 {
+		return 0, false
+	}
+	if doc.tokens[i].Type == token.LINENUM {
+		n, err := strconv.Atoi(doc.tokens[i].Literal)
+		return n, err == nil
+	}
+	return doc.jumpTarget(i)
+}
+
+// definition retourne l'emplacement de la ligne visée par un saut
+func (doc *document) definition(pos Position) []Location {
+	i := doc.tokenAt(pos)
+	n, ok := doc.jumpTarget(i)
+	if !ok {
+		return nil
+	}
+	decl, ok := doc.numbers[n]
+	if !ok {
+		return nil
+	}
+	return []Location{{URI: doc.uri, Range: tokenRange(doc.tokens[decl])}}
+}
+
+// references retourne les sauts vers la ligne désignée
+func (doc *document) references(pos Position, declaration bool) []Location {
+	n, ok := doc.lineAt(pos)
+	if !ok {
+		return nil
+	}
+
+	locs := []Location{}
+	if decl, ok := doc.numbers[n]; ok && declaration {
+		locs = append(locs, Location{URI: doc.uri, Range: tokenRange(doc.tokens[decl])})
+	}
+	for i := range doc.tokens {
+		if target, ok := doc.jumpTarget(i); ok && target == n {
+			locs = append(locs, Location{URI: doc.uri, Range: tokenRange(doc.tokens[i])})
+		}
+	}
+	return locs
+}
+
+// hover décrit la variable sous le curseur : type déduit de son suffixe
+// et lignes où elle est affectée
+func (doc *document) hover(pos Position) *Hover {
+	i := doc.tokenAt(pos)
+	if i < 0 || doc.tokens[i].Type != token.IDENT {
+		return nil
+	}
+	name := doc.tokens[i].Literal
+
+	kind := "real variable"
+	switch {
+	case strings.HasSuffix(name, "$"):
+		kind = "string variable"
+	case strings.HasSuffix(name, "%"):
+		kind = "integer variable (-32767 to 32767)"
+	}
+
+	text := fmt.Sprintf("`%s`: %s", name, kind)
+	if lines := doc.assignments(name); len(lines) > 0 {
+		text += "\n\nAssigned in " + strings.Join(lines, ", ")
+	}
+	return &Hover{Contents: MarkupContent{Kind: "markdown", Value: text}, Range: tokenRange(doc.tokens[i])}
+}
+
+// assignments retourne les lignes qui affectent la variable
+func (doc *document) assignments(name string) []string {
+	var lines []string
+	for _, line := range doc.prog.Lines {
+		found := false
+		parser.Inspect(line, func(node any) bool {
+			switch s := node.(type) {
+			case *parser.LetStmt:
+				found = found || s.Name == name
+			case *parser.ForStmt:
+				found = found || s.Var == name
+			case *parser.InputStmt:
+				for _, v := range s.Vars {
+					found = found || v.Name == name
+				}
+			case *parser.GetStmt:
+				found = found || s.Var.Name == name
+			}
+			return !found
+		})
+		if found {
+			lines = append(lines, strconv.Itoa(line.Number))
+		}
+	}
+	return lines
+}
+
+// completion propose les mots-clés du dialecte
+func (doc *document) completion() []CompletionItem {
+	var words []string
+	for kw := range doc.dialect.AllKeywords() {
+		words = append(words, kw)
+	}
+	sort.Strings(words)
+
+	items := make([]CompletionItem, 0, len(words))
+	for _, kw := range words {
+		items = append(items, CompletionItem{Label: kw, Kind: CompletionItemKeyword})
+	}
+	return items
+}
+
+// symbols retourne un symbole par ligne BASIC ; les lignes appelées par
+// GOSUB sont des fonctions
+func (doc *document) symbols() []DocumentSymbol {
+	called := map[int]bool{}
+	for i := range doc.tokens {
+		if n, ok := doc.jumpTarget(i); ok && doc.tokens[i-1].Literal == "GOSUB" {
+			called[n] = true
+		}
+	}
+
+	symbols := []DocumentSymbol{}
+	for _, line := range doc.prog.Lines {
+		i, ok := doc.numbers[line.Number]
+		if !ok {
+			continue
+		}
+
+		kind := SymbolKindNumber
+		if called[line.Number] {
+			kind = SymbolKindFunction
+		}
+		symbols = append(symbols, DocumentSymbol{
+			Name:           strconv.Itoa(line.Number),
+			Detail:         unparse.Statements(line.Stmts),
+			Kind:           kind,
+			Range:          doc.lineRange(doc.tokens[i].Line - 1),
+			SelectionRange: tokenRange(doc.tokens[i]),
+		})
+	}
+	return symbols
+}
@@ -0,0 +1,202 @@
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"basics/internal/constants"
+	"basics/internal/dialect"
+	"basics/internal/logger"
+)
+
+// Server est un serveur LSP pour les programmes BASIC, dialogant en
+// JSON-RPC sur un flux (stdin / stdout pour "basics lsp")
+type Server struct {
+	conn     *conn
+	dialect  *dialect.Dialect
+	docs     map[string]*document
+	shutdown bool
+}
+
+// NewServer crée un serveur lisant r et écrivant w ; le dialecte peut être
+// remplacé par les options d'initialisation du client
+func NewServer(r io.Reader, w io.Writer, d *dialect.Dialect) *Server {
+	return &Server{conn: newConn(r, w), dialect: d, docs: map[string]*document{}}
+}
+
+// Serve traite les messages jusqu'à la notification exit ou la fin du flux
+func (s *Server) Serve() error {
+	for {
+		body, err := s.conn.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := s.conn.reply(nil, nil, &responseError{codeParseError, err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if req.Method == "exit" {
+			return nil
+		}
+		if err := s.handle(&req); err != nil {
+			return err
+		}
+	}
+}
+
+// handle traite un message ; les notifications inconnues sont ignorées
+func (s *Server) handle(req *request) error {
+	logger.Debug("LSP " + req.Method)
+
+	if req.ID == nil {
+		return s.notification(req)
+	}
+
+	if s.shutdown {
+		return s.conn.reply(req.ID, nil, &responseError{codeInvalidRequest, "server is shutting down"})
+	}
+
+	var result any
+	var rerr *responseError
+
+	switch req.Method {
+	case "initialize":
+		result, rerr = s.initialize(req.Params)
+	case "shutdown":
+		s.shutdown = true
+	case "textDocument/definition":
+		var p TextDocumentPositionParams
+		if rerr = decode(req.Params, &p); rerr == nil {
+			if doc := s.docs[p.TextDocument.URI]; doc != nil {
+				result = doc.definition(p.Position)
+			}
+		}
+	case "textDocument/references":
+		var p ReferenceParams
+		if rerr = decode(req.Params, &p); rerr == nil {
+			if doc := s.docs[p.TextDocument.URI]; doc != nil {
+				result = doc.references(p.Position, p.Context.IncludeDeclaration)
+			}
+		}
+	case "textDocument/hover":
+		var p TextDocumentPositionParams
+		if rerr = decode(req.Params, &p); rerr == nil {
+			if doc := s.docs[p.TextDocument.URI]; doc != nil {
+				if h := doc.hover(p.Position); h != nil {
+					result = h
+				}
+			}
+		}
+	case "textDocument/completion":
+		var p TextDocumentPositionParams
+		if rerr = decode(req.Params, &p); rerr == nil {
+			if doc := s.docs[p.TextDocument.URI]; doc != nil {
+				result = doc.completion()
+			}
+		}
+	case "textDocument/documentSymbol":
+		var p DocumentSymbolParams
+		if rerr = decode(req.Params, &p); rerr == nil {
+			if doc := s.docs[p.TextDocument.URI]; doc != nil {
+				result = doc.symbols()
+			}
+		}
+	default:
+		rerr = &responseError{codeMethodNotFound, "method not found: " + req.Method}
+	}
+
+	return s.conn.reply(req.ID, result, rerr)
+}
+
+// notification traite les notifications de synchronisation des documents
+func (s *Server) notification(req *request) error {
+	switch req.Method {
+	case "textDocument/didOpen":
+		var p DidOpenParams
+		if decode(req.Params, &p) == nil {
+			return s.update(p.TextDocument.URI, p.TextDocument.Text)
+		}
+	case "textDocument/didChange":
+		var p DidChangeParams
+		if decode(req.Params, &p) == nil && len(p.ContentChanges) > 0 {
+			return s.update(p.TextDocument.URI, p.ContentChanges[len(p.ContentChanges)-1].Text)
+		}
+	case "textDocument/didClose":
+		var p DidCloseParams
+		if decode(req.Params, &p) == nil {
+			delete(s.docs, p.TextDocument.URI)
+			// efface les diagnostics du document fermé
+			return s.conn.notify("textDocument/publishDiagnostics",
+				&PublishDiagnosticsParams{URI: p.TextDocument.URI, Diagnostics: []Diagnostic{}})
+		}
+	}
+	return nil
+}
+
+// initialize choisit le dialecte et annonce les capacités du serveur
+func (s *Server) initialize(params json.RawMessage) (any, *responseError) {
+	var p InitializeParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+
+	opts := p.InitializationOptions
+	if opts.Basic != "" {
+		switch strings.ToUpper(opts.Basic) {
+		case "APPLE":
+			s.dialect = dialect.ForType(constants.BASIC_APPLE)
+		case "C64":
+			s.dialect = dialect.ForType(constants.BASIC_C64)
+		case "AMS":
+			s.dialect = dialect.ForType(constants.BASIC_AMS)
+		default:
+			return nil, &responseError{codeInvalidParams, "unknown BASIC type: " + opts.Basic}
+		}
+	}
+	if opts.Crunched {
+		s.dialect = s.dialect.WithCrunched()
+	}
+	if opts.Lowercase {
+		s.dialect = s.dialect.WithLowercase()
+	}
+
+	return map[string]any{
+		"capabilities": map[string]any{
+			"textDocumentSync":       1, // texte complet à chaque modification
+			"definitionProvider":     true,
+			"referencesProvider":     true,
+			"hoverProvider":          true,
+			"completionProvider":     map[string]any{},
+			"documentSymbolProvider": true,
+		},
+		"serverInfo": map[string]any{"name": "basics"},
+	}, nil
+}
+
+// update analyse le nouveau texte d'un document et publie ses diagnostics
+func (s *Server) update(uri, text string) error {
+	doc := newDocument(uri, text, s.dialect)
+	s.docs[uri] = doc
+	return s.conn.notify("textDocument/publishDiagnostics",
+		&PublishDiagnosticsParams{URI: uri, Diagnostics: doc.diagnostics()})
+}
+
+// decode lit les paramètres d'un message
+func decode(params json.RawMessage, v any) *responseError {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &responseError{codeInvalidParams, err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"basics/internal/dialect"
+	"basics/testutils"
+)
+
+const uri = "file:///test.bas"
+
+// client pilote un serveur lancé dans le même processus
+type client struct {
+	t    *testing.T
+	conn *conn
+	id   int
+	done chan error
+}
+
+func newClient(t *testing.T) *client {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+
+	c := &client{t: t, conn: newConn(outR, inW), done: make(chan error, 1)}
+	go func() {
+		err := NewServer(inR, outW, dialect.Applesoft).Serve()
+		outW.Close()
+		c.done <- err
+	}()
+	return c
+}
+
+// message lit le message suivant du serveur
+func (c *client) message() map[string]json.RawMessage {
+	body, err := c.conn.read()
+	if err != nil {
+		c.t.Fatalf("read: %v", err)
+	}
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		c.t.Fatalf("unmarshal: %v", err)
+	}
+	return msg
+}
+
+// call envoie une requête et décode le résultat dans v
+func (c *client) call(method string, params any, v any) map[string]json.RawMessage {
+	c.id++
+	if err := c.conn.write(map[string]any{"jsonrpc": "2.0", "id": c.id, "method": method, "params": params}); err != nil {
+		c.t.Fatalf("write: %v", err)
+	}
+	msg := c.message()
+	if v != nil {
+		if err := json.Unmarshal(msg["result"], v); err != nil {
+			c.t.Fatalf("result of %s: %v", method, err)
+		}
+	}
+	return msg
+}
+
+// open ouvre un document et retourne les diagnostics publiés
+func (c *client) open(text string) []Diagnostic {
+	params := DidOpenParams{TextDocument: TextDocumentItem{URI: uri, Version: 1, Text: text}}
+	if err := c.conn.notify("textDocument/didOpen", params); err != nil {
+		c.t.Fatalf("write: %v", err)
+	}
+	var p PublishDiagnosticsParams
+	if err := json.Unmarshal(c.message()["params"], &p); err != nil {
+		c.t.Fatalf("diagnostics: %v", err)
+	}
+	return p.Diagnostics
+}
+
+func (c *client) close() {
+	c.call("shutdown", nil, nil)
+	_ = c.conn.notify("exit", nil)
+	if err := <-c.done; err != nil {
+		c.t.Fatalf("serve: %v", err)
+	}
+}
+
+func at(line, char int) TextDocumentPositionParams {
+	return TextDocumentPositionParams{TextDocument: TextDocumentIdentifier{URI: uri}, Position: Position{Line: line, Character: char}}
+}
+
+func TestServer_Initialize(t *testing.T) {
+	c := newClient(t)
+	defer c.close()
+
+	var result struct {
+		Capabilities map[string]any `json:"capabilities"`
+	}
+	c.call("initialize", map[string]any{"initializationOptions": map[string]any{"basic": "c64"}}, &result)
+	testutils.Equal(t, "full sync", result.Capabilities["textDocumentSync"], any(float64(1)))
+	testutils.Equal(t, "definition", result.Capabilities["definitionProvider"], any(true))
+
+	msg := c.call("textDocument/formatting", map[string]any{}, nil)
+	var rerr responseError
+	_ = json.Unmarshal(msg["error"], &rerr)
+	testutils.Equal(t, "unknown method", rerr.Code, codeMethodNotFound)
+}
+
+func TestServer_Diagnostics(t *testing.T) {
+	c := newClient(t)
+	defer c.close()
+	c.call("initialize", map[string]any{}, nil)
+
+	diags := c.open("10 PRINT \"A\"\n20 GOTO 99\n")
+	testutils.Equal(t, "lint diagnostics", len(diags), 1)
+	testutils.Equal(t, "lint code", diags[0].Code, "undefined-line")
+	testutils.Equal(t, "lint line", diags[0].Range.Start.Line, 1)
+	testutils.Equal(t, "lint severity", diags[0].Severity, SeverityError)
+
+	diags = c.open("10 PRINT \"A\"\n20 FOR = 1\n")
+	testutils.True(t, "syntax diagnostics", len(diags) > 0)
+	testutils.Equal(t, "syntax message", diags[0].Message, "EXPECTED =")
+	testutils.Equal(t, "syntax position", diags[0].Range.Start, Position{Line: 1, Character: 7})
+
+	diags = c.open("10 PRINT 1\n20 END\n")
+	testutils.Equal(t, "clean program", len(diags), 0)
+}
+
+func TestServer_Navigation(t *testing.T) {
+	c := newClient(t)
+	defer c.close()
+	c.call("initialize", map[string]any{}, nil)
+
+	c.open("10 A% = 5: GOSUB 100\n20 IF A% > 1 THEN 10\n30 END\n100 PRINT A%: RETURN\n")
+
+	var locs []Location
+	c.call("textDocument/definition", at(0, 18), &locs)
+	testutils.Equal(t, "definition count", len(locs), 1)
+	testutils.Equal(t, "definition line", locs[0].Range.Start.Line, 3)
+
+	refs := ReferenceParams{TextDocumentPositionParams: at(0, 0)}
+	refs.Context.IncludeDeclaration = true
+	c.call("textDocument/references", refs, &locs)
+	testutils.Equal(t, "references count", len(locs), 2)
+	testutils.Equal(t, "reference from THEN", locs[1].Range.Start.Line, 1)
+
+	var hover Hover
+	c.call("textDocument/hover", at(1, 6), &hover)
+	testutils.Equal(t, "hover", hover.Contents.Value, "`A%`: integer variable (-32767 to 32767)\n\nAssigned in 10")
+
+	var symbols []DocumentSymbol
+	c.call("textDocument/documentSymbol", DocumentSymbolParams{TextDocument: TextDocumentIdentifier{URI: uri}}, &symbols)
+	testutils.Equal(t, "symbols", len(symbols), 4)
+	testutils.Equal(t, "subroutine", symbols[3].Kind, SymbolKindFunction)
+	testutils.Equal(t, "line", symbols[1].Kind, SymbolKindNumber)
+
+	var items []CompletionItem
+	c.call("textDocument/completion", at(2, 0), &items)
+	found := false
+	for _, item := range items {
+		found = found || item.Label == "GOSUB"
+	}
+	testutils.True(t, "completion has GOSUB", found)
+}
@@ -1,6 +1,7 @@
 package apple2
 
 import (
+	"basics/internal/input"
 	"basics/internal/video"
 	ebitenrenderer "basics/internal/video/ebiten"
 	"basics/internal/video/text"
@@ -8,7 +9,6 @@ import (
 	"image/color"
 	"io"
 	"strings"
-	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -26,21 +26,8 @@ type Text40 struct {
 	in  *bufio.Reader
 	out io.Writer
 
-	// For INPUT
-	inputBuffer []rune
-	lineReady   bool
-
-	// For GET
-	getActive bool
-	getChan   chan rune
-
-	// Blinking cursor
-	cursorVisible bool
-	blinkCounter  int
-	inInput       bool
-
-	// Input is allowed
-	allowInput bool
+	// INPUT, GET et curseur clignotant
+	*input.Keyboard
 }
 
 func NewText40(renderer video.Renderer) *Text40 {
@@ -51,15 +38,14 @@ func NewText40(renderer video.Renderer) *Text40 {
 		1, 0, // blanc sur noir
 	)
 
-	return &Text40{
-		Mode:        mode,
-		renderer:    renderer,
-		in:          bufio.NewReader(strings.NewReader("")),
-		out:         io.Discard,
-		inputBuffer: make([]rune, 0, 64),
-		lineReady:   false,
-		allowInput:  false,
+	t := &Text40{
+		Mode:     mode,
+		renderer: renderer,
+		in:       bufio.NewReader(strings.NewReader("")),
+		out:      io.Discard,
 	}
+	t.Keyboard = input.NewKeyboard(t)
+	return t
 }
 
 // --------------------
@@ -110,28 +96,22 @@ func (t *Text40) SetOutput(w io.Writer) {
 // Ebiten integration
 // --------------------
 
-func (t *Text40) Update() error {
-	if !t.inInput {
-		t.cursorVisible = false
-		t.blinkCounter = 0
-		return nil
-	}
-
-	t.blinkCounter++
-	if t.blinkCounter >= 30 { // ~0.5s à 60 FPS
-		t.cursorVisible = !t.cursorVisible
-		t.blinkCounter = 0
-	}
+// Title retourne le titre de la fenêtre
+func (t *Text40) Title() string {
+	return "BASIC – Apple II"
+}
 
+func (t *Text40) Update() error {
+	t.Blink()
 	return nil
 }
 
 func (t *Text40) Draw(screen *ebiten.Image) {
 	// Gestion du curseur clignotant
-	if t.inInput && t.cursorVisible {
+	if t.CursorOn() {
 		t.Mode.PutChar('░')
 		t.SetCursorX(t.Mode.CursorX() - 1)
-	} else if t.inInput && !t.cursorVisible {
+	} else if t.InInput() {
 		t.Mode.PutChar(' ')
 		t.SetCursorX(t.Mode.CursorX() - 1)
 	}
@@ -155,114 +135,25 @@ func (t *Text40) Layout(w, h int) (int, int) {
 }
 
 // --------------------
-// Input & cursor movement
+// input.Echo
 // --------------------
-func (t *Text40) ReadLine() (string, error) {
-	t.BeginInput()
-	defer t.EndInput()
-
-	for !t.lineReady {
-		// attente active mais NON bloquante
-		time.Sleep(5 * time.Millisecond)
-	}
-
-	line := string(t.inputBuffer)
 
-	t.inputBuffer = t.inputBuffer[:0]
-	t.lineReady = false
-
-	// comportement AppleSoft : retour à la ligne automatique
-	t.Mode.NewLine()
-
-	return line, nil
-}
-
-func (t *Text40) InputRune(r rune) {
-	if !t.allowInput {
-		return
-	}
-
-	t.eraseCursorIfVisible()
-
-	t.inputBuffer = append(t.inputBuffer, r)
+func (t *Text40) EchoRune(r rune) {
 	t.Mode.PutChar(r)
 }
 
-func (t *Text40) Backspace() {
-	if !t.allowInput || len(t.inputBuffer) == 0 {
-		return
-	}
-
-	t.eraseCursorIfVisible()
+func (t *Text40) EchoBackspace() bool {
 	t.Mode.Backspace()
-
-	t.inputBuffer = t.inputBuffer[:len(t.inputBuffer)-1]
+	return true
 }
 
-func (t *Text40) Enter() {
-	if !t.allowInput {
-		return
-	}
-
-	t.eraseCursorIfVisible()
-	t.EndInput()
-	t.lineReady = true
-}
-
-func (t *Text40) BeginInput() {
-	t.inInput = true
-	t.allowInput = true
-	t.cursorVisible = true
-	t.blinkCounter = 0
-}
-
-func (t *Text40) EndInput() {
-	t.eraseCursorIfVisible()
-	t.inInput = false
-	t.allowInput = false
-	t.cursorVisible = false
-}
-
-func (t *Text40) BeginGet() {
-	t.getActive = true
-	t.getChan = make(chan rune, 1)
-}
-
-func (t *Text40) EndGet() {
-	t.getActive = false
-}
-
-func (t *Text40) PushGetRune(r rune) {
-	if t.getActive {
-		select {
-		case t.getChan <- r:
-		default:
-		}
-	}
-}
-
-func (t *Text40) GetChar() (rune, error) {
-	t.BeginGet()
-	r := <-t.getChan
-	t.EndGet()
-	return r, nil
-}
-
-func (t *Text40) IsGetActive() bool {
-	return t.getActive
-}
-
-func (t *Text40) eraseCursorIfVisible() {
-	if t.inInput && t.cursorVisible {
-		// remplacer le curseur par un espace
-		t.Mode.PutChar(' ')
-		t.SetCursorX(t.Mode.CursorX() - 1)
-		t.cursorVisible = false
-		t.blinkCounter = 0
-	}
+// EchoNewLine : comportement AppleSoft, retour à la ligne automatique
+func (t *Text40) EchoNewLine() {
+	t.Mode.NewLine()
 }
 
-func (t *Text40) DisableKeyboard() {
-	t.allowInput = false
-	t.inInput = false
+// HideCursor remplace le curseur par un espace
+func (t *Text40) HideCursor() {
+	t.Mode.PutChar(' ')
+	t.SetCursorX(t.Mode.CursorX() - 1)
 }
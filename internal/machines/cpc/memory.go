@@ -0,0 +1,88 @@
+package cpc
+
+import "basics/internal/video/font"
+
+// memory représente la mémoire écran du CPC : un numéro d'encre par pixel
+// matériel. Texte et graphique partagent la même mémoire, comme sur la
+// machine réelle.
+//
+// memory implémente video.Renderer afin de servir de cible au text.TextMode.
+type memory struct {
+	pixels     []byte
+	pixelWidth int
+	font       *font.BitmapFont
+}
+
+func newMemory(f *font.BitmapFont) *memory {
+	return &memory{
+		pixels:     make([]byte, ScreenWidth*ScreenHeight),
+		pixelWidth: Modes[DefaultMode].PixelWidth,
+		font:       f,
+	}
+}
+
+func (m *memory) Width() int  { return ScreenWidth }
+func (m *memory) Height() int { return ScreenHeight }
+
+func (m *memory) Clear() {
+	m.fill(0)
+}
+
+func (m *memory) fill(pen int) {
+	for i := range m.pixels {
+		m.pixels[i] = byte(pen)
+	}
+}
+
+// DrawPixel écrit un pixel matériel
+func (m *memory) DrawPixel(x, y int, pen int) {
+	if x < 0 || y < 0 || x >= ScreenWidth || y >= ScreenHeight {
+		return
+	}
+	m.pixels[y*ScreenWidth+x] = byte(pen)
+}
+
+// plot écrit un pixel logique du mode courant (x en pixels logiques)
+func (m *memory) plot(x, y int, pen int) {
+	for i := 0; i < m.pixelWidth; i++ {
+		m.DrawPixel(x*m.pixelWidth+i, y, pen)
+	}
+}
+
+// DrawGlyph rasterise un caractère à la position (x, y) en pixels matériels.
+// Chaque colonne du glyphe occupe un pixel logique du mode courant.
+func (m *memory) DrawGlyph(x, y int, glyph rune, fg, bg int) {
+	bitmap := m.font.Glyph(glyph)
+
+	for row := 0; row < m.font.Height; row++ {
+		bits := bitmap[row]
+
+		for col := 0; col < m.font.Width; col++ {
+			pen := bg
+			if bits&byte(1<<col) != 0 {
+				pen = fg
+			}
+			for i := 0; i < m.pixelWidth; i++ {
+				m.DrawPixel(x+col*m.pixelWidth+i, y+row, pen)
+			}
+		}
+	}
+}
+
+// scrollUp décale l'écran d'une ligne de texte vers le haut et efface la
+// dernière ligne avec l'encre de fond
+func (m *memory) scrollUp(lines int, paper int) {
+	offset := lines * ScreenWidth
+	copy(m.pixels, m.pixels[offset:])
+	for i := len(m.pixels) - offset; i < len(m.pixels); i++ {
+		m.pixels[i] = byte(paper)
+	}
+}
+
+// at retourne l'encre d'un pixel matériel
+func (m *memory) at(x, y int) int {
+	if x < 0 || y < 0 || x >= ScreenWidth || y >= ScreenHeight {
+		return 0
+	}
+	return int(m.pixels[y*ScreenWidth+x])
+}
@@ -0,0 +1,53 @@
+package cpc
+
+import "basics/internal/video"
+
+// Résolution matérielle de l'écran (granularité du mode 2)
+const (
+	ScreenWidth  = 640
+	ScreenHeight = 200
+	TextRows     = 25
+)
+
+// Taille du cadre (BORDER) autour de l'écran, en pixels de sortie
+const (
+	BorderX = 32
+	BorderY = 32
+)
+
+// Taille du framebuffer de sortie : les lignes matérielles sont doublées
+// pour respecter le rapport d'aspect du moniteur CPC
+const (
+	OutputWidth  = ScreenWidth + 2*BorderX
+	OutputHeight = 2*ScreenHeight + 2*BorderY
+)
+
+// ModeSpec décrit un mode écran du CPC
+type ModeSpec struct {
+	ID         video.ModeID
+	Name       string
+	Cols       int // colonnes texte
+	PixelWidth int // largeur d'un pixel logique en pixels matériels
+	Pens       int // nombre d'encres affichables
+}
+
+// Modes liste les trois modes écran, indexés par leur numéro BASIC
+var Modes = [3]ModeSpec{
+	{ID: "cpc.mode0", Name: "MODE 0 (160x200, 16 colors)", Cols: 20, PixelWidth: 4, Pens: 16},
+	{ID: "cpc.mode1", Name: "MODE 1 (320x200, 4 colors)", Cols: 40, PixelWidth: 2, Pens: 4},
+	{ID: "cpc.mode2", Name: "MODE 2 (640x200, 2 colors)", Cols: 80, PixelWidth: 1, Pens: 2},
+}
+
+// DefaultMode est le mode actif à l'allumage
+const DefaultMode = 1
+
+// Info retourne la description video.ModeInfo du mode
+func (m ModeSpec) Info() video.ModeInfo {
+	return video.ModeInfo{
+		ID:     m.ID,
+		Name:   m.Name,
+		Width:  ScreenWidth / m.PixelWidth,
+		Height: ScreenHeight,
+		Text:   false,
+	}
+}
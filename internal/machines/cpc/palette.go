@@ -0,0 +1,50 @@
+package cpc
+
+import (
+	"basics/internal/video"
+	"image/color"
+)
+
+// NumColors est le nombre de couleurs matérielles du CPC
+const NumColors = 27
+
+// ColorNames donne le nom de chaque couleur matérielle (numérotation firmware)
+var ColorNames = [NumColors]string{
+	"Black", "Blue", "Bright Blue",
+	"Red", "Magenta", "Mauve",
+	"Bright Red", "Purple", "Bright Magenta",
+	"Green", "Cyan", "Sky Blue",
+	"Yellow", "White", "Pastel Blue",
+	"Orange", "Pink", "Pastel Magenta",
+	"Bright Green", "Sea Green", "Bright Cyan",
+	"Lime", "Pastel Green", "Pastel Cyan",
+	"Bright Yellow", "Pastel Yellow", "Bright White",
+}
+
+// Palette retourne les 27 couleurs matérielles du CPC.
+// La couleur firmware n vaut 9*G + 3*R + B, chaque composante ayant
+// trois niveaux (0%, 50%, 100%).
+func Palette() video.Palette {
+	levels := [3]uint8{0x00, 0x80, 0xff}
+
+	p := make(video.Palette, NumColors)
+	for n := 0; n < NumColors; n++ {
+		g := n / 9
+		r := (n / 3) % 3
+		b := n % 3
+		p[n] = color.RGBA{levels[r], levels[g], levels[b], 0xff}
+	}
+	return p
+}
+
+// DefaultInks est l'affectation encre → couleurs au démarrage de la machine.
+// Chaque encre a deux couleurs (identiques si l'encre ne clignote pas).
+var DefaultInks = [16][2]int{
+	{1, 1}, {24, 24}, {20, 20}, {6, 6},
+	{26, 26}, {0, 0}, {2, 2}, {8, 8},
+	{10, 10}, {12, 12}, {14, 14}, {16, 16},
+	{18, 18}, {22, 22}, {1, 24}, {16, 11},
+}
+
+// DefaultBorder est la couleur du cadre au démarrage
+const DefaultBorder = 1
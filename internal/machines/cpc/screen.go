@@ -0,0 +1,449 @@
+package cpc
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"sync"
+
+	"basics/internal/input"
+	"basics/internal/video"
+	ebitenrenderer "basics/internal/video/ebiten"
+	"basics/internal/video/font"
+	"basics/internal/video/text"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Nombre de frames (60 FPS) entre deux phases des encres clignotantes
+// (SPEED INK 10,10 par défaut : 10/50e de seconde)
+const flashFrames = 12
+
+// Screen est le device vidéo de l'Amstrad CPC 6128
+type Screen struct {
+	Text     *text.TextMode
+	renderer video.Renderer
+
+	mu     sync.Mutex
+	mem    *memory
+	mode   int
+	inks   [16][2]int
+	border [2]int
+
+	// Encres clignotantes
+	flashPhase   int
+	flashCounter int
+
+	// Curseur graphique (coordonnées BASIC, origine en bas à gauche)
+	gx, gy int
+	gpen   int
+
+	in  *bufio.Reader
+	out io.Writer
+
+	// INPUT, GET et curseur clignotant
+	*input.Keyboard
+}
+
+// NewScreen crée l'écran CPC en MODE 1, avec les encres par défaut
+func NewScreen(renderer video.Renderer, f *font.BitmapFont) *Screen {
+	s := &Screen{
+		renderer: renderer,
+		mem:      newMemory(f),
+		inks:     DefaultInks,
+		border:   [2]int{DefaultBorder, DefaultBorder},
+		in:       bufio.NewReader(strings.NewReader("")),
+		out:      io.Discard,
+	}
+	s.Keyboard = input.NewKeyboard(s)
+	s.setMode(DefaultMode)
+	return s
+}
+
+// --------------------
+// video.Provider
+// --------------------
+
+func (s *Screen) Modes() []video.ModeID {
+	ids := make([]video.ModeID, 0, len(Modes))
+	for _, m := range Modes {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func (s *Screen) ModeInfo(id video.ModeID) (video.ModeInfo, bool) {
+	for _, m := range Modes {
+		if m.ID == id {
+			return m.Info(), true
+		}
+	}
+	return video.ModeInfo{}, false
+}
+
+func (s *Screen) DefaultMode() video.ModeID {
+	return Modes[DefaultMode].ID
+}
+
+// CurrentMode retourne le numéro BASIC du mode actif
+func (s *Screen) CurrentMode() int {
+	return s.mode
+}
+
+// --------------------
+// video.ModeDevice
+// --------------------
+
+func (s *Screen) SetMode(mode int) error {
+	if mode < 0 || mode >= len(Modes) {
+		return video.ErrImproperArgument
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setMode(mode)
+	return nil
+}
+
+// setMode change de mode : l'écran est effacé, PEN 1 / PAPER 0 restaurés
+func (s *Screen) setMode(mode int) {
+	spec := Modes[mode]
+
+	s.mode = mode
+	s.mem.pixelWidth = spec.PixelWidth
+	s.mem.Clear()
+
+	s.Text = text.NewTextMode(
+		s.mem,
+		spec.Cols, TextRows,
+		s.mem.font.Width*spec.PixelWidth, s.mem.font.Height,
+		1, 0,
+	)
+
+	s.gx, s.gy = 0, 0
+	s.gpen = 1
+}
+
+// maskPen ramène une encre au nombre d'encres du mode courant
+func (s *Screen) maskPen(pen int) int {
+	return pen % Modes[s.mode].Pens
+}
+
+// --------------------
+// video.InkDevice
+// --------------------
+
+func (s *Screen) SetInk(ink, color1, color2 int) error {
+	if ink < 0 || ink > 15 || !validColor(color1) || !validColor(color2) {
+		return video.ErrImproperArgument
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inks[ink] = [2]int{color1, color2}
+	return nil
+}
+
+func (s *Screen) SetPen(pen int) error {
+	if pen < 0 || pen > 15 {
+		return video.ErrImproperArgument
+	}
+	s.Text.FG = s.maskPen(pen)
+	return nil
+}
+
+func (s *Screen) SetPaper(paper int) error {
+	if paper < 0 || paper > 15 {
+		return video.ErrImproperArgument
+	}
+	s.Text.BG = s.maskPen(paper)
+	return nil
+}
+
+func (s *Screen) SetBorder(color1, color2 int) error {
+	if !validColor(color1) || !validColor(color2) {
+		return video.ErrImproperArgument
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.border = [2]int{color1, color2}
+	return nil
+}
+
+// Ink retourne les deux couleurs matérielles associées à une encre
+func (s *Screen) Ink(ink int) (int, int) {
+	return s.inks[ink][0], s.inks[ink][1]
+}
+
+// Border retourne les deux couleurs matérielles du cadre
+func (s *Screen) Border() (int, int) {
+	return s.border[0], s.border[1]
+}
+
+func validColor(c int) bool {
+	return c >= 0 && c < NumColors
+}
+
+// --------------------
+// video.GraphicsDevice
+// --------------------
+
+func (s *Screen) SetGraphicsPen(ink int) error {
+	if ink < 0 || ink > 15 {
+		return video.ErrImproperArgument
+	}
+	s.gpen = s.maskPen(ink)
+	return nil
+}
+
+// Plot allume le point (x, y) en coordonnées BASIC (640x400, origine en bas
+// à gauche) et y place le curseur graphique
+func (s *Screen) Plot(x, y int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.gx, s.gy = x, y
+	px, py := s.toPixel(x, y)
+	s.plotPixel(px, py)
+}
+
+// DrawTo trace une ligne du curseur graphique jusqu'à (x, y)
+func (s *Screen) DrawTo(x, y int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	x0, y0 := s.toPixel(s.gx, s.gy)
+	x1, y1 := s.toPixel(x, y)
+	s.gx, s.gy = x, y
+
+	// Bresenham
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+
+	for {
+		s.plotPixel(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// GraphicsCursor retourne la position du curseur graphique
+func (s *Screen) GraphicsCursor() (int, int) {
+	return s.gx, s.gy
+}
+
+// PixelAt retourne l'encre du pixel logique qui contient le point BASIC (x, y)
+func (s *Screen) PixelAt(x, y int) int {
+	px, py := s.toPixel(x, y)
+	return s.mem.at(px*s.mem.pixelWidth, py)
+}
+
+// toPixel convertit des coordonnées BASIC en pixels logiques du mode
+func (s *Screen) toPixel(x, y int) (int, int) {
+	return floorDiv(x, s.mem.pixelWidth), ScreenHeight - 1 - floorDiv(y, 2)
+}
+
+func (s *Screen) plotPixel(px, py int) {
+	if px < 0 || py < 0 || px >= ScreenWidth/s.mem.pixelWidth || py >= ScreenHeight {
+		return
+	}
+	s.mem.plot(px, py, s.gpen)
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// --------------------
+// video.Device
+// --------------------
+
+// Clear efface l'écran texte avec l'encre de fond (CLS)
+func (s *Screen) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mem.fill(s.Text.BG)
+	s.Text.Home()
+}
+
+func (s *Screen) PrintChar(r rune) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.putChar(r)
+}
+
+func (s *Screen) PrintString(str string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range str {
+		s.putChar(r)
+	}
+}
+
+// putChar écrit un caractère dans la grille texte ET dans la mémoire écran.
+// Seule la cellule modifiée est rasterisée, afin de préserver les tracés
+// graphiques du reste de l'écran.
+func (s *Screen) putChar(r rune) {
+	b := s.Text.Buffer
+	x, y := b.CursorX, b.CursorY
+
+	scroll := y == b.Rows-1 &&
+		(r == '\n' || (r != '\r' && x == b.Cols-1))
+
+	if r != '\n' && r != '\r' {
+		s.mem.DrawGlyph(x*s.Text.CellW, y*s.Text.CellH, r, s.Text.FG, s.Text.BG)
+	}
+
+	s.Text.PutChar(r)
+
+	if scroll {
+		s.mem.scrollUp(s.Text.CellH, s.Text.BG)
+	}
+}
+
+func (s *Screen) SetCursorX(x int) {
+	s.Text.SetCursor(x, s.Text.CursorY())
+}
+
+func (s *Screen) SetCursorY(y int) {
+	s.Text.SetCursor(s.Text.CursorX(), y)
+}
+
+// Render est sans effet : la mémoire écran est mise à jour à chaque
+// écriture, la conversion en couleurs est faite par Draw
+func (s *Screen) Render() {}
+
+func (s *Screen) SetInput(r io.Reader) {
+	s.in = bufio.NewReader(r)
+}
+
+func (s *Screen) SetOutput(w io.Writer) {
+	s.out = w
+}
+
+// --------------------
+// Ebiten integration
+// --------------------
+
+// Title retourne le titre de la fenêtre
+func (s *Screen) Title() string {
+	return "BASIC – Amstrad CPC 6128"
+}
+
+func (s *Screen) Update() error {
+	s.flashCounter++
+	if s.flashCounter >= flashFrames {
+		s.flashPhase = 1 - s.flashPhase
+		s.flashCounter = 0
+	}
+
+	s.Blink()
+	return nil
+}
+
+// present convertit la mémoire écran en couleurs matérielles via la table
+// des encres, dessine le cadre et le curseur d'INPUT
+func (s *Screen) present() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.renderer
+	border := s.border[s.flashPhase]
+
+	for y := 0; y < OutputHeight; y++ {
+		for x := 0; x < OutputWidth; x++ {
+			if x < BorderX || x >= BorderX+ScreenWidth ||
+				y < BorderY || y >= BorderY+2*ScreenHeight {
+				r.DrawPixel(x, y, border)
+			}
+		}
+	}
+
+	for y := 0; y < ScreenHeight; y++ {
+		for x := 0; x < ScreenWidth; x++ {
+			c := s.inks[s.mem.pixels[y*ScreenWidth+x]][s.flashPhase]
+			r.DrawPixel(BorderX+x, BorderY+2*y, c)
+			r.DrawPixel(BorderX+x, BorderY+2*y+1, c)
+		}
+	}
+
+	if s.CursorOn() {
+		c := s.inks[s.Text.FG][s.flashPhase]
+		cx := BorderX + s.Text.CursorX()*s.Text.CellW
+		cy := BorderY + 2*s.Text.CursorY()*s.Text.CellH
+		for y := 0; y < 2*s.Text.CellH; y++ {
+			for x := 0; x < s.Text.CellW; x++ {
+				r.DrawPixel(cx+x, cy+y, c)
+			}
+		}
+	}
+}
+
+func (s *Screen) Draw(screen *ebiten.Image) {
+	s.present()
+
+	if r, ok := s.renderer.(*ebitenrenderer.Renderer); ok {
+		r.BlitTo(screen)
+	}
+}
+
+func (s *Screen) Layout(w, h int) (int, int) {
+	return OutputWidth, OutputHeight
+}
+
+// --------------------
+// input.Echo
+// --------------------
+
+func (s *Screen) EchoRune(r rune) {
+	s.PrintChar(r)
+}
+
+func (s *Screen) EchoBackspace() bool {
+	if s.Text.CursorX() == 0 {
+		return false
+	}
+
+	s.SetCursorX(s.Text.CursorX() - 1)
+	s.PrintChar(' ')
+	s.SetCursorX(s.Text.CursorX() - 1)
+	return true
+}
+
+func (s *Screen) EchoNewLine() {
+	s.PrintChar('\n')
+}
+
+// HideCursor : le curseur est dessiné par present, pas dans l'écran
+func (s *Screen) HideCursor() {}
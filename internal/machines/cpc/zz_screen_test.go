@@ -0,0 +1,207 @@
+package cpc
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"basics/internal/video"
+	"basics/internal/video/font"
+	"basics/testutils"
+)
+
+// fakeRenderer mémorise les pixels présentés par Screen
+type fakeRenderer struct {
+	pixels map[[2]int]int
+}
+
+func newFakeRenderer() *fakeRenderer {
+	return &fakeRenderer{pixels: make(map[[2]int]int)}
+}
+
+func (f *fakeRenderer) Width() int                             { return OutputWidth }
+func (f *fakeRenderer) Height() int                            { return OutputHeight }
+func (f *fakeRenderer) Clear()                                 {}
+func (f *fakeRenderer) DrawPixel(x, y int, c int)              { f.pixels[[2]int{x, y}] = c }
+func (f *fakeRenderer) DrawGlyph(x, y int, g rune, fg, bg int) {}
+func newTestScreen() (*Screen, *fakeRenderer) {
+	r := newFakeRenderer()
+	return NewScreen(r, font.Font8x8), r
+}
+func (f *fakeRenderer) at(x, y int) int { return f.pixels[[2]int{x, y}] }
+
+func TestPalette(t *testing.T) {
+	p := Palette()
+	testutils.Equal(t, "palette size", len(p), NumColors)
+
+	tests := []struct {
+		n    int
+		want color.RGBA
+	}{
+		{0, color.RGBA{0x00, 0x00, 0x00, 0xff}},  // Black
+		{1, color.RGBA{0x00, 0x00, 0x80, 0xff}},  // Blue
+		{6, color.RGBA{0xff, 0x00, 0x00, 0xff}},  // Bright Red
+		{13, color.RGBA{0x80, 0x80, 0x80, 0xff}}, // White
+		{18, color.RGBA{0x00, 0xff, 0x00, 0xff}}, // Bright Green
+		{24, color.RGBA{0xff, 0xff, 0x00, 0xff}}, // Bright Yellow
+		{26, color.RGBA{0xff, 0xff, 0xff, 0xff}}, // Bright White
+	}
+
+	for _, tt := range tests {
+		msg := fmt.Sprintf("colour %d (%s)", tt.n, ColorNames[tt.n])
+		testutils.Equal(t, msg, p[tt.n], tt.want)
+	}
+}
+
+func TestScreen_Modes(t *testing.T) {
+	s, _ := newTestScreen()
+	testutils.Equal(t, "default mode", s.CurrentMode(), 1)
+	testutils.Equal(t, "default columns", s.Text.Buffer.Cols, 40)
+
+	tests := []struct {
+		mode int
+		cols int
+	}{
+		{0, 20},
+		{1, 40},
+		{2, 80},
+	}
+
+	for _, tt := range tests {
+		err := s.SetMode(tt.mode)
+		testutils.True(t, "SetMode ok", err == nil)
+		testutils.Equal(t, fmt.Sprintf("MODE %d columns", tt.mode), s.Text.Buffer.Cols, tt.cols)
+		testutils.Equal(t, fmt.Sprintf("MODE %d rows", tt.mode), s.Text.Buffer.Rows, TextRows)
+		testutils.Equal(t, fmt.Sprintf("MODE %d cell width", tt.mode), s.Text.CellW*tt.cols, ScreenWidth)
+	}
+
+	testutils.True(t, "MODE 3 is improper", s.SetMode(3) == video.ErrImproperArgument)
+	testutils.True(t, "MODE -1 is improper", s.SetMode(-1) == video.ErrImproperArgument)
+
+	info, ok := s.ModeInfo("cpc.mode0")
+	testutils.True(t, "mode0 info exists", ok)
+	testutils.Equal(t, "mode0 width", info.Width, 160)
+	testutils.Equal(t, "provider default mode", s.DefaultMode(), video.ModeID("cpc.mode1"))
+}
+
+func TestScreen_InksAndBorder(t *testing.T) {
+	s, r := newTestScreen()
+
+	testutils.True(t, "INK 0,26", s.SetInk(0, 26, 26) == nil)
+	testutils.True(t, "BORDER 6", s.SetBorder(6, 6) == nil)
+
+	c1, c2 := s.Ink(0)
+	testutils.Equal(t, "ink 0 colour 1", c1, 26)
+	testutils.Equal(t, "ink 0 colour 2", c2, 26)
+
+	s.present()
+	testutils.Equal(t, "border pixel", r.at(0, 0), 6)
+	testutils.Equal(t, "screen pixel uses ink 0", r.at(BorderX, BorderY), 26)
+
+	testutils.True(t, "INK 16 is improper", s.SetInk(16, 0, 0) == video.ErrImproperArgument)
+	testutils.True(t, "colour 27 is improper", s.SetInk(0, 27, 0) == video.ErrImproperArgument)
+	testutils.True(t, "BORDER 27 is improper", s.SetBorder(27, 27) == video.ErrImproperArgument)
+}
+
+func TestScreen_FlashingInk(t *testing.T) {
+	s, r := newTestScreen()
+	_ = s.SetInk(0, 3, 9)
+
+	s.present()
+	testutils.Equal(t, "first phase", r.at(BorderX, BorderY), 3)
+
+	for i := 0; i < flashFrames; i++ {
+		_ = s.Update()
+	}
+	s.present()
+	testutils.Equal(t, "second phase", r.at(BorderX, BorderY), 9)
+}
+
+func TestScreen_PenMaskedByMode(t *testing.T) {
+	s, _ := newTestScreen()
+
+	_ = s.SetMode(1)
+	_ = s.SetPen(5)
+	testutils.Equal(t, "PEN 5 in MODE 1", s.Text.FG, 1)
+
+	_ = s.SetMode(0)
+	_ = s.SetPen(5)
+	testutils.Equal(t, "PEN 5 in MODE 0", s.Text.FG, 5)
+
+	testutils.True(t, "PEN 16 is improper", s.SetPen(16) == video.ErrImproperArgument)
+}
+
+func TestScreen_PrintWritesScreenMemory(t *testing.T) {
+	s, _ := newTestScreen()
+	_ = s.SetMode(2)
+	_ = s.SetPaper(0)
+	_ = s.SetPen(1)
+
+	s.PrintString("!")
+
+	// '!' : la ligne 0 du glyphe vaut 0x18 (bits 3 et 4)
+	testutils.Equal(t, "glyph bit 3", s.mem.at(3, 0), 1)
+	testutils.Equal(t, "glyph bit 4", s.mem.at(4, 0), 1)
+	testutils.Equal(t, "glyph bit 0", s.mem.at(0, 0), 0)
+	testutils.Equal(t, "cursor advanced", s.Text.CursorX(), 1)
+}
+
+func TestScreen_PrintPreservesGraphics(t *testing.T) {
+	s, _ := newTestScreen()
+	_ = s.SetMode(2)
+
+	s.Plot(639, 0) // dernier pixel de la dernière ligne
+	s.PrintString("A")
+
+	testutils.Equal(t, "plotted pixel kept", s.PixelAt(639, 0), 1)
+}
+
+func TestScreen_ScrollMovesGraphics(t *testing.T) {
+	s, _ := newTestScreen()
+	_ = s.SetMode(2)
+
+	s.Plot(0, 0) // ligne matérielle 199
+	s.SetCursorY(TextRows - 1)
+	s.PrintString("\n")
+
+	testutils.Equal(t, "pixel scrolled up one text row", s.mem.at(0, 199-8), 1)
+	testutils.Equal(t, "bottom row cleared", s.mem.at(0, 199), 0)
+}
+
+func TestScreen_PlotAndDraw(t *testing.T) {
+	s, _ := newTestScreen()
+	_ = s.SetMode(1)
+
+	s.Plot(0, 0)
+	testutils.Equal(t, "origin is bottom-left", s.mem.at(0, 199), 1)
+	testutils.Equal(t, "MODE 1 pixel is 2 wide", s.mem.at(1, 199), 1)
+	testutils.Equal(t, "next pixel untouched", s.mem.at(2, 199), 0)
+
+	_ = s.SetGraphicsPen(3)
+	s.DrawTo(0, 398)
+	for y := 0; y < 399; y += 2 {
+		testutils.Equal(t, fmt.Sprintf("vertical line at y=%d", y), s.PixelAt(0, y), 3)
+	}
+
+	gx, gy := s.GraphicsCursor()
+	testutils.Equal(t, "graphics cursor x", gx, 0)
+	testutils.Equal(t, "graphics cursor y", gy, 398)
+
+	s.DrawTo(638, 398)
+	testutils.Equal(t, "horizontal line end", s.PixelAt(638, 398), 3)
+
+	// hors écran : ignoré sans erreur
+	s.Plot(1000, 1000)
+	s.DrawTo(-50, -50)
+}
+
+func TestScreen_ClearUsesPaper(t *testing.T) {
+	s, _ := newTestScreen()
+	_ = s.SetPaper(2)
+	s.PrintString("HELLO")
+	s.Clear()
+
+	testutils.Equal(t, "screen cleared with paper", s.mem.at(100, 100), 2)
+	testutils.Equal(t, "cursor home x", s.Text.CursorX(), 0)
+	testutils.Equal(t, "cursor home y", s.Text.CursorY(), 0)
+}
@@ -7,6 +7,7 @@ import (
 	"basics/internal/constants"
 	"basics/internal/logger"
 	"basics/internal/machines/apple2"
+	"basics/internal/machines/cpc"
 	"basics/internal/machines/tty"
 	"basics/internal/runtime"
 	ebitenrenderer "basics/internal/video/ebiten"
@@ -31,6 +32,20 @@ func NewRuntime(basicType byte) (*runtime.Runtime, error) {
 
 		return runtime.New(video), nil
 
+	case constants.BASIC_AMS:
+		// --- Amstrad CPC 6128 ---
+		renderer := ebitenrenderer.New(
+			cpc.OutputWidth, cpc.OutputHeight,
+			1, // scale
+			cpc.Palette(),
+			font.DefaultFontForMode(basicType),
+		)
+
+		video := cpc.NewScreen(renderer, font.DefaultFontForMode(basicType))
+		logger.Info("Instanciate Ebiten renderer")
+
+		return runtime.New(video), nil
+
 	case constants.BASIC_TTY:
 		in := bufio.NewReader(os.Stdin)
 		out := os.Stdout
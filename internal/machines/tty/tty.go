@@ -48,7 +48,7 @@ func (t *TTYDevice) SetCursorY(y int) {}
 
 func (t *TTYDevice) Clear() {
 	t.buffer = nil
-	fmt.Print("\033[2J\033[H")
+	fmt.Fprint(t.out, "\033[2J\033[H")
 }
 
 func (t *TTYDevice) Render() {
@@ -4,11 +4,39 @@ type Node interface {
 	Pos() (line int, col int, token string)
 }
 
+// Position est une position dans le fichier source : ligne et colonne (en
+// caractères) à partir de 1
+type Position struct {
+	Line   int
+	Column int
+}
+
+// Span délimite un nœud dans le source ; End est la position qui suit son
+// dernier caractère. Il est nul pour les nœuds construits hors du parser
+// (binaire compilé, instructions internes).
+type Span struct {
+	Start Position
+	End   Position
+}
+
+// Spanned est implémenté par les lignes, instructions et expressions
+type Spanned interface {
+	Range() Span
+	SetRange(Span)
+}
+
+// Range retourne la zone du source couverte par le nœud
+func (s Span) Range() Span { return s }
+
+// SetRange fixe la zone du nœud (parser, décodage d'un binaire)
+func (s *Span) SetRange(r Span) { *s = r }
+
 type Program struct {
 	Lines []*Line
 }
 
 type Line struct {
+	Span
 	Number int
 	Stmts  []Statement
 }
@@ -22,6 +50,7 @@ type Statement interface {
 
 // PRINT
 type PrintStmt struct {
+	Span
 	Exprs      []Expression
 	Separators []rune // ';' ou ',' pour chaque expression sauf la première
 }
@@ -30,6 +59,7 @@ func (*PrintStmt) stmtNode() {}
 
 // INPUT
 type InputStmt struct {
+	Span
 	Prompt *StringLiteral // nil si absent
 	Vars   []*Identifier
 	Line   int
@@ -39,6 +69,7 @@ type InputStmt struct {
 func (*InputStmt) stmtNode() {}
 
 type GetStmt struct {
+	Span
 	Var *Identifier
 }
 
@@ -46,6 +77,7 @@ func (*GetStmt) stmtNode() {}
 
 // LET
 type LetStmt struct {
+	Span
 	Name  string
 	Value Expression
 }
@@ -54,6 +86,7 @@ func (*LetStmt) stmtNode() {}
 
 // FOR ... TO ... STEP ... NEXT
 type ForStmt struct {
+	Span
 	Var     string
 	Start   Expression
 	End     Expression
@@ -65,6 +98,7 @@ type ForStmt struct {
 func (*ForStmt) stmtNode() {}
 
 type NextStmt struct {
+	Span
 	Var        string
 	ForLineNum int // Ligne du FOR correspondant
 }
@@ -73,6 +107,7 @@ func (*NextStmt) stmtNode() {}
 
 // HTAB
 type HTabStmt struct {
+	Span
 	Expr Expression
 }
 
@@ -80,6 +115,7 @@ func (*HTabStmt) stmtNode() {}
 
 // VTAB
 type VTabStmt struct {
+	Span
 	Expr Expression
 }
 
@@ -87,6 +123,7 @@ func (*VTabStmt) stmtNode() {}
 
 // END
 type EndStmt struct {
+	Span
 }
 
 func (*EndStmt) stmtNode() {}
@@ -96,6 +133,7 @@ func (*EndStmt) stmtNode() {}
 // =======================
 
 type HomeStmt struct {
+	Span
 	Line   int
 	Column int
 }
@@ -106,26 +144,44 @@ func (s *HomeStmt) Pos() (int, int, string) {
 	return s.Line, s.Column, "HOME"
 }
 
+// TRACE / NOTRACE (Applesoft) : affichage du numéro de chaque ligne exécutée
+type TraceStmt struct {
+	Span
+}
+
+func (*TraceStmt) stmtNode() {}
+
+type NoTraceStmt struct {
+	Span
+}
+
+func (*NoTraceStmt) stmtNode() {}
+
 // =========================
 // Flow control
 // =========================
 type GotoStmt struct {
+	Span
 	Expr Expression
 }
 
 func (*GotoStmt) stmtNode() {}
 
 type GosubStmt struct {
+	Span
 	Expr Expression // ligne cible (expression)
 }
 
 func (*GosubStmt) stmtNode() {}
 
-type ReturnStmt struct{}
+type ReturnStmt struct {
+	Span
+}
 
 func (*ReturnStmt) stmtNode() {}
 
 type IfStmt struct {
+	Span
 	Cond Expression
 	Then []Statement
 	Else []Statement // nil si absent
@@ -134,12 +190,105 @@ type IfStmt struct {
 func (*IfStmt) stmtNode() {}
 
 type IfJumpStmt struct {
+	Span
 	Cond   Expression
 	Target int // PC cible si FAUX
 }
 
 func (*IfJumpStmt) stmtNode() {}
 
+// =========================
+// Écran & graphique (Amstrad CPC)
+// =========================
+
+// MODE n
+type ModeStmt struct {
+	Span
+	Expr Expression
+}
+
+func (*ModeStmt) stmtNode() {}
+
+// CLS
+type ClsStmt struct {
+	Span
+}
+
+func (*ClsStmt) stmtNode() {}
+
+// LOCATE x, y
+type LocateStmt struct {
+	Span
+	X Expression
+	Y Expression
+}
+
+func (*LocateStmt) stmtNode() {}
+
+// INK encre, couleur1 [, couleur2]
+type InkStmt struct {
+	Span
+	Ink    Expression
+	Color1 Expression
+	Color2 Expression // nil si absent (pas de clignotement)
+}
+
+func (*InkStmt) stmtNode() {}
+
+// PEN n
+type PenStmt struct {
+	Span
+	Expr Expression
+}
+
+func (*PenStmt) stmtNode() {}
+
+// PAPER n
+type PaperStmt struct {
+	Span
+	Expr Expression
+}
+
+func (*PaperStmt) stmtNode() {}
+
+// BORDER couleur1 [, couleur2]
+type BorderStmt struct {
+	Span
+	Color1 Expression
+	Color2 Expression // nil si absent (pas de clignotement)
+}
+
+func (*BorderStmt) stmtNode() {}
+
+// PLOT x, y [, encre]
+type PlotStmt struct {
+	Span
+	X   Expression
+	Y   Expression
+	Ink Expression // nil si absent
+}
+
+func (*PlotStmt) stmtNode() {}
+
+// DRAW x, y [, encre]
+type DrawStmt struct {
+	Span
+	X   Expression
+	Y   Expression
+	Ink Expression // nil si absent
+}
+
+func (*DrawStmt) stmtNode() {}
+
+// Instruction ajoutée par le programme hôte : NAME [arg, ...]
+type CallStmt struct {
+	Span
+	Name string
+	Args []Expression
+}
+
+func (*CallStmt) stmtNode() {}
+
 // =========================
 // Expressions
 // =========================
@@ -149,6 +298,7 @@ type Expression interface {
 
 // IDENTIFIER
 type Identifier struct {
+	Span
 	Name   string
 	Line   int
 	Column int
@@ -163,6 +313,7 @@ func (i *Identifier) Pos() (int, int, string) {
 
 // NUMBER
 type NumberLiteral struct {
+	Span
 	Value  float64
 	Line   int
 	Column int
@@ -177,6 +328,7 @@ func (n *NumberLiteral) Pos() (int, int, string) {
 
 // PREFIX
 type PrefixExpr struct {
+	Span
 	Op     string
 	Right  Expression
 	Line   int
@@ -192,6 +344,7 @@ func (pe *PrefixExpr) Pos() (int, int, string) {
 
 // INFIX
 type InfixExpr struct {
+	Span
 	Left   Expression
 	Op     string
 	Right  Expression
@@ -208,6 +361,7 @@ func (ie *InfixExpr) Pos() (int, int, string) {
 
 // STRING
 type StringLiteral struct {
+	Span
 	Value  string
 	Line   int
 	Column int
@@ -227,6 +381,7 @@ func (s *StringLiteral) Pos() (int, int, string) {
 // INT(expr)
 // =========================
 type IntExpr struct {
+	Span
 	Expr   Expression
 	Line   int
 	Column int
@@ -243,6 +398,7 @@ func (i *IntExpr) Pos() (int, int, string) {
 // ABS(expr)
 // =========================
 type AbsExpr struct {
+	Span
 	Expr   Expression
 	Line   int
 	Column int
@@ -260,6 +416,7 @@ func (a *AbsExpr) Pos() (int, int, string) {
 // =========================
 
 type SgnExpr struct {
+	Span
 	Expr   Expression
 	Line   int
 	Column int
@@ -271,3 +428,23 @@ func (*SgnExpr) exprNode() {}
 func (s *SgnExpr) Pos() (int, int, string) {
 	return s.Line, s.Column, s.Token
 }
+
+// =========================
+// NAME(expr, ...)
+// =========================
+
+// CallExpr appelle une fonction ajoutée par le programme hôte
+type CallExpr struct {
+	Span
+	Name   string
+	Args   []Expression
+	Line   int
+	Column int
+	Token  string
+}
+
+func (*CallExpr) exprNode() {}
+
+func (c *CallExpr) Pos() (int, int, string) {
+	return c.Line, c.Column, c.Token
+}
@@ -100,6 +100,12 @@ func dumpStatement(s Statement, indent string, emit Emitter) {
 	case *HomeStmt:
 		emit(indent + "HOME")
 
+	case *TraceStmt:
+		emit(indent + "TRACE")
+
+	case *NoTraceStmt:
+		emit(indent + "NOTRACE")
+
 	case *HTabStmt:
 		emit(indent + "HTAB")
 		dumpExpr(stmt.Expr, indent+"  ", emit)
@@ -111,6 +117,63 @@ func dumpStatement(s Statement, indent string, emit Emitter) {
 	case *EndStmt:
 		emit(indent + "END")
 
+	case *ModeStmt:
+		emit(indent + "MODE")
+		dumpExpr(stmt.Expr, indent+"  ", emit)
+
+	case *ClsStmt:
+		emit(indent + "CLS")
+
+	case *LocateStmt:
+		emit(indent + "LOCATE")
+		dumpExpr(stmt.X, indent+"  ", emit)
+		dumpExpr(stmt.Y, indent+"  ", emit)
+
+	case *InkStmt:
+		emit(indent + "INK")
+		dumpExpr(stmt.Ink, indent+"  ", emit)
+		dumpExpr(stmt.Color1, indent+"  ", emit)
+		if stmt.Color2 != nil {
+			dumpExpr(stmt.Color2, indent+"  ", emit)
+		}
+
+	case *PenStmt:
+		emit(indent + "PEN")
+		dumpExpr(stmt.Expr, indent+"  ", emit)
+
+	case *PaperStmt:
+		emit(indent + "PAPER")
+		dumpExpr(stmt.Expr, indent+"  ", emit)
+
+	case *BorderStmt:
+		emit(indent + "BORDER")
+		dumpExpr(stmt.Color1, indent+"  ", emit)
+		if stmt.Color2 != nil {
+			dumpExpr(stmt.Color2, indent+"  ", emit)
+		}
+
+	case *PlotStmt:
+		emit(indent + "PLOT")
+		dumpExpr(stmt.X, indent+"  ", emit)
+		dumpExpr(stmt.Y, indent+"  ", emit)
+		if stmt.Ink != nil {
+			dumpExpr(stmt.Ink, indent+"  ", emit)
+		}
+
+	case *DrawStmt:
+		emit(indent + "DRAW")
+		dumpExpr(stmt.X, indent+"  ", emit)
+		dumpExpr(stmt.Y, indent+"  ", emit)
+		if stmt.Ink != nil {
+			dumpExpr(stmt.Ink, indent+"  ", emit)
+		}
+
+	case *CallStmt:
+		emit(indent + "CALL " + stmt.Name)
+		for _, arg := range stmt.Args {
+			dumpExpr(arg, indent+"  ", emit)
+		}
+
 	case nil:
 		// REM / instruction vide
 
@@ -152,6 +215,12 @@ func dumpExpr(e Expression, indent string, emit Emitter) {
 		emit(indent + "SGN")
 		dumpExpr(n.Expr, indent+"  ", emit)
 
+	case *CallExpr:
+		emit(indent + "CALL " + n.Name)
+		for _, arg := range n.Args {
+			dumpExpr(arg, indent+"  ", emit)
+		}
+
 	default:
 		emit(indent + "UNKNOWN EXPR")
 	}
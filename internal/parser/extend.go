@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"basics/internal/token"
+)
+
+// Extend ajoute au langage des instructions (NAME [arg, ...]) et des
+// fonctions (NAME(arg, ...)) définies par le programme hôte. Les noms sont
+// des identificateurs en majuscules ; ils ne peuvent plus désigner de
+// variable.
+func (p *Parser) Extend(statements, functions []string) {
+	if p.statements == nil {
+		p.statements = map[string]bool{}
+		p.functions = map[string]bool{}
+	}
+	for _, name := range statements {
+		p.statements[strings.ToUpper(name)] = true
+	}
+	for _, name := range functions {
+		p.functions[strings.ToUpper(name)] = true
+	}
+}
+
+// extension retourne le nom d'instruction ou de fonction ajoutée que
+// désigne un identificateur
+func (p *Parser) extension(lit string) string {
+	if p.dialect.Lowercase {
+		return strings.ToUpper(lit)
+	}
+	return lit
+}
+
+// parseCallStatement analyse une instruction ajoutée ; ses arguments sont
+// séparés par des virgules
+func (p *Parser) parseCallStatement(name string) Statement {
+	p.next() // NAME
+
+	stmt := &CallStmt{Name: name}
+	for !p.statementEnd() {
+		expr := p.parseExpression(LOWEST)
+		if expr == nil {
+			p.syntaxError(fmt.Sprintf("EXPECTED EXPRESSION AFTER %s", name))
+			return nil
+		}
+		stmt.Args = append(stmt.Args, expr)
+
+		if p.curr.Type != token.COMMA {
+			break
+		}
+		p.next() // ,
+	}
+	return stmt
+}
+
+// parseCallExpr analyse l'appel d'une fonction ajoutée : NAME(arg, ...)
+func (p *Parser) parseCallExpr(name string) Expression {
+	tok := p.curr
+	p.next() // NAME
+
+	if !p.expect(token.LPAREN) {
+		return nil
+	}
+
+	call := &CallExpr{Name: name, Line: tok.Line, Column: tok.Column, Token: tok.Literal}
+	for p.curr.Type != token.RPAREN {
+		expr := p.parseExpression(LOWEST)
+		if expr == nil {
+			return nil
+		}
+		call.Args = append(call.Args, expr)
+
+		if p.curr.Type != token.COMMA {
+			break
+		}
+		p.next() // ,
+	}
+
+	if !p.expect(token.RPAREN) {
+		p.hint("missing ')'")
+		return nil
+	}
+	return call
+}
+
+// statementEnd indique si l'instruction courante est terminée
+func (p *Parser) statementEnd() bool {
+	return p.curr.Type == token.EOL ||
+		p.curr.Type == token.COLON ||
+		p.curr.Type == token.EOF ||
+		(p.curr.Type == token.KEYWORD && p.curr.Literal == "ELSE")
+}
@@ -3,9 +3,13 @@ package parser
 import "fmt"
 
 func StmtName(s Statement) string {
-	switch s.(type) {
+	switch stmt := s.(type) {
 	case *HomeStmt:
 		return "HOME"
+	case *TraceStmt:
+		return "TRACE"
+	case *NoTraceStmt:
+		return "NOTRACE"
 	case *InputStmt:
 		return "INPUT"
 	case *GetStmt:
@@ -34,6 +38,26 @@ func StmtName(s Statement) string {
 		return "HTAB"
 	case *VTabStmt:
 		return "VTAB"
+	case *ModeStmt:
+		return "MODE"
+	case *ClsStmt:
+		return "CLS"
+	case *LocateStmt:
+		return "LOCATE"
+	case *InkStmt:
+		return "INK"
+	case *PenStmt:
+		return "PEN"
+	case *PaperStmt:
+		return "PAPER"
+	case *BorderStmt:
+		return "BORDER"
+	case *PlotStmt:
+		return "PLOT"
+	case *DrawStmt:
+		return "DRAW"
+	case *CallStmt:
+		return stmt.Name
 	default:
 		return "UNKNOWN"
 	}
@@ -3,7 +3,11 @@ package parser
 import (
 	"fmt"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
+	"basics/internal/constants"
+	"basics/internal/dialect"
 	"basics/internal/errors"
 	"basics/internal/logger"
 	"basics/internal/token"
@@ -14,13 +18,27 @@ type Parser struct {
 	pos      int
 	curr     token.Token
 	peek     token.Token
+	last     token.Token // dernier token consommé, hors séparateurs
 	errors   []*errors.Error
 	forStack []*ForStmt
+
+	// Dialecte du BASIC ciblé
+	dialect *dialect.Dialect
+
+	// Instructions et fonctions ajoutées par le programme hôte (Extend)
+	statements map[string]bool
+	functions  map[string]bool
 }
 
 func New(tokens []token.Token) *Parser {
+	return NewWithDialect(tokens, dialect.Default)
+}
+
+// NewWithDialect crée un parser pour un dialecte donné. Les tokens doivent
+// avoir été produits par un lexer du même dialecte.
+func NewWithDialect(tokens []token.Token, d *dialect.Dialect) *Parser {
 	logger.Info("Instanciate new parser")
-	p := &Parser{tokens: tokens}
+	p := &Parser{tokens: tokens, dialect: d}
 	p.curr = tokens[0]
 	if len(tokens) > 1 {
 		p.peek = tokens[1]
@@ -29,6 +47,9 @@ func New(tokens []token.Token) *Parser {
 }
 
 func (p *Parser) next() {
+	if p.curr.Type != token.COLON && p.curr.Type != token.EOL && p.curr.Type != token.EOF {
+		p.last = p.curr
+	}
 	p.pos++
 	p.curr = p.peek
 	if p.pos+1 < len(p.tokens) {
@@ -50,7 +71,7 @@ func (p *Parser) ParseProgram() (*Program, []*errors.Error) {
 
 		if p.curr.Type != token.LINENUM {
 			p.syntaxError("EXPECTED LINE NUMBER")
-			p.next()
+			p.skipToEndOfLine()
 			continue
 		}
 
@@ -78,25 +99,43 @@ func (p *Parser) ParseProgram() (*Program, []*errors.Error) {
 
 	// FOR non fermés → erreur
 	for _, f := range p.forStack {
-		p.errors = append(p.errors,
-			errors.NewSyntax(
-				f.LineNum,
-				f.Column,
-				"FOR",
-				"MISSING NEXT",
-			),
+		err := errors.NewSyntax(
+			f.LineNum,
+			f.Column,
+			"FOR",
+			"MISSING NEXT",
 		)
+		err.Hint = fmt.Sprintf("add NEXT %s", f.Var)
+		p.errors = append(p.errors, err)
 	}
 
 	return prog, p.errors
 }
 
+// ParseExpression analyse une expression seule, sans numéro de ligne
+// (conditions des points d'arrêt, évaluation dans le débogueur)
+func (p *Parser) ParseExpression() (Expression, []*errors.Error) {
+	expr := p.parseExpression(LOWEST)
+	if expr == nil {
+		if len(p.errors) == 0 {
+			p.syntaxError("INVALID EXPRESSION")
+		}
+		return nil, p.errors
+	}
+
+	if p.curr.Type != token.EOL && p.curr.Type != token.EOF {
+		p.syntaxError("SYNTAX ERROR")
+	}
+	return expr, p.errors
+}
+
 func (p *Parser) parseLine() *Line {
 	if p.curr.Type != token.LINENUM {
 		p.syntaxError("EXPECTED LINE NUMBER")
 		return nil
 	}
 
+	start := p.curr
 	num, _ := strconv.Atoi(p.curr.Literal)
 	line := &Line{Number: num}
 
@@ -104,6 +143,7 @@ func (p *Parser) parseLine() *Line {
 
 	// ✅ ligne vide autorisée (LINENUM seul)
 	if p.curr.Type == token.EOL || p.curr.Type == token.EOF {
+		line.SetRange(p.span(start))
 		if p.curr.Type == token.EOL {
 			p.next()
 		}
@@ -111,15 +151,28 @@ func (p *Parser) parseLine() *Line {
 	}
 
 	for {
+		errs := len(p.errors)
 		stmt := p.parseStatement(num)
 		line.Stmts = append(line.Stmts, stmt)
 
+		// après une erreur, reprise à l'instruction suivante : le reste
+		// de l'instruction ne produit pas d'autres erreurs
+		if len(p.errors) > errs {
+			p.skipToNextStatement()
+		}
+
 		if p.curr.Type != token.COLON {
 			break
 		}
 		p.next() // :
 	}
 
+	if p.curr.Type != token.EOL && p.curr.Type != token.EOF {
+		p.syntaxError("SYNTAX ERROR")
+		p.skipToEndOfLine()
+	}
+	line.SetRange(p.span(start))
+
 	if p.curr.Type == token.EOL {
 		p.next()
 	}
@@ -127,7 +180,15 @@ func (p *Parser) parseLine() *Line {
 	return line
 }
 
+// parseStatement analyse une instruction et note sa position
 func (p *Parser) parseStatement(lineNum int) Statement {
+	start := p.curr
+	stmt := p.parseStatementBody(lineNum)
+	p.setSpan(stmt, start)
+	return stmt
+}
+
+func (p *Parser) parseStatementBody(lineNum int) Statement {
 	// ELSE n'est jamais un statement valide
 	if p.curr.Type == token.KEYWORD && p.curr.Literal == "ELSE" {
 		return nil
@@ -144,6 +205,14 @@ func (p *Parser) parseStatement(lineNum int) Statement {
 			p.next()
 			return stmt
 
+		case "TRACE":
+			p.next()
+			return &TraceStmt{}
+
+		case "NOTRACE":
+			p.next()
+			return &NoTraceStmt{}
+
 		case "PRINT":
 			return p.parsePrint()
 
@@ -213,6 +282,15 @@ func (p *Parser) parseStatement(lineNum int) Statement {
 			p.next()
 			return &EndStmt{}
 
+		case "MODE", "CLS", "LOCATE", "INK", "PEN", "PAPER", "BORDER", "PLOT", "DRAW":
+			// instructions écran du Locomotive BASIC uniquement
+			if p.dialect.BasicType == constants.BASIC_AMS {
+				return p.parseScreenStatement()
+			}
+			p.syntaxError("UNKNOWN KEYWORD")
+			p.next()
+			return nil
+
 		default:
 			p.syntaxError("UNKNOWN KEYWORD")
 			p.next()
@@ -221,9 +299,14 @@ func (p *Parser) parseStatement(lineNum int) Statement {
 	}
 
 	if p.curr.Type == token.IDENT {
+		if name := p.extension(p.curr.Literal); p.statements[name] {
+			return p.parseCallStatement(name)
+		}
+
 		// IDENT doit être suivi de '='
 		if p.peek.Literal != "=" {
 			p.syntaxError("EXPECTED '='")
+			p.suggestKeyword(p.curr.Literal)
 			p.next()
 			return nil
 		}
@@ -248,6 +331,7 @@ func (p *Parser) parseInput() Statement {
 	// INPUT "string";
 	if p.curr.Type == token.STRING && p.peek.Type == token.SEMICOLON {
 		prompt = &StringLiteral{
+			Span:   tokenSpan(p.curr),
 			Value:  p.curr.Literal,
 			Line:   p.curr.Line,
 			Column: p.curr.Column,
@@ -266,6 +350,7 @@ func (p *Parser) parseInput() Statement {
 		}
 
 		vars = append(vars, &Identifier{
+			Span:   tokenSpan(p.curr),
 			Name:   p.curr.Literal,
 			Line:   p.curr.Line,
 			Column: p.curr.Column,
@@ -289,7 +374,7 @@ func (p *Parser) parseInput() Statement {
 	}
 }
 
-func (p *Parser) parseGet() *GetStmt {
+func (p *Parser) parseGet() Statement {
 	stmt := &GetStmt{}
 
 	p.next() // consomme GET
@@ -300,6 +385,7 @@ func (p *Parser) parseGet() *GetStmt {
 	}
 
 	stmt.Var = &Identifier{
+		Span:   tokenSpan(p.curr),
 		Name:   p.curr.Literal,
 		Line:   p.curr.Line,
 		Column: p.curr.Column,
@@ -454,16 +540,22 @@ func (p *Parser) parseNext(lineNum int) Statement {
 		return nil
 	}
 
-	name := p.curr.Literal
-	p.expect(token.IDENT)
-
 	// récupérer le FOR courant
 	top := p.forStack[len(p.forStack)-1]
 
-	if top.Var != name {
-		p.syntaxError(
+	nameTok := p.curr
+	name := p.curr.Literal
+	if !p.expect(token.IDENT) {
+		p.hint(fmt.Sprintf("did you mean NEXT %s?", top.Var))
+		return nil
+	}
+
+	// seuls les caractères significatifs comptent (FOR COUNT ... NEXT CO)
+	if p.dialect.SignificantName(top.Var) != p.dialect.SignificantName(name) {
+		p.syntaxErrorAt(nameTok,
 			fmt.Sprintf("MISMATCHED NEXT VARIABLE, expected '%s'", top.Var),
 		)
+		p.hint(fmt.Sprintf("did you mean NEXT %s?", top.Var))
 		return nil
 	}
 
@@ -487,6 +579,7 @@ func (p *Parser) parseIf(lineNum int) Statement {
 	}
 
 	if !p.expectKeyword("THEN") {
+		p.hint("add THEN after the condition")
 		return nil
 	}
 
@@ -515,8 +608,11 @@ func (p *Parser) parseIfBlock(lineNum int) []Statement {
 
 	// Cas spécial : THEN 40 → GOTO implicite
 	if p.curr.Type == token.NUMBER {
+		start := p.curr
 		expr := p.parseExpression(LOWEST)
-		stmts = append(stmts, &GotoStmt{Expr: expr})
+		stmt := &GotoStmt{Expr: expr}
+		p.setSpan(stmt, start)
+		stmts = append(stmts, stmt)
 		return stmts
 	}
 
@@ -547,8 +643,126 @@ func (p *Parser) parseIfBlock(lineNum int) []Statement {
 	return stmts
 }
 
+// parseScreenStatement analyse les instructions écran / graphique de l'Amstrad CPC
+func (p *Parser) parseScreenStatement() Statement {
+	kw := p.curr.Literal
+
+	switch kw {
+
+	case "CLS":
+		p.next()
+		return &ClsStmt{}
+
+	case "MODE":
+		args := p.parseArguments(kw, 1, 1)
+		if args == nil {
+			return nil
+		}
+		return &ModeStmt{Expr: args[0]}
+
+	case "PEN":
+		args := p.parseArguments(kw, 1, 1)
+		if args == nil {
+			return nil
+		}
+		return &PenStmt{Expr: args[0]}
+
+	case "PAPER":
+		args := p.parseArguments(kw, 1, 1)
+		if args == nil {
+			return nil
+		}
+		return &PaperStmt{Expr: args[0]}
+
+	case "LOCATE":
+		args := p.parseArguments(kw, 2, 2)
+		if args == nil {
+			return nil
+		}
+		return &LocateStmt{X: args[0], Y: args[1]}
+
+	case "INK":
+		args := p.parseArguments(kw, 2, 3)
+		if args == nil {
+			return nil
+		}
+		stmt := &InkStmt{Ink: args[0], Color1: args[1]}
+		if len(args) == 3 {
+			stmt.Color2 = args[2]
+		}
+		return stmt
+
+	case "BORDER":
+		args := p.parseArguments(kw, 1, 2)
+		if args == nil {
+			return nil
+		}
+		stmt := &BorderStmt{Color1: args[0]}
+		if len(args) == 2 {
+			stmt.Color2 = args[1]
+		}
+		return stmt
+
+	case "PLOT":
+		args := p.parseArguments(kw, 2, 3)
+		if args == nil {
+			return nil
+		}
+		stmt := &PlotStmt{X: args[0], Y: args[1]}
+		if len(args) == 3 {
+			stmt.Ink = args[2]
+		}
+		return stmt
+
+	case "DRAW":
+		args := p.parseArguments(kw, 2, 3)
+		if args == nil {
+			return nil
+		}
+		stmt := &DrawStmt{X: args[0], Y: args[1]}
+		if len(args) == 3 {
+			stmt.Ink = args[2]
+		}
+		return stmt
+	}
+
+	p.syntaxError("UNKNOWN KEYWORD")
+	p.next()
+	return nil
+}
+
+// parseArguments consomme le mot-clé courant puis une liste d'expressions
+// séparées par des virgules (entre min et max éléments)
+func (p *Parser) parseArguments(kw string, min, max int) []Expression {
+	p.next() // consommer le mot-clé
+
+	var args []Expression
+
+	for {
+		expr := p.parseExpression(LOWEST)
+		if expr == nil {
+			p.syntaxError(fmt.Sprintf("EXPECTED EXPRESSION AFTER %s", kw))
+			return nil
+		}
+		args = append(args, expr)
+
+		if p.curr.Type != token.COMMA {
+			break
+		}
+		p.next() // ,
+	}
+
+	if len(args) < min || len(args) > max {
+		p.syntaxError(fmt.Sprintf("WRONG NUMBER OF ARGUMENTS FOR %s", kw))
+		return nil
+	}
+
+	return args
+}
+
 func (p *Parser) parseExpression(precedence int) Expression {
 	var left Expression
+	start := p.curr
 
 	// --- prefix ---
 	switch p.curr.Type {
@@ -623,7 +837,7 @@ func (p *Parser) parseExpression(precedence int) Expression {
 				return nil
 			}
 
-			return &SgnExpr{
+			left = &SgnExpr{
 				Expr:   expr,
 				Line:   line,
 				Column: col,
@@ -650,6 +864,13 @@ func (p *Parser) parseExpression(precedence int) Expression {
 		p.next()
 
 	case token.IDENT:
+		if name := p.extension(p.curr.Literal); p.functions[name] {
+			left = p.parseCallExpr(name)
+			if left == nil {
+				return nil
+			}
+			break
+		}
 		left = &Identifier{
 			Name:   p.curr.Literal,
 			Line:   p.curr.Line,
@@ -684,6 +905,7 @@ func (p *Parser) parseExpression(precedence int) Expression {
 		left = p.parseExpression(LOWEST)
 
 		if !p.expect(token.RPAREN) {
+			p.hint("missing ')'")
 			return nil
 		}
 
@@ -691,6 +913,7 @@ func (p *Parser) parseExpression(precedence int) Expression {
 		p.syntaxError("INVALID EXPRESSION")
 		return nil
 	}
+	p.setSpan(left, start)
 
 	// --- infix ---
 	for p.curr.Type != token.EOL &&
@@ -710,6 +933,7 @@ func (p *Parser) parseExpression(precedence int) Expression {
 			Column: opTok.Column,
 			Token:  opTok.Literal,
 		}
+		p.setSpan(left, start)
 	}
 
 	return left
@@ -763,15 +987,86 @@ func (p *Parser) peekPrecedence() int {
 }
 
 func (p *Parser) syntaxError(msg string) {
+	tok := p.curr
+	if (tok.Type == token.EOL || tok.Type == token.EOF) && p.last.Line > 0 {
+		// le token EOL porte la ligne suivante : l'erreur est placée
+		// après le dernier token de la ligne
+		end := tokenSpan(p.last).End
+		tok = token.Token{Type: tok.Type, Line: end.Line, Column: end.Column}
+	}
+	p.syntaxErrorAt(tok, msg)
+}
+
+func (p *Parser) syntaxErrorAt(tok token.Token, msg string) {
 	err := errors.NewSyntax(
-		p.curr.Line,
-		p.curr.Column,
-		p.curr.Literal,
+		tok.Line,
+		tok.Column,
+		tok.Literal,
 		msg,
 	)
 	p.errors = append(p.errors, err)
 }
 
+// hint ajoute une suggestion à la dernière erreur
+func (p *Parser) hint(msg string) {
+	if n := len(p.errors); n > 0 {
+		p.errors[n-1].Hint = msg
+	}
+}
+
+// suggestKeyword propose l'instruction la plus proche d'un mot inconnu
+// (PRNT → PRINT)
+func (p *Parser) suggestKeyword(word string) {
+	best, dist := "", 3
+	for _, kw := range statementKeywords {
+		if !p.dialect.IsKeyword(kw) {
+			continue
+		}
+		if d := editDistance(strings.ToUpper(word), kw); d < dist && d < len(kw)/2 {
+			best, dist = kw, d
+		}
+	}
+	if best != "" {
+		p.hint(fmt.Sprintf("did you mean %s?", best))
+	}
+}
+
+// span retourne la zone qui va de start au dernier token consommé
+func (p *Parser) span(start token.Token) Span {
+	s := tokenSpan(start)
+	if end := tokenSpan(p.last).End; p.last.Line > start.Line ||
+		(p.last.Line == start.Line && end.Column > s.End.Column) {
+		s.End = end
+	}
+	return s
+}
+
+// setSpan note la position d'un nœud qui commence au token start
+func (p *Parser) setSpan(n any, start token.Token) {
+	if s, ok := n.(Spanned); ok {
+		s.SetRange(p.span(start))
+	}
+}
+
+// tokenSpan retourne la zone d'un token ; les guillemets font partie des
+// chaînes
+func tokenSpan(tok token.Token) Span {
+	width := utf8.RuneCountInString(tok.Literal)
+	if tok.Type == token.STRING {
+		width += 2
+	}
+	return Span{
+		Start: Position{Line: tok.Line, Column: tok.Column},
+		End:   Position{Line: tok.Line, Column: tok.Column + width},
+	}
+}
+
+func (p *Parser) skipToEndOfLine() {
+	for p.curr.Type != token.EOL && p.curr.Type != token.EOF {
+		p.next()
+	}
+}
+
 func (p *Parser) skipToNextStatement() {
 	for p.curr.Type != token.COLON &&
 		p.curr.Type != token.EOL &&
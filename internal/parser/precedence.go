@@ -24,3 +24,11 @@ var precedences = map[string]int{
 	"/":  PRODUCT,
 	"^":  POWER,
 }
+
+// Precedence retourne la priorité d'un opérateur infixe (LOWEST si inconnu)
+func Precedence(op string) int {
+	if prec, ok := precedences[op]; ok {
+		return prec
+	}
+	return LOWEST
+}
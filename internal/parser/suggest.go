@@ -0,0 +1,31 @@
+package parser
+
+// statementKeywords sont les instructions reconnues par le parser, pour
+// les suggestions (celles qui n'existent pas dans le dialecte sont ignorées)
+var statementKeywords = []string{
+	"PRINT", "INPUT", "GET", "LET", "FOR", "NEXT", "IF", "GOTO", "GOSUB",
+	"RETURN", "HTAB", "VTAB", "HOME", "TRACE", "NOTRACE", "END", "REM",
+	"MODE", "CLS", "LOCATE", "INK", "PEN", "PAPER", "BORDER", "PLOT", "DRAW",
+}
+
+// editDistance est la distance de Levenshtein entre deux mots
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
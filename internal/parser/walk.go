@@ -0,0 +1,132 @@
+package parser
+
+// Inspect parcourt l'AST en profondeur et appelle fn pour chaque nœud
+// (*Program, *Line, Statement ou Expression). Si fn retourne false, les
+// enfants du nœud ne sont pas visités.
+func Inspect(node any, fn func(node any) bool) {
+	if node == nil || !fn(node) {
+		return
+	}
+
+	switch n := node.(type) {
+
+	case *Program:
+		for _, line := range n.Lines {
+			Inspect(line, fn)
+		}
+
+	case *Line:
+		inspectStmts(n.Stmts, fn)
+
+	// -------- Statements --------
+
+	case *PrintStmt:
+		inspectExprs(n.Exprs, fn)
+
+	case *InputStmt:
+		if n.Prompt != nil {
+			Inspect(n.Prompt, fn)
+		}
+		for _, v := range n.Vars {
+			Inspect(v, fn)
+		}
+
+	case *GetStmt:
+		if n.Var != nil {
+			Inspect(n.Var, fn)
+		}
+
+	case *LetStmt:
+		inspectExprs([]Expression{n.Value}, fn)
+
+	case *ForStmt:
+		inspectExprs([]Expression{n.Start, n.End, n.Step}, fn)
+
+	case *HTabStmt:
+		inspectExprs([]Expression{n.Expr}, fn)
+
+	case *VTabStmt:
+		inspectExprs([]Expression{n.Expr}, fn)
+
+	case *GotoStmt:
+		inspectExprs([]Expression{n.Expr}, fn)
+
+	case *GosubStmt:
+		inspectExprs([]Expression{n.Expr}, fn)
+
+	case *IfStmt:
+		inspectExprs([]Expression{n.Cond}, fn)
+		inspectStmts(n.Then, fn)
+		inspectStmts(n.Else, fn)
+
+	case *IfJumpStmt:
+		inspectExprs([]Expression{n.Cond}, fn)
+
+	case *ModeStmt:
+		inspectExprs([]Expression{n.Expr}, fn)
+
+	case *LocateStmt:
+		inspectExprs([]Expression{n.X, n.Y}, fn)
+
+	case *InkStmt:
+		inspectExprs([]Expression{n.Ink, n.Color1, n.Color2}, fn)
+
+	case *PenStmt:
+		inspectExprs([]Expression{n.Expr}, fn)
+
+	case *PaperStmt:
+		inspectExprs([]Expression{n.Expr}, fn)
+
+	case *BorderStmt:
+		inspectExprs([]Expression{n.Color1, n.Color2}, fn)
+
+	case *PlotStmt:
+		inspectExprs([]Expression{n.X, n.Y, n.Ink}, fn)
+
+	case *DrawStmt:
+		inspectExprs([]Expression{n.X, n.Y, n.Ink}, fn)
+
+	case *CallStmt:
+		inspectExprs(n.Args, fn)
+
+	// -------- Expressions --------
+
+	case *PrefixExpr:
+		inspectExprs([]Expression{n.Right}, fn)
+
+	case *InfixExpr:
+		inspectExprs([]Expression{n.Left, n.Right}, fn)
+
+	case *IntExpr:
+		inspectExprs([]Expression{n.Expr}, fn)
+
+	case *AbsExpr:
+		inspectExprs([]Expression{n.Expr}, fn)
+
+	case *SgnExpr:
+		inspectExprs([]Expression{n.Expr}, fn)
+
+	case *CallExpr:
+		inspectExprs(n.Args, fn)
+	}
+}
+
+// inspectStmts visite une liste d'instructions en ignorant les nil
+// (REM, instructions en erreur)
+func inspectStmts(stmts []Statement, fn func(node any) bool) {
+	for _, stmt := range stmts {
+		if stmt != nil {
+			Inspect(stmt, fn)
+		}
+	}
+}
+
+// inspectExprs visite une liste d'expressions en ignorant les nil
+// (arguments optionnels absents)
+func inspectExprs(exprs []Expression, fn func(node any) bool) {
+	for _, expr := range exprs {
+		if expr != nil {
+			Inspect(expr, fn)
+		}
+	}
+}
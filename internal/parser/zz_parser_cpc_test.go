@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"testing"
+
+	"basics/internal/dialect"
+	"basics/internal/lexer"
+	"basics/testutils"
+)
+
+func parseLocomotive(t *testing.T, src string) *Program {
+	t.Helper()
+
+	p := NewWithDialect(lexer.LexDialect(src, dialect.Locomotive), dialect.Locomotive)
+	prog, errs := p.ParseProgram()
+	testutils.Equal(t, "no parser errors", len(errs), 0)
+
+	return prog
+}
+
+func TestParse_CPC_ScreenStatements(t *testing.T) {
+	prog := parseLocomotive(t, `
+10 MODE 0:CLS
+20 INK 1,24:INK 2,6,26
+30 PEN 1:PAPER 0
+40 BORDER 3:BORDER 1,2
+50 LOCATE 10,5
+60 PLOT 10,20:PLOT 10,20,3
+70 DRAW 100,200:DRAW 100,200,1
+`)
+
+	testutils.Equal(t, "line count", len(prog.Lines), 7)
+
+	mode, ok := prog.Lines[0].Stmts[0].(*ModeStmt)
+	testutils.True(t, "line 10 stmt 0 is ModeStmt", ok)
+	testutils.Equal(t, "MODE arg", mode.Expr.(*NumberLiteral).Value, 0.0)
+
+	_, ok = prog.Lines[0].Stmts[1].(*ClsStmt)
+	testutils.True(t, "line 10 stmt 1 is ClsStmt", ok)
+
+	ink, ok := prog.Lines[1].Stmts[0].(*InkStmt)
+	testutils.True(t, "line 20 stmt 0 is InkStmt", ok)
+	testutils.True(t, "INK without flashing colour", ink.Color2 == nil)
+
+	ink, ok = prog.Lines[1].Stmts[1].(*InkStmt)
+	testutils.True(t, "line 20 stmt 1 is InkStmt", ok)
+	testutils.Equal(t, "INK flashing colour", ink.Color2.(*NumberLiteral).Value, 26.0)
+
+	_, ok = prog.Lines[2].Stmts[0].(*PenStmt)
+	testutils.True(t, "line 30 stmt 0 is PenStmt", ok)
+
+	_, ok = prog.Lines[2].Stmts[1].(*PaperStmt)
+	testutils.True(t, "line 30 stmt 1 is PaperStmt", ok)
+
+	border, ok := prog.Lines[3].Stmts[0].(*BorderStmt)
+	testutils.True(t, "line 40 stmt 0 is BorderStmt", ok)
+	testutils.True(t, "BORDER without flashing colour", border.Color2 == nil)
+
+	border, ok = prog.Lines[3].Stmts[1].(*BorderStmt)
+	testutils.True(t, "line 40 stmt 1 is BorderStmt", ok)
+	testutils.True(t, "BORDER with flashing colour", border.Color2 != nil)
+
+	locate, ok := prog.Lines[4].Stmts[0].(*LocateStmt)
+	testutils.True(t, "line 50 is LocateStmt", ok)
+	testutils.Equal(t, "LOCATE x", locate.X.(*NumberLiteral).Value, 10.0)
+	testutils.Equal(t, "LOCATE y", locate.Y.(*NumberLiteral).Value, 5.0)
+
+	plot, ok := prog.Lines[5].Stmts[0].(*PlotStmt)
+	testutils.True(t, "line 60 stmt 0 is PlotStmt", ok)
+	testutils.True(t, "PLOT without ink", plot.Ink == nil)
+
+	plot, ok = prog.Lines[5].Stmts[1].(*PlotStmt)
+	testutils.True(t, "line 60 stmt 1 is PlotStmt", ok)
+	testutils.True(t, "PLOT with ink", plot.Ink != nil)
+
+	draw, ok := prog.Lines[6].Stmts[1].(*DrawStmt)
+	testutils.True(t, "line 70 stmt 1 is DrawStmt", ok)
+	testutils.Equal(t, "DRAW ink", draw.Ink.(*NumberLiteral).Value, 1.0)
+}
+
+func TestParse_CPC_ArgumentErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{"MODE without argument", "10 MODE\n"},
+		{"INK with one argument", "10 INK 1\n"},
+		{"INK with four arguments", "10 INK 1,2,3,4\n"},
+		{"LOCATE with one argument", "10 LOCATE 5\n"},
+		{"BORDER with three arguments", "10 BORDER 1,2,3\n"},
+		{"DRAW with one argument", "10 DRAW 5\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewWithDialect(lexer.LexDialect(tt.source, dialect.Locomotive), dialect.Locomotive)
+			_, errs := p.ParseProgram()
+			testutils.True(t, "expected parser errors", len(errs) > 0)
+		})
+	}
+}
+
+func TestParse_CPC_StatementsNeedLocomotive(t *testing.T) {
+	// PLOT est un mot-clé Applesoft, mais pas l'instruction CPC
+	src := "10 PLOT 10,20\n"
+
+	p := New(lexer.Lex(src))
+	_, errs := p.ParseProgram()
+	testutils.True(t, "Applesoft rejects CPC PLOT", len(errs) > 0)
+
+	p = NewWithDialect(lexer.LexDialect(src, dialect.Locomotive), dialect.Locomotive)
+	_, errs = p.ParseProgram()
+	testutils.Equal(t, "Locomotive accepts PLOT", len(errs), 0)
+}
+
+func TestStmtName_CPC(t *testing.T) {
+	tests := []struct {
+		stmt Statement
+		want string
+	}{
+		{&ModeStmt{}, "MODE"},
+		{&ClsStmt{}, "CLS"},
+		{&LocateStmt{}, "LOCATE"},
+		{&InkStmt{}, "INK"},
+		{&PenStmt{}, "PEN"},
+		{&PaperStmt{}, "PAPER"},
+		{&BorderStmt{}, "BORDER"},
+		{&PlotStmt{}, "PLOT"},
+		{&DrawStmt{}, "DRAW"},
+	}
+
+	for _, tt := range tests {
+		testutils.Equal(t, "StmtName", StmtName(tt.stmt), tt.want)
+	}
+}
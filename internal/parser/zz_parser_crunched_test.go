@@ -0,0 +1,28 @@
+package parser
+
+import (
+	"testing"
+
+	"basics/internal/dialect"
+	"basics/internal/lexer"
+	"basics/testutils"
+)
+
+func TestParse_CrunchedSource(t *testing.T) {
+	d := dialect.Applesoft.WithCrunched()
+	src := "10FORI=1TO10STEP2:PRINTI:NEXTI\n20IFI>5THENPRINT\"DONE\"\n30HOME:VTAB12:HTAB10:GOTO10\n"
+
+	prog, errs := NewWithDialect(lexer.LexDialect(src, d), d).ParseProgram()
+	testutils.Equal(t, "no parser errors", len(errs), 0)
+	testutils.Equal(t, "line count", len(prog.Lines), 3)
+
+	f, ok := prog.Lines[0].Stmts[0].(*ForStmt)
+	testutils.True(t, "line 10 is FOR", ok)
+	testutils.Equal(t, "FOR variable", f.Var, "I")
+	testutils.True(t, "FOR has STEP", f.Step != nil)
+
+	_, ok = prog.Lines[1].Stmts[0].(*IfStmt)
+	testutils.True(t, "line 20 is IF", ok)
+
+	testutils.Equal(t, "line 30 statements", len(prog.Lines[2].Stmts), 4)
+}
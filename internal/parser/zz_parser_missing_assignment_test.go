@@ -35,7 +35,7 @@ func TestParse_LetMissingEqual(t *testing.T) {
 			p := New(tokens)
 			_, errs := p.ParseProgram()
 
-			testutils.Equal(t, "one error", len(errs), 2)
+			testutils.Equal(t, "one error", len(errs), 1)
 			testutils.Equal(t, "error message", errs[0].Msg, "EXPECTED '='")
 			testutils.Equal(t, "error line", errs[0].Line, 3)
 		})
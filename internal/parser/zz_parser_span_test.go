@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"testing"
+
+	"basics/internal/lexer"
+	"basics/testutils"
+)
+
+func TestParser_Spans(t *testing.T) {
+	src := "10 PRINT \"A\"; B + 1 : GOTO 10\n20 IF A THEN 10\n"
+	prog, errs := New(lexer.Lex(src)).ParseProgram()
+	testutils.Equal(t, "no errors", len(errs), 0)
+
+	span := func(l1, c1, l2, c2 int) Span {
+		return Span{Start: Position{l1, c1}, End: Position{l2, c2}}
+	}
+
+	line := prog.Lines[0]
+	testutils.Equal(t, "line", line.Range(), span(1, 1, 1, 30))
+
+	print := line.Stmts[0].(*PrintStmt)
+	testutils.Equal(t, "PRINT", print.Range(), span(1, 4, 1, 20))
+	testutils.Equal(t, "string", print.Exprs[0].(*StringLiteral).Range(), span(1, 10, 1, 13))
+	testutils.Equal(t, "B + 1", print.Exprs[1].(*InfixExpr).Range(), span(1, 15, 1, 20))
+	testutils.Equal(t, "GOTO", line.Stmts[1].(*GotoStmt).Range(), span(1, 23, 1, 30))
+
+	// THEN 10 est un GOTO implicite
+	ifStmt := prog.Lines[1].Stmts[0].(*IfStmt)
+	testutils.Equal(t, "IF", ifStmt.Range(), span(2, 4, 2, 16))
+	testutils.Equal(t, "THEN 10", ifStmt.Then[0].(*GotoStmt).Range(), span(2, 14, 2, 16))
+}
+
+func TestParser_Recovery(t *testing.T) {
+	src := "10 PRNT \"A\" : PRINT 1\n20 FOR I = 1 TO 3\n30 NEXT J\n40 IF A PRINT 2 3\n50 A = (1 + 2\n"
+	_, errs := New(lexer.Lex(src)).ParseProgram()
+
+	// une erreur par instruction fautive, sans erreurs en cascade
+	testutils.Equal(t, "errors", len(errs), 5)
+
+	testutils.Equal(t, "unknown keyword", errs[0].Msg, "EXPECTED '='")
+	testutils.Equal(t, "keyword hint", errs[0].Hint, "did you mean PRINT?")
+
+	testutils.Equal(t, "NEXT", errs[1].Msg, "MISMATCHED NEXT VARIABLE, expected 'I'")
+	testutils.Equal(t, "NEXT position", errs[1].Column, 9)
+	testutils.Equal(t, "NEXT hint", errs[1].Hint, "did you mean NEXT I?")
+
+	testutils.Equal(t, "THEN hint", errs[2].Hint, "add THEN after the condition")
+
+	// la parenthèse manquante est signalée en fin de ligne, pas sur la
+	// ligne suivante
+	testutils.Equal(t, "parenthesis line", errs[3].Line, 5)
+	testutils.Equal(t, "parenthesis column", errs[3].Column, 14)
+	testutils.Equal(t, "parenthesis hint", errs[3].Hint, "missing ')'")
+
+	testutils.Equal(t, "missing NEXT", errs[4].Msg, "MISSING NEXT")
+	testutils.Equal(t, "missing NEXT hint", errs[4].Hint, "add NEXT I")
+}
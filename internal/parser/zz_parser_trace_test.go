@@ -0,0 +1,30 @@
+package parser
+
+import (
+	"testing"
+
+	"basics/internal/lexer"
+	"basics/testutils"
+)
+
+func TestParse_TRACE_NOTRACE(t *testing.T) {
+	source := `
+10 TRACE : NOTRACE
+20 IF A = 1 THEN TRACE
+`
+
+	prog, errs := New(lexer.Lex(source)).ParseProgram()
+
+	testutils.Equal(t, "no parser errors", len(errs), 0)
+	testutils.Equal(t, "two lines parsed", len(prog.Lines), 2)
+
+	_, ok := prog.Lines[0].Stmts[0].(*TraceStmt)
+	testutils.True(t, "statement is TraceStmt", ok)
+	_, ok = prog.Lines[0].Stmts[1].(*NoTraceStmt)
+	testutils.True(t, "statement is NoTraceStmt", ok)
+
+	ifStmt, ok := prog.Lines[1].Stmts[0].(*IfStmt)
+	testutils.True(t, "statement is IfStmt", ok)
+	_, ok = ifStmt.Then[0].(*TraceStmt)
+	testutils.True(t, "THEN TRACE", ok)
+}
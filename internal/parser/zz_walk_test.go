@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"testing"
+
+	"basics/internal/lexer"
+	"basics/testutils"
+)
+
+func TestInspect_VisitsNestedNodes(t *testing.T) {
+	src := `
+10 A = 1 + ABS(B)
+20 IF A > 1 THEN PRINT C ELSE INPUT D
+30 FOR I = 1 TO N STEP S
+40 NEXT I
+`
+	prog, errs := New(lexer.Lex(src)).ParseProgram()
+	testutils.Equal(t, "no parser errors", len(errs), 0)
+
+	var idents []string
+	lines := 0
+	Inspect(prog, func(node any) bool {
+		switch n := node.(type) {
+		case *Line:
+			lines++
+		case *Identifier:
+			idents = append(idents, n.Name)
+		}
+		return true
+	})
+
+	testutils.Equal(t, "lines visited", lines, 4)
+
+	want := []string{"B", "A", "C", "D", "N", "S"}
+	testutils.Equal(t, "identifier count", len(idents), len(want))
+	for i := range want {
+		testutils.Equal(t, "identifier", idents[i], want[i])
+	}
+}
+
+func TestInspect_StopsDescending(t *testing.T) {
+	prog, _ := New(lexer.Lex("10 PRINT A + B\n")).ParseProgram()
+
+	count := 0
+	Inspect(prog, func(node any) bool {
+		count++
+		_, isPrint := node.(*PrintStmt)
+		return !isPrint
+	})
+
+	// Program, Line, PrintStmt
+	testutils.Equal(t, "visited nodes", count, 3)
+}
+
+func TestParseNext_SignificantName(t *testing.T) {
+	src := "10 FOR COUNT = 1 TO 3\n20 NEXT CO\n"
+
+	_, errs := New(lexer.Lex(src)).ParseProgram()
+	testutils.Equal(t, "Applesoft: NEXT CO closes FOR COUNT", len(errs), 0)
+}
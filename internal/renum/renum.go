@@ -0,0 +1,152 @@
+package renum
+
+import (
+	"fmt"
+	"strconv"
+
+	"basics/internal/applesoft"
+	"basics/internal/parser"
+)
+
+// Options décrit une renumérotation : les lignes From à To (To = 0 :
+// jusqu'à la fin) reçoivent les numéros Start, Start+Step, ...
+type Options struct {
+	Start int
+	Step  int
+	From  int
+	To    int
+}
+
+// Result décrit une renumérotation effectuée
+type Result struct {
+	// Lines associe l'ancien numéro de chaque ligne renumérotée au nouveau
+	Lines map[int]int
+
+	// Computed liste les lignes (nouveaux numéros) contenant un GOTO ou un
+	// GOSUB calculé (GOTO A*10), qui ne peut pas être renuméroté
+	Computed []int
+}
+
+// Renumber renumérote le programme et réécrit les numéros de ligne cités
+// par GOTO, GOSUB et THEN. Le programme n'est pas modifié en cas d'erreur :
+// cible inexistante, numéros hors limites ou qui chevauchent les lignes
+// non renumérotées. IfJumpStmt contient un indice d'instruction et non un
+// numéro de ligne : il n'est pas concerné.
+func Renumber(prog *parser.Program, opts Options) (*Result, error) {
+	if opts.Start < 0 || opts.Step <= 0 {
+		return nil, fmt.Errorf("ILLEGAL QUANTITY: START %d, STEP %d", opts.Start, opts.Step)
+	}
+	if opts.To > 0 && opts.To < opts.From {
+		return nil, fmt.Errorf("ILLEGAL QUANTITY: FROM %d, TO %d", opts.From, opts.To)
+	}
+
+	// lignes existantes : toute cible constante doit en faire partie
+	exists := make(map[int]bool, len(prog.Lines))
+	for _, line := range prog.Lines {
+		exists[line.Number] = true
+	}
+
+	res := &Result{Lines: map[int]int{}}
+	prev := -1 // dernière ligne (nouveau numéro) déjà placée
+	number := opts.Start
+
+	for _, line := range prog.Lines {
+		if !opts.selects(line.Number) {
+			if line.Number <= prev {
+				return nil, fmt.Errorf("RENUMBERED LINES WOULD OVERLAP LINE %d", line.Number)
+			}
+			prev = line.Number
+			continue
+		}
+
+		if number <= prev {
+			return nil, fmt.Errorf("RENUMBERED LINES WOULD OVERLAP LINE %d", prev)
+		}
+		if number > applesoft.MaxLineNumber {
+			return nil, fmt.Errorf("LINE NUMBER %d TOO LARGE", number)
+		}
+		res.Lines[line.Number] = number
+		prev = number
+		number += opts.Step
+	}
+
+	// cibles : vérifiées avant toute modification
+	var targets []*parser.NumberLiteral
+	for _, line := range prog.Lines {
+		var err error
+		parser.Inspect(line, func(node any) bool {
+			var expr parser.Expression
+			switch n := node.(type) {
+			case *parser.GotoStmt:
+				expr = n.Expr
+			case *parser.GosubStmt:
+				expr = n.Expr
+			default:
+				return true
+			}
+
+			lit, ok := expr.(*parser.NumberLiteral)
+			if !ok {
+				res.Computed = append(res.Computed, line.Number)
+				return true
+			}
+			if target := int(lit.Value); !exists[target] && err == nil {
+				err = fmt.Errorf("UNDEFINED LINE %d IN %d", target, line.Number)
+			}
+			targets = append(targets, lit)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// réécriture
+	for _, lit := range targets {
+		if n, ok := res.Lines[int(lit.Value)]; ok {
+			lit.Value = float64(n)
+			lit.Token = strconv.Itoa(n)
+		}
+	}
+	for _, line := range prog.Lines {
+		parser.Inspect(line, func(node any) bool {
+			switch n := node.(type) {
+			case *parser.ForStmt:
+				n.LineNum = res.renamed(n.LineNum)
+			case *parser.NextStmt:
+				n.ForLineNum = res.renamed(n.ForLineNum)
+			}
+			return true
+		})
+	}
+	for _, line := range prog.Lines {
+		line.Number = res.renamed(line.Number)
+	}
+	for i, number := range res.Computed {
+		res.Computed[i] = res.renamed(number)
+	}
+
+	return res, nil
+}
+
+// selects indique si la ligne fait partie de la plage renumérotée
+func (o Options) selects(number int) bool {
+	return number >= o.From && (o.To <= 0 || number <= o.To)
+}
+
+// renamed retourne le nouveau numéro d'une ligne
+func (r *Result) renamed(number int) int {
+	if n, ok := r.Lines[number]; ok {
+		return n
+	}
+	return number
+}
+
+// Remarks renumérote les commentaires relevés par unparse.Remarks
+func (r *Result) Remarks(remarks map[int]string) map[int]string {
+	out := make(map[int]string, len(remarks))
+	for number, text := range remarks {
+		out[r.renamed(number)] = text
+	}
+	return out
+}
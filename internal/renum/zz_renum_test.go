@@ -0,0 +1,92 @@
+package renum
+
+import (
+	"testing"
+
+	"basics/internal/lexer"
+	"basics/internal/parser"
+	"basics/internal/unparse"
+	"basics/testutils"
+)
+
+func parse(t *testing.T, src string) *parser.Program {
+	t.Helper()
+
+	prog, errs := parser.New(lexer.Lex(src)).ParseProgram()
+	testutils.Equal(t, "no parser errors", len(errs), 0)
+	return prog
+}
+
+const source = `1 GOSUB 7
+2 FOR I = 1 TO 3: IF I = 2 THEN 5
+3 NEXT I
+5 IF I > 3 THEN 2 ELSE GOSUB 7
+6 END
+7 PRINT I: RETURN
+`
+
+func TestRenumber(t *testing.T) {
+	prog := parse(t, source)
+
+	res, err := Renumber(prog, Options{Start: 100, Step: 10})
+	testutils.True(t, "renumbered", err == nil)
+	testutils.Equal(t, "listing", unparse.Program(prog), `100 GOSUB 150
+110 FOR I = 1 TO 3: IF I=2 THEN 130
+120 NEXT I
+130 IF I>3 THEN 110 ELSE GOSUB 150
+140 END
+150 PRINT I: RETURN
+`)
+	testutils.Equal(t, "mapping", res.Lines[7], 150)
+
+	next := prog.Lines[2].Stmts[0].(*parser.NextStmt)
+	testutils.Equal(t, "NEXT follows its FOR", next.ForLineNum, 110)
+}
+
+func TestRenumber_Range(t *testing.T) {
+	prog := parse(t, source)
+
+	_, err := Renumber(prog, Options{Start: 3, Step: 1, From: 3, To: 5})
+	testutils.True(t, "renumbered", err == nil)
+	testutils.Equal(t, "listing", unparse.Program(prog), `1 GOSUB 7
+2 FOR I = 1 TO 3: IF I=2 THEN 4
+3 NEXT I
+4 IF I>3 THEN 2 ELSE GOSUB 7
+6 END
+7 PRINT I: RETURN
+`)
+}
+
+func TestRenumber_Refused(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		opts Options
+	}{
+		{"undefined target", "10 GOTO 99\n20 END\n", Options{Start: 10, Step: 10}},
+		{"overlap", "10 END\n20 END\n30 END\n", Options{Start: 25, Step: 10, From: 10, To: 10}},
+		{"too large", "10 END\n20 END\n", Options{Start: 63990, Step: 10}},
+		{"zero step", "10 END\n", Options{Start: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prog := parse(t, tt.src)
+			before := unparse.Program(prog)
+
+			_, err := Renumber(prog, tt.opts)
+			testutils.True(t, "refused", err != nil)
+			testutils.Equal(t, "program untouched", unparse.Program(prog), before)
+		})
+	}
+}
+
+func TestRenumber_ComputedAndRemarks(t *testing.T) {
+	prog := parse(t, "10 A = 30: GOTO A\n20 REM X\n30 END\n")
+
+	res, err := Renumber(prog, Options{Start: 1, Step: 1})
+	testutils.True(t, "renumbered", err == nil)
+	testutils.Equal(t, "computed GOTO reported", len(res.Computed), 1)
+	testutils.Equal(t, "computed line", res.Computed[0], 1)
+	testutils.Equal(t, "remark follows its line", res.Remarks(map[int]string{20: "X"})[2], "X")
+}
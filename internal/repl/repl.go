@@ -0,0 +1,244 @@
+// Package repl implémente le mode immédiat : une ligne numérotée ajoute,
+// remplace ou efface une ligne du programme en mémoire ; les commandes
+// LIST, RUN, NEW, RENUM, LOAD et SAVE l'exploitent.
+package repl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"basics/internal/constants"
+	"basics/internal/dialect"
+	"basics/internal/input"
+	"basics/internal/interpreter"
+	"basics/internal/lexer"
+	"basics/internal/machines"
+	"basics/internal/parser"
+	"basics/internal/renum"
+	"basics/internal/token"
+	"basics/internal/unparse"
+)
+
+// Session est une session du mode immédiat
+type Session struct {
+	in      *bufio.Reader // commandes, puis saisies de INPUT pendant RUN
+	out     io.Writer
+	dialect *dialect.Dialect
+
+	lines    map[int]string // texte de chaque ligne, numéro compris
+	filename string         // fichier de LOAD, repris par SAVE
+}
+
+func New(in io.Reader, out io.Writer, d *dialect.Dialect) *Session {
+	return &Session{
+		in:      bufio.NewReader(in),
+		out:     out,
+		dialect: d,
+		lines:   map[int]string{},
+	}
+}
+
+// Loop lit et exécute les commandes jusqu'à la fin de l'entrée
+func (s *Session) Loop() {
+	for {
+		fmt.Fprint(s.out, "]")
+		text, err := s.in.ReadString('\n')
+		if text = strings.TrimSpace(text); text != "" {
+			if err := s.Exec(text); err != nil {
+				fmt.Fprintf(s.out, "?%v\n", err)
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(s.out)
+			return
+		}
+	}
+}
+
+// Exec exécute une ligne tapée en mode immédiat
+func (s *Session) Exec(text string) error {
+	if unicode.IsDigit(rune(text[0])) {
+		return s.enter(text)
+	}
+
+	cmd, args, _ := strings.Cut(text, " ")
+	args = strings.TrimSpace(args)
+	switch strings.ToUpper(cmd) {
+	case "LIST":
+		fmt.Fprint(s.out, s.listing())
+	case "RUN":
+		return s.run()
+	case "NEW":
+		s.lines = map[int]string{}
+	case "RENUM":
+		return s.renum(args)
+	case "LOAD":
+		return s.Load(unquote(args))
+	case "SAVE":
+		return s.save(unquote(args))
+	default:
+		return fmt.Errorf("SYNTAX ERROR")
+	}
+	return nil
+}
+
+// Load remplace le programme par le contenu d'un fichier
+func (s *Session) Load(filename string) error {
+	if filename == "" {
+		return fmt.Errorf("SYNTAX ERROR")
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("FILE NOT FOUND")
+	}
+	s.set(string(data))
+	s.filename = filename
+	return nil
+}
+
+// save écrit le programme ; sans nom, dans le fichier chargé
+func (s *Session) save(filename string) error {
+	if filename == "" {
+		filename = s.filename
+	}
+	if filename == "" {
+		return fmt.Errorf("SYNTAX ERROR")
+	}
+	if err := os.WriteFile(filename, []byte(s.listing()), 0o644); err != nil {
+		return fmt.Errorf("I/O ERROR")
+	}
+	s.filename = filename
+	return nil
+}
+
+// enter ajoute ou remplace une ligne ; un numéro seul efface la ligne
+func (s *Session) enter(text string) error {
+	end := strings.IndexFunc(text, func(r rune) bool { return !unicode.IsDigit(r) })
+	if end < 0 {
+		end = len(text)
+	}
+	number, err := strconv.Atoi(text[:end])
+	if err != nil || number > 63999 {
+		return fmt.Errorf("SYNTAX ERROR")
+	}
+
+	if rest := strings.TrimSpace(text[end:]); rest != "" {
+		s.lines[number] = strconv.Itoa(number) + " " + rest
+	} else {
+		delete(s.lines, number)
+	}
+	return nil
+}
+
+// set remplace le programme par un listing
+func (s *Session) set(listing string) {
+	s.lines = map[int]string{}
+	for _, line := range strings.Split(listing, "\n") {
+		if line = strings.TrimSpace(line); line != "" && unicode.IsDigit(rune(line[0])) {
+			_ = s.enter(line)
+		}
+	}
+}
+
+// listing retourne le programme dans l'ordre des numéros de ligne
+func (s *Session) listing() string {
+	numbers := make([]int, 0, len(s.lines))
+	for n := range s.lines {
+		numbers = append(numbers, n)
+	}
+	slices.Sort(numbers)
+
+	var sb strings.Builder
+	for _, n := range numbers {
+		sb.WriteString(s.lines[n])
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// parse analyse le programme en mémoire
+func (s *Session) parse() (string, []token.Token, *parser.Program, error) {
+	src := s.listing()
+	tokens, err := lexer.Scan(src, s.dialect)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("SYNTAX ERROR")
+	}
+	prog, errs := parser.NewWithDialect(tokens, s.dialect).ParseProgram()
+	if len(errs) > 0 {
+		return "", nil, nil, errs[0]
+	}
+	return src, tokens, prog, nil
+}
+
+// run exécute le programme ; ses INPUT lisent l'entrée de la session
+func (s *Session) run() error {
+	_, _, prog, err := s.parse()
+	if err != nil {
+		return err
+	}
+
+	rt, err := machines.NewRuntime(constants.BASIC_TTY)
+	if err != nil {
+		return err
+	}
+	rt.Input = input.NewTTYInput(s.in, s.out)
+	rt.SetOutput(s.out)
+	// la cause d'un arrêt est affichée par le runtime
+	_ = interpreter.New(rt).RunContext(context.Background(), prog)
+	return nil
+}
+
+// renum implémente RENUM [nouveau][,[ancien][,pas]] (syntaxe Locomotive
+// BASIC) : les lignes à partir de l'ancien numéro reçoivent les numéros
+// nouveau, nouveau+pas... (10 et 10 par défaut)
+func (s *Session) renum(args string) error {
+	opts := renum.Options{Start: 10, Step: 10}
+	if args != "" {
+		fields := strings.Split(args, ",")
+		if len(fields) > 3 {
+			return fmt.Errorf("SYNTAX ERROR")
+		}
+		targets := []*int{&opts.Start, &opts.From, &opts.Step}
+		for k, field := range fields {
+			if field = strings.TrimSpace(field); field == "" {
+				continue
+			}
+			n, err := strconv.Atoi(field)
+			if err != nil {
+				return fmt.Errorf("SYNTAX ERROR")
+			}
+			*targets[k] = n
+		}
+	}
+
+	src, tokens, prog, err := s.parse()
+	if err != nil {
+		return err
+	}
+	res, err := renum.Renumber(prog, opts)
+	if err != nil {
+		return err
+	}
+	for _, line := range res.Computed {
+		fmt.Fprintf(s.out, "?COMPUTED GOTO OR GOSUB NOT RENUMBERED IN %d\n", line)
+	}
+
+	out, err := unparse.Format(prog, s.dialect, unparse.Options{Remarks: res.Remarks(unparse.Remarks(src, tokens))})
+	if err != nil {
+		return err
+	}
+	s.set(out)
+	return nil
+}
+
+// unquote retire les guillemets d'un nom de fichier
+func unquote(name string) string {
+	return strings.Trim(name, `"`)
+}
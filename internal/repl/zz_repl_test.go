@@ -0,0 +1,61 @@
+package repl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"basics/internal/dialect"
+	"basics/testutils"
+)
+
+// session exécute les commandes tapées en mode immédiat et retourne la
+// sortie
+func session(t *testing.T, commands string) string {
+	t.Helper()
+
+	var out strings.Builder
+	New(strings.NewReader(commands), &out, dialect.Applesoft).Loop()
+	return out.String()
+}
+
+func TestSession_Lines(t *testing.T) {
+	out := session(t, "20 PRINT 2\n10 PRINT 1\n30 PRINT 3\n30\n10 PRINT \"ONE\"\nLIST\n")
+	testutils.Equal(t, "listing", out, "]]]]]]10 PRINT \"ONE\"\n20 PRINT 2\n]\n")
+}
+
+func TestSession_Run(t *testing.T) {
+	out := session(t, "10 INPUT A\n20 PRINT A * 2\nRUN\n21\nRUN\n")
+	testutils.Equal(t, "output", out, "]]]? 42\n]? ⚠️ END OF INPUT IN 10 ()\n]\n")
+}
+
+func TestSession_Renum(t *testing.T) {
+	src := "5 REM LOOP\n7 GOSUB 12\n9 GOTO 7\n12 PRINT \"HI\": RETURN\n"
+
+	out := session(t, src+"RENUM\nLIST\n")
+	testutils.True(t, "default start and step: "+out, strings.Contains(out,
+		"10 REM LOOP\n20 GOSUB 40\n30 GOTO 20\n40 PRINT \"HI\": RETURN\n"))
+
+	out = session(t, src+"RENUM 100,9,5\nLIST\n")
+	testutils.True(t, "from line 9: "+out, strings.Contains(out,
+		"5 REM LOOP\n7 GOSUB 105\n100 GOTO 7\n105 PRINT \"HI\": RETURN\n"))
+
+	out = session(t, "10 GOTO 99\nRENUM\nLIST\n")
+	testutils.True(t, "undefined target refused: "+out, strings.Contains(out, "?") && strings.Contains(out, "10 GOTO 99\n"))
+
+	out = session(t, src+"RENUM X\n")
+	testutils.True(t, "syntax error: "+out, strings.Contains(out, "?SYNTAX ERROR\n"))
+}
+
+func TestSession_LoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prog.bas")
+	if err := os.WriteFile(path, []byte("1 GOTO 2\n2 END\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	session(t, "LOAD \""+path+"\"\nRENUM\nSAVE\n")
+	data, err := os.ReadFile(path)
+	testutils.True(t, "read", err == nil)
+	testutils.Equal(t, "saved", string(data), "10 GOTO 20\n20 END\n")
+}
@@ -1,54 +1,165 @@
 package runtime
 
-import "fmt"
+import (
+	"basics/internal/dialect"
+)
 
-type ValueType int
+// Environment stocke les variables par type : chaque variable résolue
+// (Declare) reçoit un slot dans le tableau des réels, des entiers ou des
+// chaînes. Get et Set restent disponibles par nom.
+type Environment struct {
+	// symbole de chaque nom (significatif)
+	index   map[string]int
+	symbols []Symbol
 
-const (
-	NUMBER ValueType = iota
-	INTEGER
-	STRING
-	BOOLEAN
-)
+	reals   store[float64]
+	ints    store[int]
+	strings store[string]
 
-type Value struct {
-	Type ValueType
-	Num  float64
-	Int  int
-	Str  string
-	Flag bool
+	// Dialecte utilisé pour réduire les noms à leur partie significative
+	// (nil = nom complet)
+	names *dialect.Dialect
 }
 
-type Environment struct {
-	vars map[string]Value
+// store contient les valeurs d'un type ; set reste faux tant que la
+// variable n'a pas été affectée
+type store[T any] struct {
+	values []T
+	set    []bool
+}
+
+func (s *store[T]) add() int {
+	var zero T
+	s.values = append(s.values, zero)
+	s.set = append(s.set, false)
+	return len(s.values) - 1
+}
+
+func (s *store[T]) get(slot int) (T, bool) {
+	return s.values[slot], s.set[slot]
+}
+
+func (s *store[T]) put(slot int, v T) {
+	s.values[slot] = v
+	s.set[slot] = true
 }
 
 func NewEnvironment() *Environment {
 	return &Environment{
-		vars: make(map[string]Value),
+		index: make(map[string]int),
 	}
 }
 
-func (e *Environment) Set(name string, v Value) {
-	e.vars[name] = v
+// SetNameSignificance active la significativité des noms du dialecte
+// (Applesoft : COUNT et CO sont la même variable). nil rétablit les noms
+// complets.
+func (e *Environment) SetNameSignificance(d *dialect.Dialect) {
+	e.names = d
 }
 
-func (e *Environment) Get(name string) (Value, bool) {
-	if v, ok := e.vars[name]; ok {
-		return v, true
+func (e *Environment) key(name string) string {
+	if e.names == nil {
+		return name
 	}
-	// Applesoft : variable non initialisée = 0
-	return Value{Type: NUMBER, Num: 0}, false
+	return e.names.SignificantName(name)
 }
 
-func (v Value) String() string {
-	if v.Type == STRING {
-		return v.Str
+// Declare résout une variable en symbole, créé au besoin avec son slot
+func (e *Environment) Declare(name string) Symbol {
+	k := e.key(name)
+	if i, ok := e.index[k]; ok {
+		return e.symbols[i]
 	}
 
-	if v.Type == INTEGER {
-		return fmt.Sprintf("%d", v.Int)
+	sym := Symbol{Name: name, Kind: KindOf(name)}
+	switch sym.Kind {
+	case IntegerKind:
+		sym.Slot = e.ints.add()
+	case StringKind:
+		sym.Slot = e.strings.add()
+	default:
+		sym.Slot = e.reals.add()
 	}
 
-	return fmt.Sprintf("%f", v.Num)
+	e.index[k] = len(e.symbols)
+	e.symbols = append(e.symbols, sym)
+	return sym
+}
+
+// Lookup retourne le symbole d'une variable déjà déclarée
+func (e *Environment) Lookup(name string) (Symbol, bool) {
+	i, ok := e.index[e.key(name)]
+	if !ok {
+		return Symbol{}, false
+	}
+	return e.symbols[i], true
+}
+
+// Accès typés par slot (bytecode)
+
+func (e *Environment) Real(slot int) (float64, bool) { return e.reals.get(slot) }
+func (e *Environment) SetReal(slot int, v float64)   { e.reals.put(slot, v) }
+func (e *Environment) Int(slot int) (int, bool)      { return e.ints.get(slot) }
+func (e *Environment) SetInt(slot int, v int)        { e.ints.put(slot, v) }
+func (e *Environment) Str(slot int) (string, bool)   { return e.strings.get(slot) }
+func (e *Environment) SetStr(slot int, v string)     { e.strings.put(slot, v) }
+
+// Load lit une variable sous forme de Value
+func (e *Environment) Load(sym Symbol) (Value, bool) {
+	switch sym.Kind {
+	case IntegerKind:
+		v, ok := e.ints.get(sym.Slot)
+		return Value{Type: INTEGER, Int: v}, ok
+	case StringKind:
+		v, ok := e.strings.get(sym.Slot)
+		return Value{Type: STRING, Str: v}, ok
+	default:
+		v, ok := e.reals.get(sym.Slot)
+		return Value{Type: NUMBER, Num: v}, ok
+	}
+}
+
+// Store écrit une valeur dans le stockage du type de la variable, avec les
+// conversions d'une affectation (Coerce)
+func (e *Environment) Store(sym Symbol, v Value) error {
+	v, err := Coerce(sym.Kind, v)
+	if err != nil {
+		return err
+	}
+
+	switch sym.Kind {
+	case IntegerKind:
+		e.ints.put(sym.Slot, v.Int)
+	case StringKind:
+		e.strings.put(sym.Slot, v.Str)
+	default:
+		e.reals.put(sym.Slot, v.Num)
+	}
+	return nil
+}
+
+// Set affecte une variable par son nom
+func (e *Environment) Set(name string, v Value) error {
+	return e.Store(e.Declare(name), v)
+}
+
+func (e *Environment) Get(name string) (Value, bool) {
+	if sym, ok := e.Lookup(name); ok {
+		if v, set := e.Load(sym); set {
+			return v, true
+		}
+	}
+	// Applesoft : variable non initialisée = 0
+	return Value{Type: NUMBER, Num: 0}, false
+}
+
+// Variables énumère les variables dans l'ordre de leur déclaration, avec
+// leur valeur (débogueurs)
+func (e *Environment) Variables() []Variable {
+	vars := make([]Variable, 0, len(e.symbols))
+	for _, sym := range e.symbols {
+		v, set := e.Load(sym)
+		vars = append(vars, Variable{Symbol: sym, Value: v, Set: set})
+	}
+	return vars
 }
@@ -15,6 +15,7 @@ type Runtime struct {
 	Input  input.Device
 	Env    *Environment
 	halted bool
+	err    error // dernière erreur affichée
 }
 
 func New(video video.Device) *Runtime {
@@ -29,11 +30,17 @@ func (r *Runtime) SetOutput(out io.Writer) {
 }
 
 func (rt *Runtime) ExecError(err error) {
+	rt.err = err
 	rt.Video.PrintString(err.Error())
 	rt.Video.PrintString("\n")
 	rt.Video.Render()
 }
 
+// Err retourne la dernière erreur affichée par ExecError
+func (rt *Runtime) Err() error {
+	return rt.err
+}
+
 func (rt *Runtime) Halt() {
 	rt.halted = true
 }
@@ -81,6 +88,69 @@ func (rt *Runtime) ExecHome() {
 	rt.Video.SetCursorY(0)
 }
 
+// ExecMode change le mode écran (MODE). Sans effet si la machine n'a qu'un mode.
+func (rt *Runtime) ExecMode(mode int) error {
+	if d, ok := rt.Video.(video.ModeDevice); ok {
+		return d.SetMode(mode)
+	}
+	return nil
+}
+
+// ExecCls efface l'écran texte (CLS)
+func (rt *Runtime) ExecCls() {
+	rt.ExecHome()
+}
+
+// ExecLocate positionne le curseur texte (LOCATE, coordonnées 1-based)
+func (rt *Runtime) ExecLocate(x, y int) {
+	rt.Video.SetCursorX(x - 1)
+	rt.Video.SetCursorY(y - 1)
+}
+
+func (rt *Runtime) ExecInk(ink, color1, color2 int) error {
+	if d, ok := rt.Video.(video.InkDevice); ok {
+		return d.SetInk(ink, color1, color2)
+	}
+	return nil
+}
+
+func (rt *Runtime) ExecPen(pen int) error {
+	if d, ok := rt.Video.(video.InkDevice); ok {
+		return d.SetPen(pen)
+	}
+	return nil
+}
+
+func (rt *Runtime) ExecPaper(paper int) error {
+	if d, ok := rt.Video.(video.InkDevice); ok {
+		return d.SetPaper(paper)
+	}
+	return nil
+}
+
+func (rt *Runtime) ExecBorder(color1, color2 int) error {
+	if d, ok := rt.Video.(video.InkDevice); ok {
+		return d.SetBorder(color1, color2)
+	}
+	return nil
+}
+
+// ExecGraphicsPen sélectionne l'encre utilisée par PLOT / DRAW
+func (rt *Runtime) ExecGraphicsPen(ink int) error {
+	if d, ok := rt.Video.(video.GraphicsDevice); ok {
+		return d.SetGraphicsPen(ink)
+	}
+	return nil
+}
+
+// ExecDraw trace une ligne du curseur graphique jusqu'à (x, y)
+func (rt *Runtime) ExecDraw(x, y int) {
+	if d, ok := rt.Video.(video.GraphicsDevice); ok {
+		d.DrawTo(x, y)
+		rt.Video.Render()
+	}
+}
+
 func (rt *Runtime) DisableKeyboard() {
 	rt.Video.DisableKeyboard()
 }
@@ -0,0 +1,49 @@
+package runtime
+
+import "strings"
+
+// Kind est le type d'une variable, donné par le suffixe de son nom
+type Kind uint8
+
+const (
+	RealKind    Kind = iota // A, A!
+	IntegerKind             // A%
+	StringKind              // A$
+)
+
+func (k Kind) String() string {
+	switch k {
+	case IntegerKind:
+		return "integer"
+	case StringKind:
+		return "string"
+	default:
+		return "real"
+	}
+}
+
+// KindOf retourne le type d'une variable d'après son suffixe
+func KindOf(name string) Kind {
+	switch {
+	case strings.HasSuffix(name, "%"):
+		return IntegerKind
+	case strings.HasSuffix(name, "$"):
+		return StringKind
+	}
+	return RealKind
+}
+
+// Symbol est une variable résolue : son type et son slot dans le stockage
+// de ce type
+type Symbol struct {
+	Name string // nom à la première déclaration
+	Kind Kind
+	Slot int
+}
+
+// Variable est une variable énumérée par Environment.Variables
+type Variable struct {
+	Symbol
+	Value Value
+	Set   bool // faux si la variable n'a jamais été affectée
+}
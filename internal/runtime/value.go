@@ -0,0 +1,108 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+type ValueType int
+
+const (
+	NUMBER  ValueType = iota // réel
+	INTEGER                  // entier 16 bits signé
+	STRING
+)
+
+// Value est une valeur BASIC : seul le champ de son type est significatif.
+// Les conversions passent par ToReal, ToInt16 et ToString.
+type Value struct {
+	Type ValueType
+	Num  float64 // NUMBER
+	Int  int     // INTEGER, entre MinInt16 et MaxInt16
+	Str  string  // STRING
+}
+
+// Bornes des entiers Applesoft (-32768 est refusé)
+const (
+	MinInt16 = -32767
+	MaxInt16 = 32767
+)
+
+var (
+	ErrTypeMismatch    = errors.New("TYPE MISMATCH")
+	ErrIllegalQuantity = errors.New("ILLEGAL QUANTITY")
+)
+
+func NewReal(f float64) Value {
+	return Value{Type: NUMBER, Num: f}
+}
+
+func NewInteger(n int) Value {
+	return Value{Type: INTEGER, Int: n}
+}
+
+func NewString(s string) Value {
+	return Value{Type: STRING, Str: s}
+}
+
+// ToReal retourne la valeur numérique ; un entier est converti en réel
+func (v Value) ToReal() (float64, error) {
+	switch v.Type {
+	case NUMBER:
+		return v.Num, nil
+	case INTEGER:
+		return float64(v.Int), nil
+	}
+	return 0, ErrTypeMismatch
+}
+
+// ToInt16 convertit en entier Applesoft : la partie décimale est tronquée
+// et la valeur doit tenir sur 16 bits
+func (v Value) ToInt16() (int, error) {
+	f, err := v.ToReal()
+	if err != nil {
+		return 0, err
+	}
+
+	f = math.Trunc(f)
+	if math.IsNaN(f) || f < MinInt16 || f > MaxInt16 {
+		return 0, ErrIllegalQuantity
+	}
+	return int(f), nil
+}
+
+// ToString retourne la chaîne ; un nombre n'est jamais converti en chaîne
+func (v Value) ToString() (string, error) {
+	if v.Type != STRING {
+		return "", ErrTypeMismatch
+	}
+	return v.Str, nil
+}
+
+// Coerce convertit une valeur affectée à une variable du type kind
+func Coerce(kind Kind, v Value) (Value, error) {
+	switch kind {
+	case IntegerKind:
+		n, err := v.ToInt16()
+		return NewInteger(n), err
+	case StringKind:
+		s, err := v.ToString()
+		return NewString(s), err
+	default:
+		f, err := v.ToReal()
+		return NewReal(f), err
+	}
+}
+
+func (v Value) String() string {
+	if v.Type == STRING {
+		return v.Str
+	}
+
+	if v.Type == INTEGER {
+		return fmt.Sprintf("%d", v.Int)
+	}
+
+	return fmt.Sprintf("%f", v.Num)
+}
@@ -3,6 +3,7 @@ package runtime
 import (
 	"testing"
 
+	"basics/internal/dialect"
 	"basics/testutils"
 )
 
@@ -25,28 +26,12 @@ func TestEnv_SetGet(t *testing.T) {
 		},
 		{
 			name:       "Get after set string",
-			setName:    "MSG",
+			setName:    "MSG$",
 			setValue:   Value{Type: STRING, Str: "Hello"},
-			getName:    "MSG",
+			getName:    "MSG$",
 			wantValue:  Value{Type: STRING, Str: "Hello"},
 			wantExists: true,
 		},
-		{
-			name:       "Get after set boolean to true",
-			setName:    "MSG",
-			setValue:   Value{Type: BOOLEAN, Flag: true},
-			getName:    "MSG",
-			wantValue:  Value{Type: BOOLEAN, Flag: true},
-			wantExists: true,
-		},
-		{
-			name:       "Get after set boolean to false",
-			setName:    "MSG",
-			setValue:   Value{Type: BOOLEAN, Flag: false},
-			getName:    "MSG",
-			wantValue:  Value{Type: BOOLEAN, Flag: false},
-			wantExists: true,
-		},
 		{
 			name:       "Get unset variable returns default number 0",
 			setName:    "",
@@ -153,3 +138,77 @@ func TestValue_String(t *testing.T) {
 		})
 	}
 }
+
+func TestEnv_NameSignificance(t *testing.T) {
+	env := NewEnvironment()
+	env.Set("COUNT", Value{Type: NUMBER, Num: 1})
+
+	_, ok := env.Get("CO")
+	testutils.False(t, "full names: CO is not COUNT", ok)
+
+	env = NewEnvironment()
+	env.SetNameSignificance(dialect.Applesoft)
+	env.Set("COUNT", Value{Type: NUMBER, Num: 1})
+
+	v, ok := env.Get("CO")
+	testutils.True(t, "Applesoft: CO is COUNT", ok)
+	testutils.Equal(t, "Applesoft: CO value", v.Num, 1.0)
+
+	v, _ = env.Get("COLOR")
+	testutils.Equal(t, "Applesoft: COLOR value", v.Num, 1.0)
+
+	_, ok = env.Get("CO$")
+	testutils.False(t, "Applesoft: suffix stays significant", ok)
+
+	env = NewEnvironment()
+	env.SetNameSignificance(dialect.Locomotive)
+	env.Set("COUNT", Value{Type: NUMBER, Num: 1})
+	_, ok = env.Get("CO")
+	testutils.False(t, "Locomotive: every character is significant", ok)
+}
+
+func TestEnv_TypedSlots(t *testing.T) {
+	env := NewEnvironment()
+	env.SetNameSignificance(dialect.Applesoft)
+
+	a := env.Declare("COUNT")
+	testutils.Equal(t, "same significant name", env.Declare("CO"), a)
+	testutils.Equal(t, "kind", a.Kind, RealKind)
+
+	n := env.Declare("N%")
+	s := env.Declare("S$")
+	testutils.Equal(t, "integer kind", n.Kind, IntegerKind)
+	testutils.Equal(t, "string kind", s.Kind, StringKind)
+	testutils.Equal(t, "one slot per type", a.Slot+n.Slot+s.Slot, 0)
+	testutils.Equal(t, "next real slot", env.Declare("B").Slot, 1)
+
+	_, ok := env.Real(a.Slot)
+	testutils.False(t, "not assigned yet", ok)
+
+	env.SetReal(a.Slot, 3)
+	v, ok := env.Get("COUNT")
+	testutils.True(t, "visible by name", ok)
+	testutils.Equal(t, "value", v.Num, 3.0)
+
+	env.Set("N%", Value{Type: INTEGER, Int: 7})
+	i, _ := env.Int(n.Slot)
+	testutils.Equal(t, "visible by slot", i, 7)
+
+	env.SetStr(s.Slot, "HI")
+	v, _ = env.Get("S$")
+	testutils.Equal(t, "string value", v, Value{Type: STRING, Str: "HI"})
+}
+
+func TestEnv_Variables(t *testing.T) {
+	env := NewEnvironment()
+	env.Declare("B$")
+	env.Set("A", Value{Type: NUMBER, Num: 2})
+
+	vars := env.Variables()
+	testutils.Equal(t, "count", len(vars), 2)
+	testutils.Equal(t, "declaration order", vars[0].Name+","+vars[1].Name, "B$,A")
+	testutils.False(t, "B$ unset", vars[0].Set)
+	testutils.Equal(t, "B$ kind", vars[0].Kind.String(), "string")
+	testutils.True(t, "A set", vars[1].Set)
+	testutils.Equal(t, "A value", vars[1].Value, Value{Type: NUMBER, Num: 2})
+}
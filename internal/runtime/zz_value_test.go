@@ -0,0 +1,59 @@
+package runtime
+
+import (
+	"math"
+	"testing"
+
+	"basics/testutils"
+)
+
+func TestValue_ToReal(t *testing.T) {
+	f, err := NewInteger(-3).ToReal()
+	testutils.True(t, "integer widened", err == nil && f == -3)
+
+	f, err = NewReal(1.5).ToReal()
+	testutils.True(t, "real", err == nil && f == 1.5)
+
+	_, err = NewString("1").ToReal()
+	testutils.Equal(t, "string refused", err, ErrTypeMismatch)
+}
+
+func TestValue_ToInt16(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want int
+		err  error
+	}{
+		{1.9, 1, nil},
+		{-1.9, -1, nil},
+		{MaxInt16, MaxInt16, nil},
+		{MinInt16, MinInt16, nil},
+		{32767.99, MaxInt16, nil},
+		{32768, 0, ErrIllegalQuantity},
+		{-32768, 0, ErrIllegalQuantity},
+		{math.NaN(), 0, ErrIllegalQuantity},
+	}
+
+	for _, tt := range tests {
+		n, err := NewReal(tt.in).ToInt16()
+		testutils.Equal(t, "error", err, tt.err)
+		testutils.Equal(t, "value", n, tt.want)
+	}
+
+	_, err := NewString("1").ToInt16()
+	testutils.Equal(t, "string refused", err, ErrTypeMismatch)
+}
+
+func TestValue_Coerce(t *testing.T) {
+	v, err := Coerce(IntegerKind, NewReal(-7.5))
+	testutils.True(t, "truncated", err == nil && v == NewInteger(-7))
+
+	v, err = Coerce(RealKind, NewInteger(4))
+	testutils.True(t, "widened", err == nil && v == NewReal(4))
+
+	_, err = Coerce(StringKind, NewReal(1))
+	testutils.Equal(t, "number to string", err, ErrTypeMismatch)
+
+	_, err = Coerce(RealKind, NewString("A"))
+	testutils.Equal(t, "string to number", err, ErrTypeMismatch)
+}
@@ -0,0 +1,133 @@
+package unparse
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"basics/internal/dialect"
+	"basics/internal/lexer"
+	"basics/internal/parser"
+	"basics/internal/token"
+)
+
+// Remarks relève le commentaire de chaque REM du source, par numéro de
+// ligne. L'AST ne conserve pas les commentaires : le formateur les
+// reprend du source.
+func Remarks(source string, tokens []token.Token) map[int]string {
+	lines := strings.Split(source, "\n")
+	remarks := map[int]string{}
+
+	number := 0
+	for _, tok := range tokens {
+		switch {
+		case tok.Type == token.LINENUM:
+			number, _ = strconv.Atoi(tok.Literal)
+
+		case tok.Type == token.KEYWORD && tok.Literal == "REM":
+			if tok.Line < 1 || tok.Line > len(lines) {
+				continue
+			}
+			text := []rune(lines[tok.Line-1])
+			start := tok.Column - 1 + len("REM")
+			if start <= len(text) {
+				remarks[number] = strings.TrimSpace(string(text[start:]))
+			}
+		}
+	}
+	return remarks
+}
+
+// Format imprime le programme selon les options et vérifie que le texte
+// produit redonne le même programme avec le dialecte d (en mode crunché
+// pour le style Crunched, mots-clés en minuscules pour la casse Lower)
+func Format(prog *parser.Program, d *dialect.Dialect, opts Options) (string, error) {
+	var sb strings.Builder
+	var numbers []int // numéro BASIC de chaque ligne du texte produit
+
+	for _, line := range prog.Lines {
+		text := opts.Line(line)
+		for n := strings.Count(text, "\n"); n >= 0; n-- {
+			numbers = append(numbers, line.Number)
+		}
+		sb.WriteString(text)
+		sb.WriteString("\n")
+	}
+	out := sb.String()
+
+	if opts.Style == Crunched {
+		d = d.WithCrunched()
+	}
+	if opts.Case == Lower {
+		d = d.WithLowercase()
+	}
+
+	tokens, err := lexer.Scan(out, d)
+	if err != nil {
+		return "", fmt.Errorf("formatted program does not lex: %v", err)
+	}
+	again, errs := parser.NewWithDialect(tokens, d).ParseProgram()
+	if len(errs) > 0 {
+		e := errs[0]
+		if e.Line >= 1 && e.Line <= len(numbers) {
+			return "", fmt.Errorf("line %d does not re-parse: %s (%s)", numbers[e.Line-1], e.Msg, e.Token)
+		}
+		return "", fmt.Errorf("formatted program does not parse: %s", e.Error())
+	}
+
+	if len(again.Lines) != len(prog.Lines) {
+		return "", fmt.Errorf("formatted program has %d lines instead of %d", len(again.Lines), len(prog.Lines))
+	}
+	for i, line := range prog.Lines {
+		if !sameNode(reflect.ValueOf(again.Lines[i]), reflect.ValueOf(line)) {
+			return "", fmt.Errorf("line %d does not re-parse to the same statements", line.Number)
+		}
+	}
+
+	return out, nil
+}
+
+// positions sont les champs qui situent un nœud dans le source
+var positions = map[string]bool{"Span": true, "Line": true, "Column": true, "LineNum": true, "Token": true}
+
+// sameNode compare deux nœuds de l'AST sans tenir compte de leur position
+// dans le source
+func sameNode(a, b reflect.Value) bool {
+	if a.Kind() != b.Kind() {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		if a.Elem().Type() != b.Elem().Type() {
+			return false
+		}
+		return sameNode(a.Elem(), b.Elem())
+
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for k := range a.Len() {
+			if !sameNode(a.Index(k), b.Index(k)) {
+				return false
+			}
+		}
+		return true
+
+	case reflect.Struct:
+		for k := range a.NumField() {
+			if !positions[a.Type().Field(k).Name] && !sameNode(a.Field(k), b.Field(k)) {
+				return false
+			}
+		}
+		return true
+
+	default:
+		return a.Equal(b)
+	}
+}
@@ -0,0 +1,431 @@
+package unparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"basics/internal/parser"
+)
+
+// Case est la casse des mots-clés
+type Case int
+
+const (
+	Upper Case = iota // PRINT
+	Lower             // print
+)
+
+// Style est l'espacement du listing
+type Style int
+
+const (
+	List     Style = iota // comme LIST : 10 FOR I = 1 TO 10: PRINT I
+	Crunched              // sans espaces : 10FORI=1TO10:PRINTI
+)
+
+// Options règle l'impression du programme. La valeur zéro donne le
+// listing canonique.
+type Options struct {
+	Case  Case
+	Style Style
+
+	// Width coupe les lignes plus longues entre deux instructions, la
+	// suite étant placée sur une ligne de continuation commençant par ':'.
+	// 0 : pas de coupure.
+	Width int
+
+	// Remarks donne le texte des REM par numéro de ligne (voir Remarks) ;
+	// sans lui, un REM est écrit sans son commentaire.
+	Remarks map[int]string
+}
+
+// Program retourne le listing complet du programme, une ligne par ligne
+// BASIC
+func Program(prog *parser.Program) string {
+	return Options{}.Program(prog)
+}
+
+// Line retourne le texte d'une ligne BASIC, numéro de ligne compris
+func Line(line *parser.Line) string {
+	return Options{}.Line(line)
+}
+
+// Statements retourne une liste d'instructions séparées par ':'
+func Statements(stmts []parser.Statement) string {
+	p := &printer{Options{}, ""}
+	return p.join(p.statements(stmts))
+}
+
+// Statement retourne le texte source d'une instruction. Une instruction
+// nil (REM) donne REM : le commentaire n'est pas conservé dans l'AST.
+func Statement(stmt parser.Statement) string {
+	p := &printer{Options{}, ""}
+	return p.join(p.statement(stmt))
+}
+
+// Expression retourne le texte source d'une expression. Les parenthèses
+// sont rétablies d'après la priorité des opérateurs.
+func Expression(expr parser.Expression) string {
+	p := &printer{Options{}, ""}
+	return p.expression(expr)
+}
+
+// Program retourne le listing du programme selon les options
+func (o Options) Program(prog *parser.Program) string {
+	var sb strings.Builder
+	for _, line := range prog.Lines {
+		sb.WriteString(o.Line(line))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Line retourne le texte d'une ligne selon les options, lignes de
+// continuation comprises
+func (o Options) Line(line *parser.Line) string {
+	p := &printer{o, o.Remarks[line.Number]}
+
+	number := strconv.Itoa(line.Number)
+	segs := p.statements(line.Stmts)
+	if p.remark != "" {
+		// REM retiré de l'AST (bloc THEN) : remis en fin de ligne
+		segs = append(segs, p.rem())
+	}
+	if len(segs) == 0 {
+		return number
+	}
+
+	head := number + p.gap()
+	if o.Width <= 0 {
+		return head + p.join(segs)
+	}
+	return head + p.wrap(segs, len(head))
+}
+
+// printer imprime une ligne : remark est le commentaire du REM de la ligne
+// pas encore écrit
+type printer struct {
+	opts   Options
+	remark string
+}
+
+// keyword applique la casse choisie à un mot-clé
+func (p *printer) keyword(kw string) string {
+	if p.opts.Case == Lower {
+		return strings.ToLower(kw)
+	}
+	return kw
+}
+
+// gap est un espace facultatif, absent en style crunché
+func (p *printer) gap() string {
+	if p.opts.Style == Crunched {
+		return ""
+	}
+	return " "
+}
+
+// separator sépare deux instructions
+func (p *printer) separator() string {
+	return ":" + p.gap()
+}
+
+func (p *printer) join(segs []string) string {
+	return strings.Join(segs, p.separator())
+}
+
+// wrap coupe la ligne avant un ':' lorsqu'elle dépasse la largeur ; une
+// instruction trop longue n'est pas coupée
+func (p *printer) wrap(segs []string, indent int) string {
+	var sb strings.Builder
+	col := indent
+	pad := strings.Repeat(" ", indent)
+
+	for i, seg := range segs {
+		if i > 0 {
+			sep := p.separator()
+			if col+len(sep)+len(seg) > p.opts.Width {
+				sb.WriteString("\n" + pad)
+				col = indent
+			}
+			sb.WriteString(sep)
+			col += len(sep)
+		}
+		sb.WriteString(seg)
+		col += len(seg)
+	}
+	return sb.String()
+}
+
+// rem écrit un REM avec le commentaire de la ligne
+func (p *printer) rem() string {
+	text := p.remark
+	p.remark = ""
+	if text == "" {
+		return p.keyword("REM")
+	}
+	return p.keyword("REM") + p.gap() + text
+}
+
+// statements retourne les segments d'une liste d'instructions, à séparer
+// par ':'
+func (p *printer) statements(stmts []parser.Statement) []string {
+	var segs []string
+	for _, stmt := range stmts {
+		segs = append(segs, p.statement(stmt)...)
+	}
+	return segs
+}
+
+// statement retourne le texte d'une instruction. Un IF donne plusieurs
+// segments lorsque ses blocs contiennent plusieurs instructions.
+func (p *printer) statement(stmt parser.Statement) []string {
+	if s, ok := stmt.(*parser.IfStmt); ok {
+		return p.ifStatement(s)
+	}
+	return []string{p.simple(stmt)}
+}
+
+// simple retourne le texte d'une instruction autre que IF
+func (p *printer) simple(stmt parser.Statement) string {
+	g := p.gap()
+	kw := func(name string, args string) string {
+		return p.keyword(name) + g + args
+	}
+
+	switch s := stmt.(type) {
+
+	case nil:
+		return p.rem()
+
+	case *parser.PrintStmt:
+		if len(s.Exprs) == 0 {
+			return p.keyword("PRINT")
+		}
+		var sb strings.Builder
+		for i, expr := range s.Exprs {
+			sb.WriteString(p.expression(expr))
+			if i < len(s.Separators) {
+				sb.WriteRune(s.Separators[i])
+			}
+			if i+1 < len(s.Exprs) {
+				sb.WriteString(g)
+			}
+		}
+		return kw("PRINT", sb.String())
+
+	case *parser.InputStmt:
+		var sb strings.Builder
+		if s.Prompt != nil {
+			sb.WriteString(p.expression(s.Prompt))
+			sb.WriteString(";" + g)
+		}
+		for i, v := range s.Vars {
+			if i > 0 {
+				sb.WriteString("," + g)
+			}
+			sb.WriteString(v.Name)
+		}
+		return kw("INPUT", sb.String())
+
+	case *parser.GetStmt:
+		return kw("GET", s.Var.Name)
+
+	case *parser.LetStmt:
+		return s.Name + g + "=" + g + p.expression(s.Value)
+
+	case *parser.ForStmt:
+		out := kw("FOR", s.Var+g+"="+g+p.expression(s.Start)) +
+			g + kw("TO", p.expression(s.End))
+		if s.Step != nil && !isDefaultStep(s.Step) {
+			out += g + kw("STEP", p.expression(s.Step))
+		}
+		return out
+
+	case *parser.NextStmt:
+		return kw("NEXT", s.Var)
+
+	case *parser.HTabStmt:
+		return kw("HTAB", p.expression(s.Expr))
+
+	case *parser.VTabStmt:
+		return kw("VTAB", p.expression(s.Expr))
+
+	case *parser.EndStmt:
+		return p.keyword("END")
+
+	case *parser.HomeStmt:
+		return p.keyword("HOME")
+
+	case *parser.TraceStmt:
+		return p.keyword("TRACE")
+
+	case *parser.NoTraceStmt:
+		return p.keyword("NOTRACE")
+
+	case *parser.GotoStmt:
+		return kw("GOTO", p.expression(s.Expr))
+
+	case *parser.GosubStmt:
+		return kw("GOSUB", p.expression(s.Expr))
+
+	case *parser.ReturnStmt:
+		return p.keyword("RETURN")
+
+	case *parser.ModeStmt:
+		return kw("MODE", p.expression(s.Expr))
+
+	case *parser.ClsStmt:
+		return p.keyword("CLS")
+
+	case *parser.LocateStmt:
+		return kw("LOCATE", p.arguments(s.X, s.Y))
+
+	case *parser.InkStmt:
+		return kw("INK", p.arguments(s.Ink, s.Color1, s.Color2))
+
+	case *parser.PenStmt:
+		return kw("PEN", p.expression(s.Expr))
+
+	case *parser.PaperStmt:
+		return kw("PAPER", p.expression(s.Expr))
+
+	case *parser.BorderStmt:
+		return kw("BORDER", p.arguments(s.Color1, s.Color2))
+
+	case *parser.PlotStmt:
+		return kw("PLOT", p.arguments(s.X, s.Y, s.Ink))
+
+	case *parser.DrawStmt:
+		return kw("DRAW", p.arguments(s.X, s.Y, s.Ink))
+
+	case *parser.CallStmt:
+		if len(s.Args) == 0 {
+			return s.Name
+		}
+		return s.Name + g + p.arguments(s.Args...)
+	}
+
+	return fmt.Sprintf("REM UNKNOWN STATEMENT %T", stmt)
+}
+
+// ifStatement écrit IF ... THEN ... ELSE ... : la première instruction
+// d'un bloc suit THEN (ou ELSE), les suivantes sont des segments de la
+// ligne
+func (p *printer) ifStatement(s *parser.IfStmt) []string {
+	g := p.gap()
+
+	then := p.ifBlock(s.Then, s.Else == nil)
+	segs := append([]string{
+		p.keyword("IF") + g + p.expression(s.Cond) + g + p.keyword("THEN") + g + then[0],
+	}, then[1:]...)
+
+	if s.Else != nil {
+		els := p.ifBlock(s.Else, true)
+		last := len(segs) - 1
+		segs[last] += g + p.keyword("ELSE") + g + els[0]
+		segs = append(segs, els[1:]...)
+	}
+
+	for i := range segs {
+		segs[i] = strings.TrimRight(segs[i], " ")
+	}
+	return segs
+}
+
+// ifBlock écrit le bloc THEN / ELSE. THEN 100 est la forme courte de
+// THEN GOTO 100. Un REM en fin de bloc n'est pas dans l'AST : le
+// commentaire de la ligne est remis dans le dernier bloc s'il est vide.
+func (p *printer) ifBlock(stmts []parser.Statement, last bool) []string {
+	if len(stmts) == 1 {
+		if g, ok := stmts[0].(*parser.GotoStmt); ok {
+			if n, ok := g.Expr.(*parser.NumberLiteral); ok {
+				return []string{p.expression(n)}
+			}
+		}
+	}
+
+	segs := p.statements(stmts)
+	if len(segs) == 0 {
+		if last && p.remark != "" {
+			return []string{p.rem()}
+		}
+		return []string{""}
+	}
+	return segs
+}
+
+// isDefaultStep indique si le pas est le STEP 1 implicite
+func isDefaultStep(step parser.Expression) bool {
+	n, ok := step.(*parser.NumberLiteral)
+	return ok && n.Value == 1
+}
+
+// arguments écrit une liste d'arguments, les arguments optionnels absents
+// (nil) en fin de liste étant omis
+func (p *printer) arguments(exprs ...parser.Expression) string {
+	parts := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		if expr == nil {
+			break
+		}
+		parts = append(parts, p.expression(expr))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (p *printer) expression(expr parser.Expression) string {
+	switch e := expr.(type) {
+
+	case *parser.Identifier:
+		return e.Name
+
+	case *parser.NumberLiteral:
+		// littéral d'origine s'il est disponible (1.50, 007)
+		if _, err := strconv.ParseFloat(e.Token, 64); err == nil {
+			return e.Token
+		}
+		return strconv.FormatFloat(e.Value, 'f', -1, 64)
+
+	case *parser.StringLiteral:
+		return `"` + e.Value + `"`
+
+	case *parser.PrefixExpr:
+		return e.Op + p.operand(e.Right, parser.PREFIX, false)
+
+	case *parser.InfixExpr:
+		prec := parser.Precedence(e.Op)
+		return p.operand(e.Left, prec, false) + e.Op + p.operand(e.Right, prec, true)
+
+	case *parser.IntExpr:
+		return p.keyword("INT") + "(" + p.expression(e.Expr) + ")"
+
+	case *parser.AbsExpr:
+		return p.keyword("ABS") + "(" + p.expression(e.Expr) + ")"
+
+	case *parser.SgnExpr:
+		return p.keyword("SGN") + "(" + p.expression(e.Expr) + ")"
+
+	case *parser.CallExpr:
+		return e.Name + "(" + p.arguments(e.Args...) + ")"
+	}
+
+	return ""
+}
+
+// operand écrit l'opérande d'un opérateur de priorité prec, entre
+// parenthèses si nécessaire. Les opérateurs étant associatifs à gauche,
+// l'opérande droit de même priorité est parenthésé : A-(B-C).
+func (p *printer) operand(expr parser.Expression, prec int, right bool) string {
+	inner, ok := expr.(*parser.InfixExpr)
+	if !ok {
+		return p.expression(expr)
+	}
+
+	innerPrec := parser.Precedence(inner.Op)
+	if innerPrec < prec || (right && innerPrec == prec) {
+		return "(" + p.expression(expr) + ")"
+	}
+	return p.expression(expr)
+}
@@ -0,0 +1,113 @@
+package unparse
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"basics/internal/dialect"
+	"basics/internal/lexer"
+	"basics/testutils"
+)
+
+const formatSource = `10 REM   COUNTDOWN
+20 FOR I=10 TO 1 STEP -1:PRINT "T-";I:NEXT I
+30 IF I<1 THEN PRINT "GO":GOTO 50
+40 IF I THEN REM NEVER
+50 A$="DONE":PRINT A$
+`
+
+func TestFormat_Styles(t *testing.T) {
+	prog := parse(t, formatSource, dialect.Applesoft)
+	remarks := Remarks(formatSource, lexer.LexDialect(formatSource, dialect.Applesoft))
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{
+			name: "list",
+			opts: Options{Remarks: remarks},
+			expected: `10 REM COUNTDOWN
+20 FOR I = 10 TO 1 STEP -1: PRINT "T-"; I: NEXT I
+30 IF I<1 THEN PRINT "GO": GOTO 50
+40 IF I THEN REM NEVER
+50 A$ = "DONE": PRINT A$
+`,
+		},
+		{
+			name: "crunched",
+			opts: Options{Style: Crunched, Remarks: remarks},
+			expected: `10REMCOUNTDOWN
+20FORI=10TO1STEP-1:PRINT"T-";I:NEXTI
+30IFI<1THENPRINT"GO":GOTO50
+40IFITHENREMNEVER
+50A$="DONE":PRINTA$
+`,
+		},
+		{
+			name: "lowercase",
+			opts: Options{Case: Lower},
+			expected: `10 rem
+20 for I = 10 to 1 step -1: print "T-"; I: next I
+30 if I<1 then print "GO": goto 50
+40 if I then
+50 A$ = "DONE": print A$
+`,
+		},
+		{
+			name: "wrapped",
+			opts: Options{Width: 24},
+			expected: `10 REM
+20 FOR I = 10 TO 1 STEP -1
+   : PRINT "T-"; I
+   : NEXT I
+30 IF I<1 THEN PRINT "GO"
+   : GOTO 50
+40 IF I THEN
+50 A$ = "DONE": PRINT A$
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Format(prog, dialect.Applesoft, tt.opts)
+			testutils.True(t, "re-parsed", err == nil)
+			testutils.Equal(t, tt.name, out, tt.expected)
+		})
+	}
+}
+
+func TestFormat_Verification(t *testing.T) {
+	// IFATHEN : en mode crunché, A THEN se lit AT HEN
+	prog := parse(t, "10 IF A THEN 20\n20 END\n", dialect.Applesoft)
+
+	_, err := Format(prog, dialect.Applesoft, Options{Style: Crunched})
+	testutils.True(t, "ambiguous crunched line refused", err != nil)
+	testutils.True(t, "line reported", err != nil && strings.Contains(err.Error(), "10"))
+
+	_, err = Format(prog, dialect.Applesoft, Options{})
+	testutils.True(t, "list style accepted", err == nil)
+}
+
+func TestSameNode(t *testing.T) {
+	prog := parse(t, "10 PRINT 1+2;A$:GOTO 10\n", dialect.Applesoft)
+	spaced := parse(t, "\n10  PRINT 1 + 2 ; A$ : GOTO 10\n", dialect.Applesoft)
+	other := parse(t, "10 PRINT 1+2;A$:GOSUB 10\n", dialect.Applesoft)
+
+	testutils.True(t, "positions ignored", sameNode(reflect.ValueOf(prog), reflect.ValueOf(spaced)))
+	testutils.False(t, "statements compared", sameNode(reflect.ValueOf(prog), reflect.ValueOf(other)))
+}
+
+func TestFormat_WrappedSameProgram(t *testing.T) {
+	prog := parse(t, formatSource, dialect.Applesoft)
+
+	out, err := Format(prog, dialect.Applesoft, Options{Width: 20})
+	testutils.True(t, "re-parsed", err == nil)
+	testutils.True(t, "continuation lines", strings.Contains(out, "\n   : "))
+
+	again := parse(t, out, dialect.Applesoft)
+	testutils.True(t, "same AST", sameNode(reflect.ValueOf(again), reflect.ValueOf(prog)))
+}
@@ -0,0 +1,96 @@
+package unparse
+
+import (
+	"testing"
+
+	"basics/internal/dialect"
+	"basics/internal/lexer"
+	"basics/internal/parser"
+	"basics/testutils"
+)
+
+func parse(t *testing.T, src string, d *dialect.Dialect) *parser.Program {
+	t.Helper()
+
+	prog, errs := parser.NewWithDialect(lexer.LexDialect(src, d), d).ParseProgram()
+	testutils.Equal(t, "no parser errors", len(errs), 0)
+	return prog
+}
+
+func TestLine(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"10 HOME", "10 HOME"},
+		{"10 PRINT", "10 PRINT"},
+		{`10 PRINT "A";B,C;`, `10 PRINT "A"; B, C;`},
+		{`10 INPUT "NAME";N$`, `10 INPUT "NAME"; N$`},
+		{"10 INPUT A,B", "10 INPUT A, B"},
+		{"10 GET K$", "10 GET K$"},
+		{"10 LET A=1", "10 A = 1"},
+		{"10 FOR I=1 TO 10:NEXT I", "10 FOR I = 1 TO 10: NEXT I"},
+		{"10 FOR I=10 TO 1 STEP -2:NEXT I", "10 FOR I = 10 TO 1 STEP -2: NEXT I"},
+		{"10 FOR I=1 TO 2:NEXT I", "10 FOR I = 1 TO 2: NEXT I"},
+		{"10 HTAB 5:VTAB 10", "10 HTAB 5: VTAB 10"},
+		{"10 GOTO 100", "10 GOTO 100"},
+		{"10 GOSUB 100:RETURN", "10 GOSUB 100: RETURN"},
+		{"10 IF A>1 THEN 100", "10 IF A>1 THEN 100"},
+		{"10 IF A=1 THEN PRINT A:END", "10 IF A=1 THEN PRINT A: END"},
+		{"10 IF A THEN PRINT 1 ELSE PRINT 2", "10 IF A THEN PRINT 1 ELSE PRINT 2"},
+		{"10 REM HELLO", "10 REM"},
+		{"10", "10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			prog := parse(t, tt.input+"\n", dialect.Applesoft)
+			testutils.Equal(t, tt.input, Line(prog.Lines[0]), tt.expected)
+		})
+	}
+}
+
+func TestLine_Locomotive(t *testing.T) {
+	src := "10 MODE 1:CLS:LOCATE 2,3:INK 1,2,3:PEN 1:PAPER 0:BORDER 4:PLOT 1,2:DRAW 3,4,1\n"
+	prog := parse(t, src, dialect.Locomotive)
+
+	testutils.Equal(t, "CPC statements", Line(prog.Lines[0]),
+		"10 MODE 1: CLS: LOCATE 2,3: INK 1,2,3: PEN 1: PAPER 0: BORDER 4: PLOT 1,2: DRAW 3,4,1")
+}
+
+func TestExpression_Parentheses(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1+2*3", "1+2*3"},
+		{"(1+2)*3", "(1+2)*3"},
+		{"1-(2-3)", "1-(2-3)"},
+		{"(1-2)-3", "1-2-3"},
+		{"2^(3^2)", "2^(3^2)"},
+		{"-(A+B)", "-(A+B)"},
+		{"-A^2", "-A^2"},
+		{"ABS(X-1)/SGN(Y)", "ABS(X-1)/SGN(Y)"},
+		{"INT(X*100+0.5)", "INT(X*100+0.5)"},
+		{`A$="YES"`, `A$="YES"`},
+		{"1.50", "1.50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			prog := parse(t, "10 PRINT "+tt.input+"\n", dialect.Applesoft)
+			expr := prog.Lines[0].Stmts[0].(*parser.PrintStmt).Exprs[0]
+			got := Expression(expr)
+			testutils.Equal(t, tt.input, got, tt.expected)
+
+			// le texte produit doit redonner le même arbre
+			again := parse(t, "10 PRINT "+got+"\n", dialect.Applesoft)
+			testutils.Equal(t, "reparse", Line(again.Lines[0]), Line(prog.Lines[0]))
+		})
+	}
+}
+
+func TestProgram(t *testing.T) {
+	prog := parse(t, "10 HOME\n20 PRINT \"HI\"\n", dialect.Applesoft)
+	testutils.Equal(t, "listing", Program(prog), "10 HOME\n20 PRINT \"HI\"\n")
+}
@@ -24,3 +24,25 @@ type Device interface {
 	// --- Rendu ---
 	Render()
 }
+
+// ModeDevice est implémenté par les machines disposant de plusieurs modes
+// écran sélectionnables depuis le BASIC (ex: MODE 0/1/2 de l'Amstrad CPC).
+type ModeDevice interface {
+	SetMode(mode int) error
+}
+
+// InkDevice est implémenté par les machines à palette programmable
+// (INK, PEN, PAPER, BORDER).
+type InkDevice interface {
+	SetInk(ink, color1, color2 int) error
+	SetPen(pen int) error
+	SetPaper(paper int) error
+	SetBorder(color1, color2 int) error
+}
+
+// GraphicsDevice est implémenté par les machines capables de tracer des
+// lignes depuis le curseur graphique (DRAW).
+type GraphicsDevice interface {
+	SetGraphicsPen(ink int) error
+	DrawTo(x, y int)
+}
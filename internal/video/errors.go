@@ -0,0 +1,7 @@
+package video
+
+import "errors"
+
+// ErrImproperArgument est renvoyée par un device lorsqu'un paramètre
+// (mode, encre, couleur...) est hors des limites de la machine.
+var ErrImproperArgument = errors.New("improper argument")
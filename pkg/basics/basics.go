@@ -0,0 +1,102 @@
+// Package basics exécute des programmes BASIC depuis un programme Go :
+// Compile analyse le source, une Machine l'exécute avec ses entrées,
+// sa sortie et ses limites.
+//
+//	prog, err := basics.Compile("10 PRINT \"HELLO\"\n")
+//	if err != nil {
+//		return err
+//	}
+//	m := basics.NewMachine(prog, basics.WithOutput(os.Stdout), basics.WithTimeout(time.Second))
+//	err = m.Run(ctx)
+package basics
+
+import (
+	"fmt"
+	"strings"
+
+	"basics/internal/diag"
+	"basics/internal/errors"
+	"basics/internal/interpreter"
+	"basics/internal/lexer"
+	"basics/internal/parser"
+)
+
+// Causes d'arrêt retournées par Machine.Run
+var (
+	ErrBreak      = interpreter.ErrBreak      // contexte annulé
+	ErrTimeLimit  = interpreter.ErrTimeLimit  // délai de WithTimeout ou du contexte expiré
+	ErrStepLimit  = interpreter.ErrStepLimit  // limite de WithMaxSteps atteinte
+	ErrEndOfInput = interpreter.ErrEndOfInput // INPUT ou GET sans plus rien à lire
+)
+
+// Program est un programme BASIC analysé, exécutable par plusieurs
+// machines
+type Program struct {
+	ast    *parser.Program
+	config config // options de Compile
+}
+
+// Compile analyse un programme BASIC. Les options choisissent le dialecte
+// et déclarent les instructions et fonctions ajoutées (WithStatement,
+// WithFunction) ; les autres servent de valeurs par défaut aux machines du
+// programme. L'erreur retournée est une ErrorList.
+func Compile(src string, opts ...Option) (*Program, error) {
+	cfg := newConfig(opts)
+	d := cfg.syntax()
+
+	tokens, err := lexer.Scan(src, d)
+	if err != nil {
+		// le lexer s'arrête sur le token invalide
+		bad := tokens[len(tokens)-1]
+		return nil, ErrorList{{Line: bad.Line, Column: bad.Column, Message: fmt.Sprintf("INVALID TOKEN (%s)", bad.Literal)}}
+	}
+
+	p := parser.NewWithDialect(tokens, d)
+	p.Extend(names(cfg.statements), names(cfg.functions))
+
+	prog, errs := p.ParseProgram()
+	if len(errs) > 0 {
+		return nil, newErrorList(prog, errs)
+	}
+	return &Program{ast: prog, config: cfg}, nil
+}
+
+// Error est une erreur d'un programme BASIC, d'analyse (Compile) ou
+// d'exécution (Machine.Run)
+type Error struct {
+	Line    int    // ligne du fichier, à partir de 1
+	Column  int    // colonne, 0 si inconnue
+	Message string // SYNTAX ERROR, TYPE MISMATCH...
+	Hint    string // correction proposée, éventuellement vide
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if e.Hint != "" {
+		msg += " (" + e.Hint + ")"
+	}
+	if e.Column > 0 {
+		return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, msg)
+	}
+	return fmt.Sprintf("%d: %s", e.Line, msg)
+}
+
+// ErrorList est la liste des erreurs de syntaxe d'un programme
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	msgs := make([]string, len(l))
+	for k, e := range l {
+		msgs[k] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// newErrorList situe les erreurs dans le fichier source
+func newErrorList(prog *parser.Program, errs []*errors.Error) ErrorList {
+	var list ErrorList
+	for _, d := range diag.FromErrors("", prog, errs) {
+		list = append(list, &Error{Line: d.Line, Column: d.Column, Message: d.Message, Hint: d.Hint})
+	}
+	return list
+}
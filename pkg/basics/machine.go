@@ -0,0 +1,94 @@
+package basics
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"basics/internal/errors"
+	"basics/internal/interpreter"
+	"basics/internal/machines/tty"
+	"basics/internal/runtime"
+	"basics/internal/video"
+)
+
+// Machine exécute un programme compilé. Chaque Run repart de variables
+// vides ; une machine n'exécute pas deux Run à la fois.
+type Machine struct {
+	prog   *Program
+	config config
+	screen *screen // écran du dernier Run (WithScreenSize)
+}
+
+// NewMachine prépare l'exécution d'un programme. Les options complètent
+// celles passées à Compile.
+func NewMachine(prog *Program, opts ...Option) *Machine {
+	return &Machine{prog: prog, config: prog.config.with(opts)}
+}
+
+// Run exécute le programme jusqu'à sa fin. Il retourne nil si le programme
+// se termine, une cause d'arrêt (ErrBreak quand ctx est annulé,
+// ErrTimeLimit, ErrStepLimit, ErrEndOfInput) ou l'*Error qui l'a arrêté.
+// Les erreurs sont aussi affichées par le programme, comme sur la machine
+// d'origine.
+func (m *Machine) Run(ctx context.Context) error {
+	cfg := &m.config
+	in := newReader(cfg.in)
+
+	var dev video.Device
+	if cfg.cols > 0 && cfg.rows > 0 {
+		m.screen = newScreen(cfg.cols, cfg.rows, in)
+		dev = m.screen
+	} else {
+		out := cfg.out
+		if out == nil {
+			out = io.Discard
+		}
+		dev = tty.New(strings.NewReader(""), out)
+	}
+
+	rt := runtime.New(dev)
+	if m.screen == nil {
+		rt.Input = in
+	}
+	if cfg.shortNames {
+		rt.Env.SetNameSignificance(cfg.basic())
+	}
+
+	interp := interpreter.New(rt)
+	interp.SetMaxSteps(cfg.maxSteps)
+	for name, fn := range cfg.statements {
+		interp.DefineStatement(name, func(args []runtime.Value) error {
+			return fn(values(args))
+		})
+	}
+	for name, fn := range cfg.functions {
+		interp.DefineFunction(name, func(args []runtime.Value) (runtime.Value, error) {
+			val, err := fn(values(args))
+			return val.v, err
+		})
+	}
+
+	if cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.timeout)
+		defer cancel()
+	}
+
+	if err := interp.RunContext(ctx, m.prog.ast); err != nil {
+		return err
+	}
+	if e, ok := rt.Err().(*errors.Error); ok {
+		return newErrorList(m.prog.ast, []*errors.Error{e})[0]
+	}
+	return nil
+}
+
+// Screen retourne les lignes de l'écran après Run, sans les espaces de fin
+// de ligne ; nil sans WithScreenSize
+func (m *Machine) Screen() []string {
+	if m.screen == nil {
+		return nil
+	}
+	return m.screen.lines()
+}
@@ -0,0 +1,161 @@
+package basics
+
+import (
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"basics/internal/dialect"
+)
+
+// Dialect est le BASIC d'un programme
+type Dialect int
+
+const (
+	Applesoft  Dialect = iota // Apple II (par défaut)
+	Commodore                 // Commodore 64 BASIC V2
+	Locomotive                // Amstrad CPC 6128
+)
+
+// StatementFunc implémente une instruction ajoutée au langage ; elle
+// reçoit ses arguments évalués
+type StatementFunc func(args []Value) error
+
+// FunctionFunc implémente une fonction ajoutée au langage
+type FunctionFunc func(args []Value) (Value, error)
+
+// Option configure Compile et NewMachine
+type Option func(*config)
+
+type config struct {
+	dialect    Dialect
+	crunched   bool
+	lowercase  bool
+	shortNames bool
+
+	in   io.Reader
+	out  io.Writer
+	cols int
+	rows int
+
+	maxSteps int
+	timeout  time.Duration
+
+	statements map[string]StatementFunc
+	functions  map[string]FunctionFunc
+}
+
+func newConfig(opts []Option) config {
+	return config{}.with(opts)
+}
+
+// with retourne une copie de la configuration modifiée par les options
+func (c config) with(opts []Option) config {
+	c.statements = maps.Clone(c.statements)
+	c.functions = maps.Clone(c.functions)
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// basic retourne le dialecte interne
+func (c *config) basic() *dialect.Dialect {
+	switch c.dialect {
+	case Commodore:
+		return dialect.CommodoreV2
+	case Locomotive:
+		return dialect.Locomotive
+	default:
+		return dialect.Applesoft
+	}
+}
+
+// syntax retourne le dialecte du lexer et du parser
+func (c *config) syntax() *dialect.Dialect {
+	d := c.basic()
+	if c.crunched {
+		d = d.WithCrunched()
+	}
+	if c.lowercase {
+		d = d.WithLowercase()
+	}
+	return d
+}
+
+// names retourne les noms triés d'instructions ou de fonctions ajoutées
+func names[F any](m map[string]F) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// WithDialect choisit le BASIC du programme
+func WithDialect(d Dialect) Option {
+	return func(c *config) { c.dialect = d }
+}
+
+// WithCrunched reconnaît les mots-clés sans espaces (10FORI=1TO10)
+func WithCrunched() Option {
+	return func(c *config) { c.crunched = true }
+}
+
+// WithLowercase reconnaît les mots-clés en minuscules (print)
+func WithLowercase() Option {
+	return func(c *config) { c.lowercase = true }
+}
+
+// WithShortNames ne garde que les caractères significatifs des noms de
+// variables pour le dialecte (2 pour l'Apple II et le Commodore 64)
+func WithShortNames() Option {
+	return func(c *config) { c.shortNames = true }
+}
+
+// WithInput fournit les saisies de INPUT (une ligne par saisie) et de GET
+func WithInput(r io.Reader) Option {
+	return func(c *config) { c.in = r }
+}
+
+// WithOutput reçoit la sortie du programme (sans WithScreenSize)
+func WithOutput(w io.Writer) Option {
+	return func(c *config) { c.out = w }
+}
+
+// WithScreenSize affiche le programme sur un écran texte de cols colonnes
+// et rows lignes, lu par Machine.Screen : HOME, HTAB et VTAB y placent le
+// curseur et les saisies y sont affichées
+func WithScreenSize(cols, rows int) Option {
+	return func(c *config) { c.cols, c.rows = cols, rows }
+}
+
+// WithMaxSteps arrête le programme après n instructions (ErrStepLimit)
+func WithMaxSteps(n int) Option {
+	return func(c *config) { c.maxSteps = n }
+}
+
+// WithTimeout arrête le programme après la durée d (ErrTimeLimit)
+func WithTimeout(d time.Duration) Option {
+	return func(c *config) { c.timeout = d }
+}
+
+// WithStatement ajoute l'instruction NAME [arg, ...] au langage. Elle doit
+// être déclarée à Compile ; NewMachine peut en changer l'implémentation.
+func WithStatement(name string, fn StatementFunc) Option {
+	return func(c *config) {
+		if c.statements == nil {
+			c.statements = map[string]StatementFunc{}
+		}
+		c.statements[strings.ToUpper(name)] = fn
+	}
+}
+
+// WithFunction ajoute la fonction NAME(arg, ...) au langage. Elle doit être
+// déclarée à Compile ; NewMachine peut en changer l'implémentation.
+func WithFunction(name string, fn FunctionFunc) Option {
+	return func(c *config) {
+		if c.functions == nil {
+			c.functions = map[string]FunctionFunc{}
+		}
+		c.functions[strings.ToUpper(name)] = fn
+	}
+}
@@ -0,0 +1,81 @@
+package basics
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"basics/internal/video/text"
+)
+
+// reader lit les saisies de INPUT et GET
+type reader struct {
+	in *bufio.Reader
+}
+
+func newReader(r io.Reader) *reader {
+	if r == nil {
+		r = strings.NewReader("")
+	}
+	return &reader{in: bufio.NewReader(r)}
+}
+
+func (r *reader) ReadLine() (string, error) {
+	line, err := r.in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (r *reader) GetChar() (rune, error) {
+	ch, _, err := r.in.ReadRune()
+	return ch, err
+}
+
+// screen est l'écran texte d'une machine (WithScreenSize). Les saisies de
+// INPUT y sont affichées comme si elles étaient tapées au clavier.
+type screen struct {
+	mode *text.TextMode
+	in   *reader
+}
+
+func newScreen(cols, rows int, in *reader) *screen {
+	return &screen{mode: text.NewTextMode(nil, cols, rows, 1, 1, 0, 0), in: in}
+}
+
+func (s *screen) Clear()                 { s.mode.Home() }
+func (s *screen) PrintChar(r rune)       { s.mode.PutChar(r) }
+func (s *screen) PrintString(str string) { s.mode.Print(str) }
+func (s *screen) SetCursorX(x int)       { s.mode.HTab(x) }
+func (s *screen) SetCursorY(y int)       { s.mode.VTab(y) }
+func (s *screen) Plot(x, y int)          {}
+func (s *screen) SetOutput(w io.Writer)  {}
+func (s *screen) DisableKeyboard()       {}
+func (s *screen) Render()                {}
+
+func (s *screen) ReadLine() (string, error) {
+	line, err := s.in.ReadLine()
+	if err == nil {
+		s.mode.Print(line + "\n")
+	}
+	return line, err
+}
+
+func (s *screen) GetChar() (rune, error) {
+	return s.in.GetChar()
+}
+
+// lines retourne le contenu de l'écran, sans les espaces de fin de ligne
+func (s *screen) lines() []string {
+	b := s.mode.Buffer
+	lines := make([]string, b.Rows)
+	for y := range lines {
+		row := make([]rune, b.Cols)
+		for x := range row {
+			row[x] = b.CellAt(x, y).Glyph
+		}
+		lines[y] = strings.TrimRight(string(row), " ")
+	}
+	return lines
+}
@@ -0,0 +1,51 @@
+package basics
+
+import (
+	"strconv"
+
+	"basics/internal/runtime"
+)
+
+// Value est une valeur BASIC : un nombre ou une chaîne. La valeur nulle
+// est le nombre 0.
+type Value struct {
+	v runtime.Value
+}
+
+// Number retourne un nombre BASIC
+func Number(f float64) Value {
+	return Value{runtime.NewReal(f)}
+}
+
+// String retourne une chaîne BASIC
+func String(s string) Value {
+	return Value{runtime.NewString(s)}
+}
+
+// IsString indique si la valeur est une chaîne
+func (v Value) IsString() bool {
+	return v.v.Type == runtime.STRING
+}
+
+// Float retourne la valeur d'un nombre ; 0 pour une chaîne
+func (v Value) Float() float64 {
+	f, _ := v.v.ToReal()
+	return f
+}
+
+// String retourne la chaîne, ou le nombre au format %g
+func (v Value) String() string {
+	if v.IsString() {
+		return v.v.Str
+	}
+	return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+}
+
+// values convertit les arguments d'un appel
+func values(args []runtime.Value) []Value {
+	out := make([]Value, len(args))
+	for k, a := range args {
+		out[k] = Value{a}
+	}
+	return out
+}
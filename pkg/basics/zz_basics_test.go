@@ -0,0 +1,141 @@
+package basics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"basics/testutils"
+)
+
+// run compile et exécute un programme ; il retourne sa sortie
+func run(t *testing.T, src string, opts ...Option) (string, error) {
+	t.Helper()
+
+	prog, err := Compile(src, opts...)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	var out strings.Builder
+	err = NewMachine(prog, append(opts, WithOutput(&out))...).Run(context.Background())
+	return out.String(), err
+}
+
+func TestRun_Output(t *testing.T) {
+	out, err := run(t, "10 A = 2\n20 PRINT \"A=\";A * 3\n")
+	testutils.Equal(t, "error", err, nil)
+	testutils.Equal(t, "output", out, "A=6\n")
+}
+
+func TestCompile_SyntaxErrors(t *testing.T) {
+	_, err := Compile("10 PRINT 1\n20 PRNT 2\n")
+
+	var list ErrorList
+	testutils.True(t, "error list", errors.As(err, &list))
+	testutils.Equal(t, "errors", len(list), 1)
+	testutils.Equal(t, "line", list[0].Line, 2)
+	testutils.Equal(t, "column", list[0].Column, 4)
+	testutils.Equal(t, "hint", list[0].Hint, "did you mean PRINT?")
+}
+
+func TestCompile_InvalidToken(t *testing.T) {
+	_, err := Compile("10 PRINT 1\n20 PRINT 1 @ 2\n")
+
+	var list ErrorList
+	testutils.True(t, "error list", errors.As(err, &list))
+	testutils.Equal(t, "errors", len(list), 1)
+	testutils.Equal(t, "line", list[0].Line, 2)
+	testutils.Equal(t, "column", list[0].Column, 12)
+	testutils.Equal(t, "message", list[0].Message, "INVALID TOKEN (@)")
+}
+
+func TestCompile_NoLogs(t *testing.T) {
+	var buf bytes.Buffer
+	old := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer slog.SetDefault(old)
+
+	_, err := run(t, "10 PRINT 1\n")
+	testutils.Equal(t, "error", err, nil)
+	testutils.Equal(t, "logs", buf.String(), "")
+}
+
+func TestRun_Input(t *testing.T) {
+	out, err := run(t, "10 INPUT A\n20 INPUT B$\n30 PRINT A + 1;B$\n", WithInput(strings.NewReader("41\nOK\n")))
+	testutils.Equal(t, "error", err, nil)
+	testutils.Equal(t, "output", out, "? ? 42OK\n")
+
+	_, err = run(t, "10 INPUT A\n")
+	testutils.Equal(t, "end of input", err, ErrEndOfInput)
+}
+
+func TestRun_Limits(t *testing.T) {
+	_, err := run(t, "10 GOTO 10\n", WithMaxSteps(100))
+	testutils.Equal(t, "steps", err, ErrStepLimit)
+
+	_, err = run(t, "10 GOTO 10\n", WithTimeout(20*time.Millisecond))
+	testutils.Equal(t, "timeout", err, ErrTimeLimit)
+
+	prog, _ := Compile("10 GOTO 10\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	testutils.Equal(t, "cancel", NewMachine(prog).Run(ctx), ErrBreak)
+}
+
+func TestRun_RuntimeError(t *testing.T) {
+	out, err := run(t, "10 PRINT 1\n20 A = B\n")
+
+	var e *Error
+	testutils.True(t, "error", errors.As(err, &e))
+	testutils.Equal(t, "message", e.Message, "UNDEFINED VARIABLE B")
+	testutils.Equal(t, "line", e.Line, 2)
+	testutils.True(t, "printed", strings.Contains(out, "UNDEFINED VARIABLE B"))
+}
+
+func TestRun_Extensions(t *testing.T) {
+	var beeps []string
+	opts := []Option{
+		WithStatement("BEEP", func(args []Value) error {
+			beeps = append(beeps, fmt.Sprint(args))
+			return nil
+		}),
+		WithFunction("TWICE", func(args []Value) (Value, error) {
+			if len(args) != 1 || args[0].IsString() {
+				return Value{}, errors.New("TWICE EXPECTS A NUMBER")
+			}
+			return Number(2 * args[0].Float()), nil
+		}),
+		WithFunction("HELLO", func(args []Value) (Value, error) {
+			return String("HI"), nil
+		}),
+	}
+
+	out, err := run(t, "10 BEEP : BEEP 440, \"LA\"\n20 PRINT TWICE(3) + 1;HELLO()\n", opts...)
+	testutils.Equal(t, "error", err, nil)
+	testutils.Equal(t, "output", out, "7HI\n")
+	testutils.Equal(t, "beeps", strings.Join(beeps, " "), "[] [440 LA]")
+
+	_, err = run(t, "10 PRINT TWICE(\"A\")\n", opts...)
+	var e *Error
+	testutils.True(t, "error", errors.As(err, &e))
+	testutils.Equal(t, "message", e.Message, "TWICE EXPECTS A NUMBER")
+
+	// sans déclaration, BEEP est une erreur de syntaxe
+	_, err = Compile("10 BEEP\n")
+	testutils.NotEqual(t, "undeclared", err, nil)
+}
+
+func TestMachine_Screen(t *testing.T) {
+	prog, err := Compile("10 HOME\n20 VTAB 2 : HTAB 3 : PRINT \"HI\"\n30 INPUT \"NAME\";N$\n40 PRINT \"HELLO \";N$\n")
+	testutils.Equal(t, "compile", err, nil)
+
+	m := NewMachine(prog, WithScreenSize(20, 5), WithInput(strings.NewReader("BOB\n")))
+	testutils.Equal(t, "run", m.Run(context.Background()), nil)
+
+	testutils.Equal(t, "screen", strings.Join(m.Screen(), "|"), "|  HI|NAMEBOB|HELLO BOB|")
+}